@@ -0,0 +1,70 @@
+package http2curl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Analysis is a breakdown of the size and composition of a request, useful
+// to understand 431 (headers too large) and 413 (payload too large) errors.
+type Analysis struct {
+	// HeaderBytes is the size of all header lines, as sent on the wire.
+	HeaderBytes int
+	// BodyBytes is the size of the request body.
+	BodyBytes int
+	// CookieCount is the number of cookies sent with the request.
+	CookieCount int
+	// LargestHeader is the name of the header with the largest lines.
+	LargestHeader string
+	// LargestHeaderBytes is the size of the lines of LargestHeader.
+	LargestHeaderBytes int
+}
+
+// Analyze returns the size and composition breakdown of req. The body is
+// read as done by Command, see GetCurlCommandWithOptions.
+func Analyze(req *http.Request) (*Analysis, error) {
+	return analyze(context.Background(), req)
+}
+
+func analyze(ctx context.Context, req *http.Request) (*Analysis, error) {
+	body, err := readBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{
+		BodyBytes:   len(body),
+		CookieCount: len(req.Cookies()),
+	}
+	for name, values := range req.Header {
+		size := 0
+		for _, v := range values {
+			// "Name: value\r\n"
+			size += len(name) + len(v) + 4
+		}
+		a.HeaderBytes += size
+		if size > a.LargestHeaderBytes || (size == a.LargestHeaderBytes && name < a.LargestHeader) {
+			a.LargestHeader, a.LargestHeaderBytes = name, size
+		}
+	}
+	return a, nil
+}
+
+// WithAnalysis prepends the Analysis of the request to the command as a
+// block of shell comments.
+func WithAnalysis() Option {
+	return func(o *Options) { o.analyze = true }
+}
+
+// lines returns the analysis as comment lines, with the size format of o.
+func (a *Analysis) lines(o *Options) []string {
+	lines := []string{
+		fmt.Sprintf("request size: %s (headers %s, body %s)", o.sizeString(a.HeaderBytes+a.BodyBytes), o.sizeString(a.HeaderBytes), o.sizeString(a.BodyBytes)),
+		fmt.Sprintf("cookies: %d", a.CookieCount),
+	}
+	if a.LargestHeader != "" {
+		lines = append(lines, fmt.Sprintf("largest header: %s (%s)", a.LargestHeader, o.sizeString(a.LargestHeaderBytes)))
+	}
+	return lines
+}
@@ -0,0 +1,33 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleAnalyze() {
+	req, _ := http.NewRequest("POST", "http://example.com/upload", strings.NewReader("0123456789"))
+	req.Header.Set("Cookie", "a=1; b=2; c=3")
+	req.Header.Set("Content-Type", "text/plain")
+
+	analysis, _ := Analyze(req)
+	fmt.Printf("%+v\n", *analysis)
+
+	// Output:
+	// {HeaderBytes:49 BodyBytes:10 CookieCount:3 LargestHeader:Content-Type LargestHeaderBytes:26}
+}
+
+func ExampleWithAnalysis() {
+	req, _ := http.NewRequest("POST", "http://example.com/upload", strings.NewReader("0123456789"))
+	req.Header.Set("Cookie", "a=1; b=2; c=3")
+
+	command, _ := Command(req, nil, WithAnalysis())
+	fmt.Println(command)
+
+	// Output:
+	// # request size: 33 bytes (headers 23 bytes, body 10 bytes)
+	// # cookies: 3
+	// # largest header: Cookie (23 bytes)
+	// curl -X 'POST' -d '0123456789' -H 'Cookie: a=1; b=2; c=3' 'http://example.com/upload'
+}
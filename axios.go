@@ -0,0 +1,189 @@
+package http2curl
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// GetAxiosSnippet returns a Node.js axios() call sending req. Basic
+// credentials, from the Authorization header or the URL, become the auth
+// field, and query parameters the params field when no name repeats,
+// since axios would add brackets to repeated names. JSON bodies are
+// written as object literals, bodies that are not valid UTF-8 are decoded
+// from base64. It is generated from the same arguments as the curl
+// command, so options apply alike; placeholders become references to
+// JavaScript variables of the same name, and curl flags without an
+// equivalent are reported to the loss callback.
+func GetAxiosSnippet(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		b        strings.Builder
+		method   string
+		target   string
+		headers  []string
+		cookies  []string
+		body     *arg
+		file     string
+		insecure bool
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			b.WriteString("// " + a.value + "\n")
+			continue
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by axios")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			headers = append(headers, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			file = value.value[1:]
+		case dataFlags[a.value]:
+			body = &args[i]
+		case a.value == "-k" || a.value == "--insecure":
+			insecure = true
+		case a.value == "--compressed", a.value == "-f", a.value == "--fail", a.value == "--fail-with-body":
+			// axios decompresses responses and fails on HTTP errors
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by axios")
+		}
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, "Cookie: "+strings.Join(cookies, "; "))
+	}
+	if method == "" {
+		method = http.MethodGet
+		if body != nil || file != "" {
+			method = http.MethodPost
+		}
+	}
+
+	// basic credentials of the URL or of the Authorization header
+	var username, password string
+	var hasAuth bool
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		hasAuth = true
+		u.User = nil
+	}
+	for i, h := range headers {
+		name, v, _ := strings.Cut(h, ":")
+		scheme, credentials, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(name, "Authorization") || !strings.EqualFold(scheme, "Basic") {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil || !utf8.Valid(decoded) || !strings.Contains(string(decoded), ":") {
+			continue
+		}
+		username, password, _ = strings.Cut(string(decoded), ":")
+		hasAuth = true
+		headers = append(headers[:i:i], headers[i+1:]...)
+		break
+	}
+
+	var params [][2]string
+	if pairs, ok := splitQuery(u.RawQuery); ok && uniqueKeys(pairs) {
+		params = pairs
+		u.RawQuery, u.ForceQuery = "", false
+	}
+
+	b.WriteString("axios({\n")
+	b.WriteString("  method: " + jsonString(strings.ToLower(method)) + ",\n")
+	b.WriteString("  url: " + o.jsString(u.String()) + ",\n")
+	if len(params) > 0 {
+		b.WriteString("  params: {\n")
+		for i, kv := range params {
+			b.WriteString("    " + jsonString(kv[0]) + ": " + o.jsString(kv[1]))
+			if i < len(params)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("  },\n")
+	}
+	if len(headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for i, h := range headers {
+			name, v, _ := strings.Cut(h, ":")
+			b.WriteString("    " + jsonString(name) + ": " + o.jsString(strings.TrimLeft(v, " ")))
+			if i < len(headers)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("  },\n")
+	}
+	if hasAuth {
+		b.WriteString("  auth: {\n")
+		b.WriteString("    username: " + o.jsString(username) + ",\n")
+		b.WriteString("    password: " + o.jsString(password) + "\n")
+		b.WriteString("  },\n")
+	}
+	switch {
+	case body != nil && !utf8.ValidString(body.value):
+		b.WriteString("  data: Buffer.from(" + jsonString(base64.StdEncoding.EncodeToString([]byte(body.value))) + `, "base64"),` + "\n")
+	case body != nil:
+		if literal, ok := o.jsLiteral(body.value); ok {
+			b.WriteString("  data: " + literal + ",\n")
+		} else {
+			b.WriteString("  data: " + o.jsString(body.value) + ",\n")
+		}
+	case file != "":
+		b.WriteString(`  data: require("fs").readFileSync(` + jsonString(file) + "),\n")
+	}
+	if insecure {
+		b.WriteString(`  httpsAgent: new (require("https").Agent)({ rejectUnauthorized: false }),` + "\n")
+	}
+	// no trailing comma after the last field
+	snippet := strings.TrimSuffix(b.String(), ",\n") + "\n});"
+	return snippet, nil
+}
+
+// uniqueKeys reports whether no key repeats in pairs.
+func uniqueKeys(pairs [][2]string) bool {
+	seen := map[string]bool{}
+	for _, kv := range pairs {
+		if seen[kv[0]] {
+			return false
+		}
+		seen[kv[0]] = true
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// MultiError records the failures of a batch operation while the other
+// requests of the batch are still processed.
+type MultiError struct {
+	// Errors maps the index of each failed request to its error.
+	Errors map[int]error
+}
+
+func (m *MultiError) Error() string {
+	var msgs []string
+	for _, i := range m.Indexes() {
+		msgs = append(msgs, fmt.Sprintf("request %d: %v", i, m.Errors[i]))
+	}
+	return fmt.Sprintf("%d request(s) failed: %s", len(m.Errors), strings.Join(msgs, "; "))
+}
+
+// Indexes returns the indexes of the failed requests, in ascending order.
+func (m *MultiError) Indexes() []int {
+	indexes := make([]int, 0, len(m.Errors))
+	for i := range m.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// add records err for the request at index i.
+func (m *MultiError) add(i int, err error) {
+	if m.Errors == nil {
+		m.Errors = map[int]error{}
+	}
+	m.Errors[i] = err
+}
+
+// errOrNil returns m as an error, or nil when nothing failed.
+func (m *MultiError) errOrNil() error {
+	if len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+// GetCurlCommands returns the CurlCommand of each request of reqs. A request
+// that fails leaves a nil entry and is reported in the returned *MultiError;
+// the other commands are still generated.
+func GetCurlCommands(reqs []*http.Request, opts ...Option) ([]*CurlCommand, error) {
+	var errs MultiError
+	commands := make([]*CurlCommand, len(reqs))
+	for i, req := range reqs {
+		command, err := Command(req, nil, opts...)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		commands[i] = command
+	}
+	return commands, errs.errOrNil()
+}
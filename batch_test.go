@@ -0,0 +1,28 @@
+package http2curl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func ExampleGetCurlCommands() {
+	ok, _ := http.NewRequest("GET", "http://example.com/a", nil)
+	bad, _ := http.NewRequest("POST", "http://example.com/b", failingReader{})
+
+	commands, err := GetCurlCommands([]*http.Request{ok, bad, ok})
+	for i, command := range commands {
+		fmt.Println(i, command)
+	}
+	fmt.Println(err)
+
+	// Output:
+	// 0 curl -X 'GET' 'http://example.com/a'
+	// 1 <nil>
+	// 2 curl -X 'GET' 'http://example.com/a'
+	// 1 request(s) failed: request 1: connection reset
+}
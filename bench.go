@@ -0,0 +1,213 @@
+package http2curl
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Benchmark parameters, left to the environment of the commands generated
+// by GetHeyCommand and GetABCommand.
+const (
+	benchRequestsVar    = "REQUESTS"
+	benchConcurrencyVar = "CONCURRENCY"
+)
+
+// benchRequest is a request as sent by a benchmark tool.
+type benchRequest struct {
+	notes       argList
+	method      string
+	target      string
+	headers     []string
+	cookies     []string
+	contentType string
+	body        arg
+	hasBody     bool
+	redirect    bool
+}
+
+// benchRequest reads the request of args. Flags with no equivalent in
+// tool are reported to the loss callback and in warning comments.
+func (o *Options) benchRequest(args argList, tool string) benchRequest {
+	var r benchRequest
+	warn := func(what, reason string) {
+		o.loss(LossDropped, what, reason)
+		r.notes.comment("warning: " + what + " dropped, " + reason)
+	}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argPrompt:
+			r.notes = append(r.notes, a)
+			continue
+		case argURL:
+			r.target = a.value
+			continue
+		case argPipe:
+			warn("pipeline", "not supported by "+tool)
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			r.method = value.value
+		case a.value == "--head":
+			r.method = http.MethodHead
+		case a.value == "-H":
+			name, v, _ := strings.Cut(value.value, ":")
+			if strings.EqualFold(name, "Content-Type") {
+				r.contentType = strings.TrimLeft(v, " ")
+				continue
+			}
+			r.headers = append(r.headers, value.value)
+		case a.value == "--cookie":
+			r.cookies = append(r.cookies, value.value)
+		case a.value == "-L" || a.value == "--location":
+			r.redirect = true
+		case a.value == "-k" || a.value == "--insecure" || a.value == "-s" || a.value == "-sS":
+			// benchmark tools do not verify certificates nor print progress
+		case a.value == "--url":
+		case dataFlags[a.value]:
+			r.body, r.hasBody = value, true
+		default:
+			warn("flag "+a.value, "not supported by "+tool)
+		}
+	}
+	if r.method == "" {
+		r.method = http.MethodGet
+		if r.hasBody {
+			r.method = http.MethodPost
+		}
+	}
+	return r
+}
+
+// GetHeyCommand returns a hey command load testing with req. The number of
+// requests and the concurrency are references to the REQUESTS and
+// CONCURRENCY environment variables. Redirects are only followed when -L
+// is among the flags, as curl does. It is generated from the same
+// arguments as the curl command, so options apply alike; curl flags hey
+// has no equivalent for are reported to the loss callback and in warning
+// comments above the command.
+func GetHeyCommand(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	r := o.benchRequest(args, "hey")
+
+	var hey argList
+	if r.hasBody && r.contentType == "" {
+		r.notes.comment("warning: no Content-Type, hey sends text/html")
+	}
+	hey = append(hey, r.notes...)
+	hey.add(argProgram, "hey")
+	hey.flag("-n", argRaw, o.shell.variable(benchRequestsVar))
+	hey.flag("-c", argRaw, o.shell.variable(benchConcurrencyVar))
+	hey.flag("-m", argMethod, r.method)
+	for _, h := range r.headers {
+		hey.flag("-H", argHeader, h)
+	}
+	if len(r.cookies) > 0 {
+		hey.flag("-H", argHeader, "Cookie: "+strings.Join(r.cookies, "; "))
+	}
+	if r.contentType != "" {
+		hey.flag("-T", argValue, r.contentType)
+	}
+	switch {
+	case r.hasBody && r.body.kind == argValue && strings.HasPrefix(r.body.value, "@"):
+		hey.flag("-D", argValue, r.body.value[1:])
+	case r.hasBody:
+		hey.flag("-d", argBody, r.body.value)
+	}
+	if !r.redirect {
+		hey.add(argFlag, "-disable-redirects")
+	}
+	hey.add(argURL, r.target)
+	command := o.render(hey)
+	return command.String(), nil
+}
+
+// GetABCommand returns an ab (ApacheBench) command load testing with req.
+// The number of requests and the concurrency are references to the
+// REQUESTS and CONCURRENCY environment variables. As ab only reads bodies
+// from files, the body is given to writeBody, which stores it and returns
+// the path to use, see VegetaBodyDir. It is generated from the same
+// arguments as the curl command, so options apply alike; what ab cannot
+// do, such as following redirects, is reported to the loss callback and
+// in warning comments above the command.
+func GetABCommand(req *http.Request, writeBody func(body []byte) (path string, err error), opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	r := o.benchRequest(args, "ab")
+	if r.redirect {
+		o.loss(LossDropped, "flag -L", "ab does not follow redirects")
+		r.notes.comment("warning: flag -L dropped, ab does not follow redirects")
+	}
+
+	var ab argList
+	ab = append(ab, r.notes...)
+	ab.add(argProgram, "ab")
+	ab.flag("-n", argRaw, o.shell.variable(benchRequestsVar))
+	ab.flag("-c", argRaw, o.shell.variable(benchConcurrencyVar))
+	for _, h := range r.headers {
+		ab.flag("-H", argHeader, h)
+	}
+	if len(r.cookies) > 0 {
+		ab.flag("-C", argValue, strings.Join(r.cookies, "; "))
+	}
+	if r.hasBody {
+		path := strings.TrimPrefix(r.body.value, "@")
+		if r.body.kind != argValue || !strings.HasPrefix(r.body.value, "@") {
+			if path, err = writeBody([]byte(r.body.value)); err != nil {
+				return "", err
+			}
+		}
+		// -p posts and -u puts the file, -m changes the method
+		if r.method == http.MethodPut {
+			ab.flag("-u", argValue, path)
+		} else {
+			if r.method != http.MethodPost {
+				ab.flag("-m", argMethod, r.method)
+			}
+			ab.flag("-p", argValue, path)
+		}
+		if r.contentType != "" {
+			ab.flag("-T", argValue, r.contentType)
+		}
+	} else {
+		switch r.method {
+		case http.MethodGet:
+		case http.MethodHead:
+			ab.add(argFlag, "-i")
+		default:
+			ab.flag("-m", argMethod, r.method)
+		}
+		if r.contentType != "" {
+			ab.flag("-H", argHeader, "Content-Type: "+r.contentType)
+		}
+	}
+	target := r.target
+	if u, err := req.URL.Parse(target); err == nil && u.Path == "" && u.Opaque == "" {
+		// ab requires a path
+		target = strings.Replace(target, "?", "/?", 1)
+		if !strings.Contains(target, "/?") {
+			target += "/"
+		}
+	}
+	ab.add(argURL, target)
+	command := o.render(ab)
+	return command.String(), nil
+}
@@ -0,0 +1,36 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleGetHeyCommand() {
+	req, _ := http.NewRequest("PATCH", "https://api.example.com/items/1", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	command, _ := GetHeyCommand(req)
+	fmt.Println(command)
+
+	// Output:
+	// hey -n "${REQUESTS}" -c "${CONCURRENCY}" -m 'PATCH' -H 'Accept: application/json' -T 'application/json' -d '{"name":"gopher"}' -disable-redirects 'https://api.example.com/items/1'
+}
+
+func ExampleGetABCommand() {
+	req, _ := http.NewRequest("POST", "http://api.example.com", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	writeBody := func(body []byte) (string, error) { return "body.json", nil }
+	command, _ := GetABCommand(req, writeBody, WithLossCallback(func(e LossEvent) {
+		fmt.Println("loss:", e)
+	}), WithCompressedFlag())
+	fmt.Println(command)
+
+	// Output:
+	// loss: flag --compressed dropped: not supported by ab
+	// # warning: flag --compressed dropped, not supported by ab
+	// ab -n "${REQUESTS}" -c "${CONCURRENCY}" -H 'Accept-Encoding: gzip' -p 'body.json' -T 'application/json' 'http://api.example.com/'
+}
@@ -0,0 +1,197 @@
+package http2curl
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BundleConfig configures ExportBundle.
+type BundleConfig struct {
+	// RedactHeaders are the headers whose values are replaced by REDACTED.
+	// When nil, the headers commonly carrying credentials are redacted:
+	// Authorization, Cookie, X-Api-Key and the like.
+	RedactHeaders []string `json:"redact_headers"`
+	// RedactParams are the query parameters whose values are replaced by
+	// REDACTED, compared regardless of case. When nil, the parameters
+	// commonly carrying credentials are redacted: access_token, api_key,
+	// the signatures of pre-signed URLs and the like.
+	RedactParams []string `json:"redact_params"`
+	// Options are used to generate the commands.
+	Options []Option `json:"-"`
+	// SigningKey, when set, is used to sign the manifest with HMAC-SHA256.
+	// The signature is stored as manifest.sig, see VerifyBundle.
+	SigningKey []byte `json:"-"`
+}
+
+// BundleManifest describes the files of a bundle. It is stored in the
+// bundle as manifest.json.
+type BundleManifest struct {
+	Created time.Time    `json:"created"`
+	Files   []BundleFile `json:"files"`
+}
+
+// BundleFile is a file of a bundle.
+type BundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ExportBundle writes a zip archive holding what is needed to reproduce
+// reqs without sharing credentials:
+//
+//	commands.sh     the redacted curl commands, to run from the bundle
+//	bodies/NNN.bin  the request bodies, referenced by the commands
+//	redaction.json  the redaction config used
+//	manifest.json   the size and SHA-256 of every other file
+//	manifest.sig    the signature of the manifest, with a SigningKey
+//
+// Header values, query parameters and URL passwords are redacted, bodies
+// are kept as is.
+// Requests that fail are left out of the bundle and reported in a
+// *MultiError.
+func ExportBundle(w io.Writer, reqs []*http.Request, config BundleConfig) error {
+	redacted := config.RedactHeaders
+	if redacted == nil {
+		redacted = append(redacted, sensitiveHeaders...)
+	}
+	redacted = canonicalHeaders(redacted)
+	params := config.RedactParams
+	if params == nil {
+		params = append(params, sensitiveParams...)
+	}
+
+	var errs MultiError
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\ncd \"$(dirname \"$0\")\" || exit 1\n")
+	type bodyFile struct {
+		name string
+		data []byte
+	}
+	var bodies []bodyFile
+	for i, req := range reqs {
+		if req == nil || req.URL == nil {
+			errs.add(i, fmt.Errorf("http2curl: invalid request, req.URL is nil"))
+			continue
+		}
+		body, err := readBody(req.Context(), req)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		opts := append([]Option(nil), config.Options...)
+		if len(redacted) > 0 {
+			opts = append(opts, WithRedactedHeaders(redacted...))
+		}
+		if len(params) > 0 {
+			opts = append(opts, withRedactedParams(params...))
+		}
+		name := fmt.Sprintf("bodies/%03d.bin", i)
+		if len(body) > 0 {
+			opts = append(opts, bodyFromFile(name))
+		}
+		command, err := Command(req, nil, opts...)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		if len(body) > 0 {
+			bodies = append(bodies, bodyFile{name: name, data: body})
+		}
+		fmt.Fprintf(&script, "\n# request %d\n%s\n", i, command)
+	}
+
+	config.RedactHeaders = redacted
+	config.RedactParams = params
+	redaction, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	manifest := BundleManifest{Created: newOptions(config.Options).now().UTC().Truncate(time.Second)}
+	add := func(name string, data []byte, executable bool) error {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: manifest.Created}
+		header.SetMode(0644)
+		if executable {
+			header.SetMode(0755)
+		}
+		f, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, BundleFile{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	}
+
+	if err := add("commands.sh", []byte(script.String()), true); err != nil {
+		return err
+	}
+	for _, b := range bodies {
+		if err := add(b.name, b.data, false); err != nil {
+			return err
+		}
+	}
+	if err := add("redaction.json", append(redaction, '\n'), false); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err := f.Write(b); err != nil {
+		return err
+	}
+	if config.SigningKey != nil {
+		f, err := zw.Create("manifest.sig")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, hmacHex(config.SigningKey, b)+"\n"); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return errs.errOrNil()
+}
+
+// bodyFromFile makes the command send the body from the file name rather
+// than inline.
+func bodyFromFile(name string) Option {
+	return func(o *Options) { o.bodyFile = name }
+}
+
+// canonicalHeaders returns the canonical, sorted and deduplicated names.
+func canonicalHeaders(names []string) []string {
+	seen := map[string]bool{}
+	canonical := []string{}
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		if !seen[name] {
+			seen[name] = true
+			canonical = append(canonical, name)
+		}
+	}
+	sort.Strings(canonical)
+	return canonical
+}
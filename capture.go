@@ -0,0 +1,127 @@
+package http2curl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Capture is the curl command of a request along with metadata about the
+// request, meant for audit logs.
+type Capture struct {
+	Command *CurlCommand
+	Method  string
+	URL     string
+	// BodySize is the size of the request body.
+	BodySize int
+	// BodySource tells whether the body was read with req.GetBody or
+	// replaced by a copy.
+	BodySource BodySource
+	// Parts describes the parts of multipart bodies, nil when the body
+	// cannot be read as such, which is reported to the loss callback.
+	Parts []MultipartPart
+	// Callers are the calls that issued the request, innermost first, when
+	// recorded by a Transport.
+	Callers []CallerFrame
+	// StatusCode is the status code of the response, when recorded by a
+	// Transport, zero otherwise.
+	StatusCode int
+	// Tags are the tags set with WithTag or ContextWithTag.
+	Tags map[string]string
+	// Time is when the request was sent, and Latency how long its
+	// response took, when recorded by a Transport or a Middleware, which
+	// only records Time.
+	Time    time.Time
+	Latency time.Duration
+	// Error is the error of the request when it failed before a
+	// response, such as a DNS or TLS failure, when recorded by a
+	// Transport.
+	Error string
+}
+
+// MultipartPart describes a part of a multipart body without its content,
+// so that what was uploaded is known without keeping the bytes.
+type MultipartPart struct {
+	FieldName   string `json:"field_name"`
+	FileName    string `json:"file_name,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// NewCapture returns the Capture of req, its command being generated with
+// opts. Its URL is redacted as in the command.
+func NewCapture(req *http.Request, opts ...Option) (*Capture, error) {
+	o := newOptions(opts)
+	command, err := Command(req, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	body, source, err := readBodySource(o.context(), req)
+	if err != nil {
+		return nil, err
+	}
+	// the command is there, a body not matching its Content-Type only
+	// leaves the parts out
+	parts, err := multipartParts(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		o.loss(LossDropped, "multipart parts", "invalid multipart body: "+err.Error())
+	}
+	return &Capture{
+		Command:    command,
+		Method:     req.Method,
+		URL:        o.redactedURL(req.URL),
+		BodySize:   len(body),
+		BodySource: source,
+		Parts:      parts,
+		Tags:       o.tagsFor(req.Context()),
+	}, nil
+}
+
+// MultipartParts returns the parts of the body of req when it is a
+// multipart body, nil otherwise. The body is read as done by Command, see
+// GetCurlCommandWithOptions.
+func MultipartParts(req *http.Request) ([]MultipartPart, error) {
+	body, err := readBody(req.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	return multipartParts(req.Header.Get("Content-Type"), body)
+}
+
+func multipartParts(contentType string, body []byte) ([]MultipartPart, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, nil
+	}
+
+	var parts []MultipartPart
+	r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		p, err := r.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		size, err := io.Copy(h, p)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, MultipartPart{
+			FieldName:   p.FormName(),
+			FileName:    p.FileName(),
+			ContentType: p.Header.Get("Content-Type"),
+			Size:        size,
+			SHA256:      hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package http2curl
+
+import "net/http"
+
+// WithHeaderCasing renders the given headers with the exact casing of
+// names, whatever the casing they have in the request. http.Header.Set
+// canonicalizes names, turning API_KEY into Api_key, which some servers
+// and reviewers care about. Headers assigned directly to the http.Header
+// map, as in req.Header["API_KEY"] = []string{"..."}, already keep their
+// casing.
+func WithHeaderCasing(names ...string) Option {
+	return func(o *Options) {
+		if o.headerCasing == nil {
+			o.headerCasing = map[string]string{}
+		}
+		for _, name := range names {
+			o.headerCasing[http.CanonicalHeaderKey(name)] = name
+		}
+	}
+}
+
+// headerName returns the name to render for the header name of a request.
+func (o *Options) headerName(name string) string {
+	if cased, ok := o.headerCasing[http.CanonicalHeaderKey(name)]; ok {
+		return cased
+	}
+	return name
+}
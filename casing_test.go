@@ -0,0 +1,22 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithHeaderCasing() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("API_KEY", "0123456789")
+	req.Header.Set("X-Request-ID", "42")
+
+	command, _ := Command(req, nil)
+	fmt.Println(command)
+
+	command, _ = Command(req, nil, WithHeaderCasing("API_KEY", "X-Request-ID"))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -H 'Api_key: 0123456789' -H 'X-Request-Id: 42' 'http://example.com/'
+	// curl -X 'GET' -H 'API_KEY: 0123456789' -H 'X-Request-ID: 42' 'http://example.com/'
+}
@@ -0,0 +1,253 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Generator renders a request, for instance as a curl command or a script.
+// PaginationScript is a Generator.
+type Generator func(req *http.Request, opts ...Option) (string, error)
+
+// CurlGenerator is the Generator of curl commands.
+func CurlGenerator(req *http.Request, opts ...Option) (string, error) {
+	command, err := Command(req, nil, opts...)
+	if err != nil {
+		return "", err
+	}
+	return command.String(), nil
+}
+
+// generators are the Generators known by name.
+var generators = map[string]Generator{
+	"curl":       CurlGenerator,
+	"wget":       GetWgetCommand,
+	"httpie":     GetHTTPieCommand,
+	"fetch":      GetFetchSnippet,
+	"axios":      GetAxiosSnippet,
+	"python":     GetPythonSnippet,
+	"powershell": InvokeRestMethod,
+	"hey":        GetHeyCommand,
+	"pagination": PaginationScript,
+}
+
+// LookupGenerator returns the Generator named name, one of GeneratorNames,
+// for instance to pick the target of a conversion from a flag.
+func LookupGenerator(name string) (Generator, bool) {
+	generate, ok := generators[name]
+	return generate, ok
+}
+
+// GeneratorNames returns the names of the Generators known by
+// LookupGenerator, sorted.
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CatalogEntry is a named request definition of a Catalog. In JSON, the
+// fields of the definition sit next to those of the entry:
+//
+//	{
+//	  "name": "users.create",
+//	  "version": "2",
+//	  "description": "Create a user",
+//	  "tags": ["users", "write"],
+//	  "method": "POST",
+//	  "url": "https://api.example.com/users"
+//	}
+type CatalogEntry struct {
+	Name        string   `yaml:"name" json:"name"`
+	Version     string   `yaml:"version" json:"version,omitempty"`
+	Description string   `yaml:"description" json:"description,omitempty"`
+	Tags        []string `yaml:"tags" json:"tags,omitempty"`
+	Definition  `yaml:",inline"`
+}
+
+// ID returns the name of the entry, followed by @version when it has one.
+func (e CatalogEntry) ID() string {
+	if e.Version == "" {
+		return e.Name
+	}
+	return e.Name + "@" + e.Version
+}
+
+// Catalog is a library of named request definitions, that can be searched
+// and rendered with any Generator. Several versions of an entry can be
+// kept, the last one added being the current one.
+type Catalog struct {
+	entries []CatalogEntry
+	// byID maps the IDs and names of the entries to their index, names
+	// map to their last version
+	byID map[string]int
+}
+
+// NewCatalog returns an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{byID: map[string]int{}}
+}
+
+// LoadCatalog reads the entries of a catalog in JSON: a list of entries,
+// or several entries one after the other. The yamldef package reads
+// catalogs in YAML.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	c := NewCatalog()
+	err := decodeDocuments(r, func(item json.RawMessage) error {
+		var entry CatalogEntry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return fmt.Errorf("http2curl: invalid catalog entry: %v", err)
+		}
+		return c.Add(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add adds entry to the catalog. Its name is required, and its name and
+// version must not already be used. The entries of a name are either all
+// versioned, or a single unversioned one.
+func (c *Catalog) Add(entry CatalogEntry) error {
+	if entry.Name == "" || strings.Contains(entry.Name, "@") {
+		return fmt.Errorf("http2curl: invalid catalog entry name %q", entry.Name)
+	}
+	if _, err := entry.Request(); err != nil {
+		return fmt.Errorf("%v (catalog entry %s)", err, entry.ID())
+	}
+	id := entry.ID()
+	if i, ok := c.byID[entry.Name]; ok {
+		// the versions of a name are all versioned, or it has a single
+		// unversioned entry
+		if (c.entries[i].Version == "") != (entry.Version == "") {
+			return fmt.Errorf("http2curl: catalog entry %s mixes versioned and unversioned entries", entry.Name)
+		}
+		if j, ok := c.byID[id]; ok && c.entries[j].ID() == id {
+			return fmt.Errorf("http2curl: duplicate catalog entry %s", id)
+		}
+	}
+	c.entries = append(c.entries, entry)
+	c.byID[id] = len(c.entries) - 1
+	c.byID[entry.Name] = len(c.entries) - 1
+	return nil
+}
+
+// Get returns the entry with the given ID, either name@version or a name
+// alone for its current version.
+func (c *Catalog) Get(id string) (CatalogEntry, bool) {
+	i, ok := c.byID[id]
+	if !ok {
+		return CatalogEntry{}, false
+	}
+	return c.entries[i], true
+}
+
+// List returns the current version of every entry, sorted by name.
+func (c *Catalog) List() []CatalogEntry {
+	var list []CatalogEntry
+	for i, entry := range c.entries {
+		if c.byID[entry.Name] == i {
+			list = append(list, entry)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Search returns the current entries matching every term of query, sorted
+// by name. A term of the form tag:name matches the entries with that tag,
+// other terms match the name, description or URL of the entries,
+// ignoring case.
+func (c *Catalog) Search(query string) []CatalogEntry {
+	var found []CatalogEntry
+	for _, entry := range c.List() {
+		if entry.matches(strings.Fields(query)) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+func (e CatalogEntry) matches(terms []string) bool {
+	text := strings.ToLower(e.Name + "\n" + e.Description + "\n" + e.URL)
+	for _, term := range terms {
+		if tag := strings.TrimPrefix(term, "tag:"); tag != term {
+			if !e.hasTag(tag) {
+				return false
+			}
+			continue
+		}
+		if !strings.Contains(text, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e CatalogEntry) hasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders the entry with the given ID with generate, or as a curl
+// command when generate is nil.
+func (c *Catalog) Render(id string, generate Generator, opts ...Option) (string, error) {
+	entry, ok := c.Get(id)
+	if !ok {
+		return "", fmt.Errorf("http2curl: no catalog entry %s", id)
+	}
+	req, err := entry.Request()
+	if err != nil {
+		return "", err
+	}
+	if generate == nil {
+		generate = CurlGenerator
+	}
+	return generate(req, opts...)
+}
+
+// CatalogEntry returns the catalog entry named name for the request of c,
+// read back from its command, with the tags of c as key=value tags, so
+// that captures can be promoted to the catalog.
+func (c *Capture) CatalogEntry(name string) (CatalogEntry, error) {
+	if c.Command == nil {
+		return CatalogEntry{}, fmt.Errorf("http2curl: capture without a command")
+	}
+	argv, err := splitShell(c.Command.String())
+	if err != nil {
+		return CatalogEntry{}, fmt.Errorf("http2curl: invalid command: %v", err)
+	}
+	r, err := parseCurlArgs(argv)
+	if err != nil {
+		return CatalogEntry{}, fmt.Errorf("http2curl: invalid command: %v", err)
+	}
+	def := Definition{Method: r.method, URL: r.url}
+	if strings.HasPrefix(r.body, "@") {
+		def.BodyFile = r.body[1:]
+	} else {
+		def.Body = r.body
+	}
+	if len(r.headers) > 0 {
+		def.Headers = map[string]string{}
+		for name, values := range r.headers {
+			def.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	entry := CatalogEntry{Name: name, Definition: def}
+	if len(c.Tags) > 0 {
+		entry.Tags = formatTags(c.Tags)
+	}
+	return entry, nil
+}
@@ -0,0 +1,115 @@
+package http2curl
+
+import (
+	"fmt"
+	"strings"
+)
+
+const exampleCatalog = `[
+  {
+    "name": "users.list",
+    "description": "List the users, a page at a time",
+    "tags": ["users", "read"],
+    "url": "https://api.example.com/users?page=1"
+  },
+  {
+    "name": "users.create",
+    "version": "1",
+    "description": "Create a user",
+    "tags": ["users", "write"],
+    "method": "POST",
+    "url": "https://api.example.com/users",
+    "body": "{\"name\": \"gopher\"}"
+  },
+  {
+    "name": "users.create",
+    "version": "2",
+    "description": "Create a user, with a role",
+    "tags": ["users", "write"],
+    "method": "POST",
+    "url": "https://api.example.com/v2/users",
+    "headers": {"Content-Type": "application/json"},
+    "body": "{\"name\": \"gopher\", \"role\": \"admin\"}"
+  }
+]
+`
+
+func ExampleCatalog() {
+	catalog, err := LoadCatalog(strings.NewReader(exampleCatalog))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, entry := range catalog.List() {
+		fmt.Printf("%s: %s\n", entry.ID(), entry.Description)
+	}
+	for _, entry := range catalog.Search("tag:write user") {
+		fmt.Println("found", entry.ID())
+	}
+
+	command, _ := catalog.Render("users.create", nil)
+	fmt.Println(command)
+	command, _ = catalog.Render("users.create@1", nil)
+	fmt.Println(command)
+
+	// Output:
+	// users.create@2: Create a user, with a role
+	// users.list: List the users, a page at a time
+	// found users.create@2
+	// curl -X 'POST' -d '{"name": "gopher", "role": "admin"}' -H 'Content-Type: application/json' 'https://api.example.com/v2/users'
+	// curl -X 'POST' -d '{"name": "gopher"}' 'https://api.example.com/users'
+}
+
+func ExampleCatalog_Render() {
+	catalog, _ := LoadCatalog(strings.NewReader(exampleCatalog))
+
+	script, _ := catalog.Render("users.list", PaginationScript)
+	fmt.Print(script)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// set -euo pipefail
+	//
+	// page=1
+	// while :; do
+	//   response=$(curl -X 'GET' 'https://api.example.com/users?page='"${page}")
+	//   printf '%s\n' "$response"
+	//   # stop on an empty page, adjust to the API
+	//   [ "$(printf '%s' "$response" | jq 'length')" -gt 0 ] || break
+	//   page=$((page + 1))
+	// done
+}
+
+func ExampleLookupGenerator() {
+	catalog, _ := LoadCatalog(strings.NewReader(exampleCatalog))
+
+	fmt.Println(GeneratorNames())
+	generate, _ := LookupGenerator("httpie")
+	command, _ := catalog.Render("users.create@1", generate)
+	fmt.Println(command)
+
+	// Output:
+	// [axios curl fetch hey httpie pagination powershell python wget]
+	// https 'api.example.com/users' 'name=gopher'
+}
+
+func ExampleCatalog_Add() {
+	catalog := NewCatalog()
+	def := Definition{URL: "https://api.example.com/users"}
+
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.list", Version: "1", Definition: def}))
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.list", Version: "1", Definition: def}))
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.list", Definition: def}))
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.get", Definition: def}))
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.get", Definition: def}))
+	fmt.Println(catalog.Add(CatalogEntry{Name: "users.get", Version: "2", Definition: def}))
+
+	// Output:
+	// <nil>
+	// http2curl: duplicate catalog entry users.list@1
+	// http2curl: catalog entry users.list mixes versioned and unversioned entries
+	// <nil>
+	// http2curl: duplicate catalog entry users.get
+	// http2curl: catalog entry users.get mixes versioned and unversioned entries
+}
@@ -0,0 +1,62 @@
+package http2curl
+
+import (
+	"io"
+	"time"
+)
+
+// timeNow returns the current time.
+var timeNow = time.Now
+
+// Clock tells the time, see WithClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a Clock calling a function.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time { return f() }
+
+// FixedClock returns a Clock always telling t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// WithClock sets the clock telling the time wherever it is used: the
+// expiry warnings of pre-signed URLs, the start of HAR entries, the time
+// and latency of the captures of a Transport or a Middleware, the
+// creation time of bundles, the time captures are stored at by a
+// FileStore or an SQLStore and queued at by an HTTPSink, and the rate
+// limiting of a Replayer. A fixed clock makes the output reproducible,
+// for golden tests. The system clock is used by default, or when c is
+// nil.
+func WithClock(c Clock) Option {
+	return func(o *Options) { o.clock = c }
+}
+
+// WithRandom sets the source of the random values used by options, such
+// as the values of WithCacheBust, crypto/rand by default or when r is
+// nil. A fixed source makes the output reproducible.
+func WithRandom(r io.Reader) Option {
+	return func(o *Options) { o.random = r }
+}
+
+// now returns the time told by the clock of o.
+func (o *Options) now() time.Time {
+	if o.clock == nil {
+		return timeNow()
+	}
+	return o.clock.Now()
+}
+
+// readRandom fills b with random bytes from the source of o.
+func (o *Options) readRandom(b []byte) error {
+	if o.random == nil {
+		_, err := randRead(b)
+		return err
+	}
+	_, err := io.ReadFull(o.random, b)
+	return err
+}
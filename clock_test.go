@@ -0,0 +1,23 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func ExampleWithClock() {
+	req, _ := http.NewRequest("GET", "http://example.com/items", nil)
+	opts := []Option{
+		WithClock(FixedClock(time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC))),
+		WithRandom(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6, 7, 8})),
+		WithCacheBust(),
+	}
+
+	entry, _ := ToHAREntry(req, nil, opts...)
+	fmt.Println(entry.StartedDateTime, entry.Request.URL)
+
+	// Output:
+	// 2021-03-04T05:30:00Z http://example.com/items?_cb=0102030405060708
+}
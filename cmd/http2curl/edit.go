@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gdey/http2curl/v2"
+)
+
+// editHelp describes the commands of the edit subcommand.
+const editHelp = `commands:
+  show                 show the fields of the request
+  method METHOD        change the method
+  url URL              change the URL
+  host HOST            change the host of the URL
+  set NAME: VALUE      set a header
+  del NAME             delete a header
+  body [TEXT]          change the body, removed when TEXT is empty
+  redact               toggle the redaction of credentials
+  to TARGET            render with the generator TARGET
+  shell NAME           quote for the shell NAME: posix, cmd or powershell
+  load INPUT           replace the request, as a curl command or raw request
+  print                render the request
+  quit                 stop editing`
+
+// editor is the state of the edit subcommand.
+type editor struct {
+	req    *http.Request
+	body   []byte
+	target string
+	shell  string
+	redact bool
+}
+
+// edit runs the edit subcommand: it loads a request from the arguments or
+// a file, then reads commands from stdin, one per line, changing the
+// request and rendering it after each change.
+func edit(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("http2curl edit", flag.ContinueOnError)
+	from := fs.String("from", "", "input format: raw, har, mitmproxy or curl, guessed when empty")
+	file := fs.String("file", "", "file holding the request, instead of the arguments")
+	to := fs.String("to", "curl", "generator rendering the request: "+strings.Join(http2curl.GeneratorNames(), ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	input := []byte(strings.Join(fs.Args(), " "))
+	if *file != "" {
+		if fs.NArg() > 0 {
+			return fmt.Errorf("both -file and a request given")
+		}
+		var err error
+		if input, err = ioutil.ReadFile(*file); err != nil {
+			return err
+		}
+	}
+	e := &editor{target: *to}
+	if _, ok := http2curl.LookupGenerator(e.target); !ok {
+		return fmt.Errorf("unknown generator %q", e.target)
+	}
+	if len(bytes.TrimSpace(input)) > 0 {
+		if err := e.load(*from, input); err != nil {
+			return err
+		}
+		e.show(stdout)
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" {
+			return nil
+		}
+		if err := e.run(line, stdout); err != nil {
+			fmt.Fprintln(stdout, "error:", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// load replaces the request with the single one of input.
+func (e *editor) load(from string, input []byte) error {
+	reqs, err := readRequests(from, input)
+	if err != nil {
+		return err
+	}
+	if len(reqs) != 1 {
+		return fmt.Errorf("%d requests, want a single one", len(reqs))
+	}
+	var body []byte
+	if reqs[0].Body != nil {
+		if body, err = ioutil.ReadAll(reqs[0].Body); err != nil {
+			return err
+		}
+	}
+	e.req, e.body = reqs[0], body
+	return nil
+}
+
+// run runs the command line and writes its output to w.
+func (e *editor) run(line string, w io.Writer) error {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "help":
+		fmt.Fprintln(w, editHelp)
+		return nil
+	case "load":
+		if err := e.load("", []byte(arg)); err != nil {
+			return err
+		}
+		e.show(w)
+		return nil
+	}
+	if e.req == nil {
+		return fmt.Errorf("no request, load one first")
+	}
+	switch name {
+	case "show":
+		e.show(w)
+		return nil
+	case "print":
+	case "method":
+		if arg == "" {
+			return fmt.Errorf("method: missing method")
+		}
+		e.req.Method = strings.ToUpper(arg)
+	case "url":
+		u, err := url.Parse(arg)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("url: %q is not an absolute URL", arg)
+		}
+		e.req.URL, e.req.Host = u, ""
+	case "host":
+		if arg == "" {
+			return fmt.Errorf("host: missing host")
+		}
+		e.req.URL.Host, e.req.Host = arg, ""
+	case "set":
+		key, value, ok := strings.Cut(arg, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("set: want NAME: VALUE")
+		}
+		e.req.Header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	case "del":
+		e.req.Header.Del(arg)
+	case "body":
+		e.body = []byte(arg)
+	case "redact":
+		e.redact = !e.redact
+	case "to":
+		if _, ok := http2curl.LookupGenerator(arg); !ok {
+			return fmt.Errorf("unknown generator %q, want one of %s", arg, strings.Join(http2curl.GeneratorNames(), ", "))
+		}
+		e.target = arg
+	case "shell":
+		e.shell = arg
+	default:
+		return fmt.Errorf("unknown command %q, see help", name)
+	}
+	return e.render(w)
+}
+
+// show writes the fields of the request to w.
+func (e *editor) show(w io.Writer) {
+	fmt.Fprintln(w, "method:", e.req.Method)
+	fmt.Fprintln(w, "url:", e.req.URL)
+	names := make([]string, 0, len(e.req.Header))
+	for name := range e.req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range e.req.Header[name] {
+			fmt.Fprintf(w, "header %s: %s\n", name, value)
+		}
+	}
+	if len(e.body) > 0 {
+		fmt.Fprintf(w, "body: %s\n", e.body)
+	}
+	redaction := "off"
+	if e.redact {
+		redaction = "on"
+	}
+	fmt.Fprintln(w, "redaction:", redaction)
+	fmt.Fprintln(w, "target:", e.target)
+}
+
+// render writes the request to w with the target generator.
+func (e *editor) render(w io.Writer) error {
+	var opts []http2curl.Option
+	if e.redact {
+		opts = append(opts, http2curl.WithRedactedHeaders())
+	}
+	if e.shell != "" {
+		opts = append(opts, http2curl.WithShell(e.shell))
+	}
+	req := e.req.Clone(e.req.Context())
+	req.Body, req.ContentLength = http.NoBody, 0
+	if len(e.body) > 0 {
+		req.Body = ioutil.NopCloser(bytes.NewReader(e.body))
+		req.ContentLength = int64(len(e.body))
+	}
+	req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(e.body)), nil }
+	generate, _ := http2curl.LookupGenerator(e.target)
+	out, err := generate(req, opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, strings.TrimSuffix(out, "\n"))
+	return nil
+}
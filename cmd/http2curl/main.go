@@ -0,0 +1,307 @@
+// Command http2curl converts HTTP requests between formats. It reads a raw
+// HTTP request, as dumped by httputil.DumpRequest or copied from Burp or
+// browser devtools, a HAR file, mitmproxy flows or a curl command, from a
+// file or the standard input, and writes it as a curl, wget or HTTPie
+// command, a HAR file, a .http file, a raw request or in the target of any
+// other generator of the library, such as python or powershell:
+//
+//	http2curl -to httpie request.txt
+//	pbpaste | http2curl -from curl -to raw
+//
+// The input format is guessed when -from is not given: HAR files start
+// with {, mitmproxy flows with a digit or [, curl commands with curl.
+//
+// The convert subcommand takes the input itself as arguments rather than
+// a file, reading the standard input when none is given:
+//
+//	http2curl convert -from curl -to python 'curl -X POST -d a=1 https://example.com'
+//
+// The exec subcommand sends the requests of the curl commands of a script,
+// without running a shell, and fails when a response differs from the
+// one expected:
+//
+//	http2curl exec -expect-status 200 -expect-body-file golden.json commands.sh
+//
+// The edit subcommand loads a request from its arguments or a file, then
+// reads editing commands from the standard input, such as set to set a
+// header, host to change the host, redact to toggle the redaction of
+// credentials or to to pick the generator, and renders the request after
+// each change. help lists the commands; load reads a pasted request:
+//
+//	http2curl edit 'curl -H "Authorization: Bearer t" https://example.com'
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strings"
+
+	"github.com/gdey/http2curl/v2"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		// the usage was printed by the flag set
+	default:
+		fmt.Fprintln(os.Stderr, "http2curl:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command with the arguments args.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "convert":
+			return convert(args[1:], stdin, stdout)
+		case "exec":
+			return execute(args[1:], stdin, stdout)
+		case "edit":
+			return edit(args[1:], stdin, stdout)
+		}
+	}
+	fs := flag.NewFlagSet("http2curl", flag.ContinueOnError)
+	from, to, multiline := formatFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	in := stdin
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		if name := fs.Arg(0); name != "-" {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+	default:
+		return fmt.Errorf("too many arguments, want a single input file")
+	}
+	input, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	reqs, err := readRequests(*from, input)
+	if err != nil {
+		return err
+	}
+	return writeRequests(stdout, *to, *multiline, reqs)
+}
+
+// convert runs the convert subcommand, converting the input given as
+// arguments, or read from stdin when there are none.
+func convert(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("http2curl convert", flag.ContinueOnError)
+	from, to, multiline := formatFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	input := []byte(strings.Join(fs.Args(), " "))
+	if fs.NArg() == 0 {
+		var err error
+		if input, err = ioutil.ReadAll(stdin); err != nil {
+			return err
+		}
+	}
+	reqs, err := readRequests(*from, input)
+	if err != nil {
+		return err
+	}
+	return writeRequests(stdout, *to, *multiline, reqs)
+}
+
+// execute runs the exec subcommand, replaying the curl commands of a
+// script and reporting the responses which differ from the expected one.
+func execute(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("http2curl exec", flag.ContinueOnError)
+	status := fs.Int("expect-status", 0, "expected status code, any when 0")
+	bodyFile := fs.String("expect-body-file", "", "file holding the expected response body, any when empty")
+	concurrency := fs.Int("concurrency", 1, "number of requests sent at once")
+	rate := fs.Float64("rate", 0, "requests per second sent to each host at most, without limit when 0")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	in := stdin
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		if name := fs.Arg(0); name != "-" {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+	default:
+		return fmt.Errorf("too many arguments, want a single script")
+	}
+	script, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	if *bodyFile != "" {
+		if body, err = ioutil.ReadFile(*bodyFile); err != nil {
+			return err
+		}
+	}
+	reqs, err := http2curl.ParseCurlScript(string(script))
+	if err != nil {
+		return err
+	}
+
+	replayer := &http2curl.Replayer{Concurrency: *concurrency, HostRate: *rate}
+	sum := sha256.Sum256(body)
+	diverged := 0
+	for i, result := range replayer.Replay(context.Background(), reqs) {
+		var diffs []string
+		switch {
+		case result.Err != nil:
+			diffs = append(diffs, result.Err.Error())
+		default:
+			if *status != 0 && result.StatusCode != *status {
+				diffs = append(diffs, fmt.Sprintf("status %d, want %d", result.StatusCode, *status))
+			}
+			if *bodyFile != "" && result.BodySHA256 != hex.EncodeToString(sum[:]) {
+				diffs = append(diffs, fmt.Sprintf("body of %d bytes differs from the %d bytes of %s", result.BodySize, len(body), *bodyFile))
+			}
+		}
+		command := fmt.Sprint(result.Command)
+		if result.Command == nil {
+			command = reqs[i].Method + " " + reqs[i].URL.String()
+		}
+		if len(diffs) == 0 {
+			fmt.Fprintf(stdout, "ok %d %s\n", result.StatusCode, command)
+			continue
+		}
+		diverged++
+		fmt.Fprintf(stdout, "FAIL %d %s\n", result.StatusCode, command)
+		for _, diff := range diffs {
+			fmt.Fprintf(stdout, "\t%s\n", diff)
+		}
+	}
+	if diverged > 0 {
+		return fmt.Errorf("%d of %d requests diverged", diverged, len(reqs))
+	}
+	return nil
+}
+
+// formatFlags defines the flags selecting the input and output formats.
+func formatFlags(fs *flag.FlagSet) (from, to *string, multiline *bool) {
+	from = fs.String("from", "", "input format: raw, har, mitmproxy or curl, guessed when empty")
+	to = fs.String("to", "curl", "output format: har, http, raw or a generator: "+strings.Join(http2curl.GeneratorNames(), ", "))
+	multiline = fs.Bool("multiline", false, "write curl commands on several lines")
+	return from, to, multiline
+}
+
+// readRequests reads the requests of input, in format from.
+func readRequests(from string, input []byte) ([]*http.Request, error) {
+	if from == "" {
+		switch trimmed := bytes.TrimSpace(input); {
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			from = "har"
+		case bytes.HasPrefix(trimmed, []byte("[")), len(trimmed) > 0 && trimmed[0] >= '0' && trimmed[0] <= '9':
+			from = "mitmproxy"
+		case bytes.HasPrefix(trimmed, []byte("curl ")):
+			from = "curl"
+		default:
+			from = "raw"
+		}
+	}
+	switch from {
+	case "raw":
+		req, err := http2curl.ReadRawRequest(bytes.NewReader(input))
+		if err != nil {
+			return nil, err
+		}
+		return []*http.Request{req}, nil
+	case "curl":
+		req, err := http2curl.ParseCurlCommand(strings.TrimSpace(string(input)))
+		if err != nil {
+			return nil, err
+		}
+		return []*http.Request{req}, nil
+	case "har":
+		return http2curl.FromHAR(bytes.NewReader(input))
+	case "mitmproxy":
+		return http2curl.FromMitmproxy(bytes.NewReader(input))
+	}
+	return nil, fmt.Errorf("unknown input format %q", from)
+}
+
+// writeRequests writes reqs to w in format to.
+func writeRequests(w io.Writer, to string, multiline bool, reqs []*http.Request) error {
+	switch to {
+	case "curl":
+		for _, req := range reqs {
+			command, err := http2curl.GetCurlCommandWithOptions(req)
+			if err != nil {
+				return err
+			}
+			if multiline {
+				fmt.Fprintln(w, command.MultilineString())
+			} else {
+				fmt.Fprintln(w, command)
+			}
+		}
+	case "har":
+		var entries []http2curl.HAREntry
+		for _, req := range reqs {
+			entry, err := http2curl.ToHAREntry(req, nil)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, *entry)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(http2curl.NewHAR(entries...))
+	case "http":
+		return http2curl.WriteRESTClientFile(w, reqs)
+	case "raw":
+		for _, req := range reqs {
+			dump, err := httputil.DumpRequest(req, true)
+			if err != nil {
+				return err
+			}
+			// dumps without a body end with the blank line separating them
+			if !bytes.HasSuffix(dump, []byte("\r\n\r\n")) {
+				dump = append(dump, "\r\n\r\n"...)
+			}
+			if _, err := w.Write(dump); err != nil {
+				return err
+			}
+		}
+	default:
+		generate, ok := http2curl.LookupGenerator(to)
+		if !ok {
+			return fmt.Errorf("unknown output format %q", to)
+		}
+		for _, req := range reqs {
+			out, err := generate(req)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, strings.TrimSuffix(out, "\n"))
+		}
+	}
+	return nil
+}
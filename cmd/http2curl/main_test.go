@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Example_rawToCurl() {
+	raw := "POST /api/items HTTP/1.1\r\nHost: api.example.com\r\nContent-Type: application/json\r\n\r\n{\"name\":\"gopher\"}"
+	if err := run(nil, strings.NewReader(raw), os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Content-Type: application/json' 'https://api.example.com/api/items'
+}
+
+func Example_curlToHTTPie() {
+	curl := `curl -H 'Accept: application/json' 'https://api.example.com/items?page=2'`
+	if err := run([]string{"-to", "httpie"}, strings.NewReader(curl), os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// https 'api.example.com/items' 'page==2' 'Accept:application/json'
+}
+
+func Example_harToRaw() {
+	har := `{"log": {"version": "1.2", "entries": [
+  {"request": {"method": "GET", "url": "https://api.example.com/items", "headers": [{"name": "Accept", "value": "*/*"}]}},
+  {"request": {"method": "DELETE", "url": "https://api.example.com/items/1", "headers": []}}
+]}}`
+	var out strings.Builder
+	if err := run([]string{"-to", "raw"}, strings.NewReader(har), &out); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Print(strings.ReplaceAll(out.String(), "\r\n", "\n"))
+
+	// Output:
+	// GET /items HTTP/1.1
+	// Host: api.example.com
+	// Accept: */*
+	//
+	// DELETE /items/1 HTTP/1.1
+	// Host: api.example.com
+}
+
+func Example_convert() {
+	args := []string{"convert", "-from", "curl", "-to", "python", `curl -X POST -H 'Content-Type: application/json' -d '{"name":"gopher"}' https://api.example.com/items`}
+	if err := run(args, nil, os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// import requests
+	//
+	// response = requests.request(
+	//     "POST",
+	//     "https://api.example.com/items",
+	//     json={
+	//         "name": "gopher",
+	//     },
+	// )
+}
+
+func TestExec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "golden.json")
+	if err := os.WriteFile(golden, []byte(`{"ok":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := "# smoke test\ncurl " + server.URL + "/items\necho done\ncurl -X POST \\\n  " + server.URL + "/missing\n"
+
+	var out strings.Builder
+	err := run([]string{"exec", "-expect-status", "200", "-expect-body-file", golden}, strings.NewReader(script), &out)
+	if err == nil || err.Error() != "1 of 2 requests diverged" {
+		t.Errorf("error = %v, want 1 of 2 requests diverged", err)
+	}
+	want := `ok 200 curl -X 'GET' 'http://server/items'
+FAIL 404 curl -X 'POST' 'http://server/missing'
+	status 404, want 200
+	body of 19 bytes differs from the 11 bytes of ` + golden + `
+`
+	if got := strings.ReplaceAll(out.String(), server.URL, "http://server"); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Example_edit() {
+	args := []string{"edit", `curl -H 'Authorization: Bearer secret' -d '{"name":"gopher"}' https://api.example.com/items`}
+	commands := strings.NewReader(`host api.staging.example.com
+del Content-Type
+redact
+to httpie
+bogus
+quit
+`)
+	if err := run(args, commands, os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// method: POST
+	// url: https://api.example.com/items
+	// header Authorization: Bearer secret
+	// header Content-Type: application/x-www-form-urlencoded
+	// body: {"name":"gopher"}
+	// redaction: off
+	// target: curl
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Authorization: Bearer secret' -H 'Content-Type: application/x-www-form-urlencoded' 'https://api.staging.example.com/items'
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Authorization: Bearer secret' 'https://api.staging.example.com/items'
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Authorization: REDACTED' 'https://api.staging.example.com/items'
+	// https 'api.staging.example.com/items' 'Authorization:REDACTED' 'name=gopher'
+	// error: unknown command "bogus", see help
+}
@@ -0,0 +1,90 @@
+package http2curl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by WithColor.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiCyan    = "\x1b[36m"
+	ansiMagenta = "\x1b[35m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+)
+
+// WithColor renders the command with ANSI colors: flags, header names,
+// the URL and the body each get their own color. The result is meant to
+// be displayed on a terminal, see ColorWriter to only enable it there.
+func WithColor() Option {
+	return func(o *Options) { o.color = true }
+}
+
+// colorize wraps an escaped token in the color matching its kind.
+func colorize(kind argKind, token string) string {
+	switch kind {
+	case argProgram:
+		return ansiBold + token + ansiReset
+	case argFlag:
+		return ansiCyan + token + ansiReset
+	case argHeader:
+		// only color the name when it is cleanly inside the opening quote
+		i := strings.IndexByte(token, ':')
+		if i > 1 && token[0] == '\'' && !strings.ContainsAny(token[1:i], "'\\\n") {
+			return "'" + ansiMagenta + token[1:i] + ansiReset + token[i:]
+		}
+		return token
+	case argURL:
+		return ansiGreen + token + ansiReset
+	case argBody:
+		return ansiYellow + token + ansiReset
+	}
+	return token
+}
+
+// ColorWriter writes curl commands to an io.Writer, one per line, and
+// colors them only when the writer is a terminal.
+type ColorWriter struct {
+	w     io.Writer
+	color bool
+}
+
+// NewColorWriter returns a ColorWriter for w. Colors are enabled when w is
+// a terminal and the NO_COLOR environment variable is not set.
+func NewColorWriter(w io.Writer) *ColorWriter {
+	return &ColorWriter{w: w, color: isTerminal(w) && os.Getenv("NO_COLOR") == ""}
+}
+
+// Colored reports whether the writer emits ANSI colors.
+func (cw *ColorWriter) Colored() bool { return cw.color }
+
+// WriteRequest generates the curl command for req and writes it as a line.
+func (cw *ColorWriter) WriteRequest(req *http.Request, opts ...Option) error {
+	if cw.color {
+		opts = append(opts[:len(opts):len(opts)], WithColor())
+	}
+	command, err := Command(req, nil, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cw.w, command)
+	return err
+}
+
+// isTerminal reports whether w is a character device, such as a TTY.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
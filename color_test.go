@@ -0,0 +1,48 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func ExampleWithColor() {
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader("hi"))
+	req.Header.Set("Content-Type", "text/plain")
+
+	command, _ := Command(req, nil, WithColor())
+	fmt.Println(strconv.Quote(command.String()))
+
+	// Output:
+	// "\x1b[1mcurl\x1b[0m \x1b[36m-X\x1b[0m 'POST' \x1b[36m-d\x1b[0m \x1b[33m'hi'\x1b[0m \x1b[36m-H\x1b[0m '\x1b[35mContent-Type\x1b[0m: text/plain' \x1b[32m'http://example.com/'\x1b[0m"
+}
+
+func ExampleColorWriter() {
+	var buf bytes.Buffer
+	cw := NewColorWriter(&buf)
+
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	_ = cw.WriteRequest(req)
+	fmt.Print(buf.String())
+	fmt.Println(cw.Colored())
+
+	// Output:
+	// curl -X 'GET' 'http://example.com/'
+	// false
+}
+
+func TestColorWriterKeepsOptions(t *testing.T) {
+	cw := &ColorWriter{w: new(bytes.Buffer), color: true}
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	opts := make([]Option, 1, 2)
+	opts[0] = WithQuoteStyle(QuoteMinimal)
+	if err := cw.WriteRequest(req, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if spare := opts[:2][1]; spare != nil {
+		t.Error("WriteRequest wrote into the backing array of the options")
+	}
+}
@@ -0,0 +1,28 @@
+package http2curl
+
+import (
+	"net/http"
+)
+
+// WithUpstreamCompat reproduces the output of the upstream
+// moul.io/http2curl package: -k for https URLs, a trailing --compressed,
+// -d for every body, and for server-side requests a URL rebuilt from the
+// Host header and the path only. Protections against argument injection
+// are kept.
+func WithUpstreamCompat() Option {
+	return func(o *Options) { o.upstreamCompat = true }
+}
+
+// upstreamURL returns the URL of req and its scheme as computed upstream.
+func upstreamURL(req *http.Request) (string, string) {
+	scheme := req.URL.Scheme
+	requestURL := req.URL.String()
+	if scheme == "" {
+		scheme = "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+		requestURL = scheme + "://" + req.Host + req.URL.Path
+	}
+	return requestURL, scheme
+}
@@ -0,0 +1,32 @@
+package http2curl
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func ExampleWithUpstreamCompat() {
+	req, _ := http.NewRequest("PUT", "https://www.example.com/abc?x=1", strings.NewReader("@data"))
+	req.Header.Set("Content-Type", "text/plain")
+
+	command, _ := Command(req, nil, WithUpstreamCompat())
+	fmt.Println(command)
+
+	// Output:
+	// curl -k -X 'PUT' -d '@data' -H 'Content-Type: text/plain' 'https://www.example.com/abc?x=1' --compressed
+}
+
+func ExampleWithUpstreamCompat_server() {
+	req := httptest.NewRequest("GET", "/abc?x=1", nil)
+	req.Host = "api.example.com"
+	req.TLS = &tls.ConnectionState{}
+
+	command, _ := Command(req, nil, WithUpstreamCompat())
+	fmt.Println(command)
+
+	// Output:
+	// curl -k -X 'GET' 'https://api.example.com/abc' --compressed
+}
@@ -0,0 +1,57 @@
+package http2curl
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// curlEncodings are the content codings curl --compressed may decode,
+// depending on how it was built.
+var curlEncodings = map[string]bool{"gzip": true, "x-gzip": true, "deflate": true, "br": true, "zstd": true}
+
+// WithCompressionAdvice adds a comment above commands for requests setting
+// Accept-Encoding themselves, warning that the response is likely to be
+// compressed. Go does not decompress such responses either, and curl
+// prints them as binary data unless told otherwise.
+func WithCompressionAdvice() Option {
+	return func(o *Options) { o.compressionAdvice = true }
+}
+
+// WithCompressedFlag adds --compressed to commands for requests accepting
+// a compressed response, so that curl decompresses it.
+func WithCompressedFlag() Option {
+	return func(o *Options) { o.compressedFlag = true }
+}
+
+// acceptsCompression reports whether req accepts a response encoding curl
+// can decompress.
+func acceptsCompression(req *http.Request) bool {
+	for _, value := range req.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			params := strings.Split(strings.ToLower(coding), ";")
+			refused := false
+			for _, param := range params[1:] {
+				if q := strings.TrimPrefix(strings.TrimSpace(param), "q="); q != param {
+					weight, err := strconv.ParseFloat(q, 64)
+					refused = err == nil && weight == 0
+				}
+			}
+			if curlEncodings[strings.TrimSpace(params[0])] && !refused {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compressionNote returns the comment of WithCompressionAdvice for req.
+func (o *Options) compressionNote(req *http.Request) string {
+	if !o.compressionAdvice || !acceptsCompression(req) {
+		return ""
+	}
+	if o.compressedFlag || o.upstreamCompat {
+		return "note: Accept-Encoding is set, --compressed decompresses the response"
+	}
+	return "note: Accept-Encoding is set, the response may be compressed: add --compressed to decompress it, or --output to save it as is"
+}
@@ -0,0 +1,28 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithCompressionAdvice() {
+	req, _ := http.NewRequest("GET", "http://example.com/data.json", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br;q=0")
+
+	command, _ := Command(req, nil, WithCompressionAdvice())
+	fmt.Println(command)
+
+	command, _ = Command(req, nil, WithCompressionAdvice(), WithCompressedFlag())
+	fmt.Println(command)
+
+	req.Header.Set("Accept-Encoding", "identity")
+	command, _ = Command(req, nil, WithCompressionAdvice(), WithCompressedFlag())
+	fmt.Println(command)
+
+	// Output:
+	// # note: Accept-Encoding is set, the response may be compressed: add --compressed to decompress it, or --output to save it as is
+	// curl -X 'GET' -H 'Accept-Encoding: gzip, br;q=0' 'http://example.com/data.json'
+	// # note: Accept-Encoding is set, --compressed decompresses the response
+	// curl -X 'GET' -H 'Accept-Encoding: gzip, br;q=0' 'http://example.com/data.json' --compressed
+	// curl -X 'GET' -H 'Accept-Encoding: identity' 'http://example.com/data.json'
+}
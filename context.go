@@ -0,0 +1,48 @@
+package http2curl
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type optionsKey struct{}
+
+// ContextWithOptions returns a copy of ctx carrying opts. They are applied
+// by GetCurlCommandContext before its own options, which makes it possible
+// to set request-scoped behavior, such as redaction, from a middleware.
+func ContextWithOptions(ctx context.Context, opts ...Option) context.Context {
+	opts = append(OptionsFromContext(ctx), opts...)
+	return context.WithValue(ctx, optionsKey{}, opts)
+}
+
+// OptionsFromContext returns the options stored in ctx by
+// ContextWithOptions.
+func OptionsFromContext(ctx context.Context) []Option {
+	opts, _ := ctx.Value(optionsKey{}).([]Option)
+	// never share the backing array with the context
+	return append([]Option(nil), opts...)
+}
+
+// GetCurlCommandContext returns a CurlCommand corresponding to an
+// http.Request. Reading the body stops with ctx.Err() once ctx is done, in
+// which case the request body is left as it was found. Options stored in
+// ctx with ContextWithOptions are applied before opts.
+func GetCurlCommandContext(ctx context.Context, req *http.Request, opts ...Option) (*CurlCommand, error) {
+	opts = append(OptionsFromContext(ctx), opts...)
+	opts = append(opts, func(o *Options) { o.ctx = ctx })
+	return Command(req, nil, opts...)
+}
+
+// ctxReader fails reads once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,36 @@
+package http2curl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+func ExampleGetCurlCommandContext() {
+	ctx := ContextWithOptions(context.Background(), ForInteractive())
+
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	command, _ := GetCurlCommandContext(ctx, req, WithSaveResponse("out"))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -v -D 'out.headers' -o 'out.body' 'http://example.com/'
+}
+
+func ExampleGetCurlCommandContext_canceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader("payload"))
+	_, err := GetCurlCommandContext(ctx, req)
+	fmt.Println(err)
+
+	body, _ := ioutil.ReadAll(req.Body)
+	fmt.Println(string(body))
+
+	// Output:
+	// context canceled
+	// payload
+}
@@ -0,0 +1,41 @@
+package http2curl
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithCookieFlags renders each cookie as its own --cookie 'name=value'
+// flag rather than a single Cookie header, which makes it easier to remove
+// them one at a time to find the one that matters. Cookie headers that
+// cannot be parsed are kept as they are.
+func WithCookieFlags() Option {
+	return func(o *Options) { o.cookieFlags = true }
+}
+
+// cookieArgs returns the --cookie flags for the cookies of req, and
+// whether they replace its Cookie headers.
+func (o *Options) cookieArgs(req *http.Request) (argList, bool) {
+	if !o.cookieFlags || len(req.Header["Cookie"]) == 0 {
+		return nil, false
+	}
+	// only split headers where every part is a valid cookie
+	parts := 0
+	for _, line := range req.Header["Cookie"] {
+		for _, part := range strings.Split(line, ";") {
+			if strings.TrimSpace(part) != "" {
+				parts++
+			}
+		}
+	}
+	cookies := req.Cookies()
+	if len(cookies) != parts {
+		return nil, false
+	}
+
+	var args argList
+	for _, cookie := range cookies {
+		args.flag("--cookie", argValue, cookie.Name+"="+cookie.Value)
+	}
+	return args, true
+}
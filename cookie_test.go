@@ -0,0 +1,19 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithCookieFlags() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "0123456789"})
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "ab_test", Value: "b"})
+
+	command, _ := Command(req, nil, WithCookieFlags())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' --cookie 'session=0123456789' --cookie 'theme=dark' --cookie 'ab_test=b' 'http://example.com/'
+}
@@ -0,0 +1,390 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ParseCurlCommand returns the request sent by the curl command line
+// command, written for a POSIX shell, so that a command from a ticket or a
+// runbook can be replayed from Go. It reads -X, -H, -A, -e, the -d family
+// including --data-urlencode, -G, -I, -u, -F, -b and --url, short flags
+// bundled as in -sX PUT, and follows the defaults of curl: POST when there
+// is a body, a form Content-Type for -d and http:// for URLs without a
+// scheme. Files are read for -F name=@path and name=<path, but a body or
+// cookies read by curl from a file, as with -d @path but not --data-raw
+// @text, are an error. Other flags are skipped. Variable references, such
+// as $TOKEN, are kept in the request as ${TOKEN}. Commands generated by
+// this package are read back as the request they were generated from.
+func ParseCurlCommand(command string) (*http.Request, error) {
+	argv, err := splitPastedShell(command)
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid command: %v", err)
+	}
+	r, err := parseCurlArgs(argv)
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid command: %v", err)
+	}
+	target := r.url
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+
+	var body []byte
+	switch {
+	case r.hasBody && len(r.forms) > 0:
+		return nil, fmt.Errorf("http2curl: both -d and -F bodies")
+	case r.bodyFile != "":
+		return nil, fmt.Errorf("http2curl: body read from the file %s", r.bodyFile)
+	case r.hasBody:
+		body = []byte(r.body)
+		if r.headers.Get("Content-Type") == "" {
+			r.headers.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	case len(r.forms) > 0:
+		var contentType string
+		if body, contentType, err = multipartForm(r.forms); err != nil {
+			return nil, fmt.Errorf("http2curl: %v", err)
+		}
+		r.headers.Set("Content-Type", contentType)
+	}
+
+	req, err := http.NewRequest(r.method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: %v", err)
+	}
+	if body == nil {
+		req.Body, req.GetBody, req.ContentLength = http.NoBody, nil, 0
+	}
+	for name, values := range r.headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = values[0]
+			continue
+		}
+		req.Header[name] = values
+	}
+	if r.user != "" {
+		user, password, _ := strings.Cut(r.user, ":")
+		req.SetBasicAuth(user, password)
+	}
+	for _, c := range r.cookies {
+		if !strings.Contains(c, "=") {
+			return nil, fmt.Errorf("http2curl: cookies read from the file %s", c)
+		}
+		if prev := req.Header.Get("Cookie"); prev != "" {
+			c = prev + "; " + c
+		}
+		req.Header.Set("Cookie", c)
+	}
+	return req, nil
+}
+
+// ParseCurlScript returns the requests sent by the curl commands of
+// script, a POSIX shell script with a command per line, continued with a
+// backslash or within quotes, such as a runbook or the commands logged by
+// a Transport. Lines which do not run curl, such as comments, assignments
+// or other programs, are skipped. Errors give the line of the command.
+func ParseCurlScript(script string) ([]*http.Request, error) {
+	var (
+		reqs    []*http.Request
+		command strings.Builder
+		start   int
+	)
+	lines := strings.Split(script, "\n")
+	for i, line := range lines {
+		if command.Len() == 0 {
+			start = i + 1
+		}
+		command.WriteString(line + "\n")
+		if strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			continue
+		}
+		if _, err := splitPastedShell(command.String()); err == errUnterminatedQuote && i+1 < len(lines) {
+			continue
+		}
+		text := command.String()
+		command.Reset()
+		if words := strings.Fields(text); len(words) == 0 || words[0] != "curl" {
+			continue
+		}
+		req, err := ParseCurlCommand(text)
+		if err != nil {
+			return nil, fmt.Errorf("http2curl: line %d: %s", start, strings.TrimPrefix(err.Error(), "http2curl: "))
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// dataURLEncode returns the value of --data-urlencode as sent by curl:
+// content, =content and name=content have content URL-encoded.
+func dataURLEncode(value string) (string, error) {
+	name, content, ok := strings.Cut(value, "=")
+	if !ok {
+		if strings.Contains(value, "@") {
+			return "", fmt.Errorf("--data-urlencode %s reads a file", value)
+		}
+		name, content = "", value
+	}
+	encoded := strings.ReplaceAll(url.QueryEscape(content), "+", "%20")
+	if name == "" {
+		return encoded, nil
+	}
+	return name + "=" + encoded, nil
+}
+
+// multipartForm returns the multipart body of the -F values forms and its
+// Content-Type.
+func multipartForm(forms []string) ([]byte, string, error) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	for _, form := range forms {
+		name, value, ok := strings.Cut(form, "=")
+		if !ok {
+			return nil, "", fmt.Errorf("invalid form %q", form)
+		}
+		switch {
+		case strings.HasPrefix(value, "@"):
+			path, params, _ := strings.Cut(value[1:], ";")
+			filename, contentType := filepath.Base(path), "application/octet-stream"
+			for _, param := range strings.Split(params, ";") {
+				switch k, v, _ := strings.Cut(param, "="); k {
+				case "type":
+					contentType = v
+				case "filename":
+					filename = v
+				}
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", err
+			}
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, name, filename))
+			h.Set("Content-Type", contentType)
+			part, err := w.CreatePart(h)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := part.Write(content); err != nil {
+				return nil, "", err
+			}
+		case strings.HasPrefix(value, "<"):
+			content, err := os.ReadFile(value[1:])
+			if err != nil {
+				return nil, "", err
+			}
+			if err := w.WriteField(name, string(content)); err != nil {
+				return nil, "", err
+			}
+		default:
+			if err := w.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), w.FormDataContentType(), nil
+}
+
+// curlRequest is the request a curl command line sends, as far as can be
+// told from its arguments.
+type curlRequest struct {
+	method  string
+	url     string
+	headers http.Header
+	body    string
+	hasBody bool
+	// bodyFile is the file a data flag reads the body from, as -d @path.
+	bodyFile string
+	// user is the value of -u, user:password.
+	user    string
+	cookies []string
+	// forms are the values of -F, name=value.
+	forms []string
+}
+
+// parseCurlArgs reads the request sent by the curl arguments argv, the
+// program name included. Flags other than those describing the request
+// are skipped, along with their value when they take one.
+func parseCurlArgs(argv []string) (*curlRequest, error) {
+	if len(argv) == 0 || argv[0] != "curl" {
+		return nil, fmt.Errorf("not a curl command")
+	}
+	r := &curlRequest{headers: http.Header{}}
+	head, get := false, false
+	for i := 1; i < len(argv); i++ {
+		flag := argv[i]
+		if len(flag) > 2 && flag[0] == '-' && flag[1] != '-' {
+			// short flags may be bundled, as in -sSL, and glued to the
+			// value of the last one, as in -XPATCH or -sXPUT
+			argv = append(argv[:i:i], append(splitShortFlags(flag), argv[i+1:]...)...)
+			flag = argv[i]
+		}
+		if !strings.HasPrefix(flag, "-") || flag == "-" {
+			r.url = flag
+			continue
+		}
+		switch flag {
+		case "-I", "--head":
+			head = true
+			continue
+		case "-G", "--get":
+			get = true
+			continue
+		case "-T", "--upload-file":
+			return nil, fmt.Errorf("body uploaded from a file")
+		}
+		if !valueFlags[flag] && !shortValueFlags[flag] {
+			continue
+		}
+		if i+1 >= len(argv) {
+			return nil, fmt.Errorf("missing value for %s", flag)
+		}
+		i++
+		value := argv[i]
+		switch {
+		case flag == "-X" || flag == "--request":
+			r.method = value
+		case flag == "-H" || flag == "--header":
+			name, v, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q", value)
+			}
+			r.headers.Add(strings.TrimSpace(name), strings.TrimSpace(v))
+		case flag == "--data-urlencode":
+			if r.hasBody {
+				r.body += "&"
+			}
+			encoded, err := dataURLEncode(value)
+			if err != nil {
+				return nil, err
+			}
+			r.body += encoded
+			r.hasBody = true
+		case dataFlags[flag]:
+			// only --data-raw sends a value starting with @ as it is
+			if flag != "--data-raw" && strings.HasPrefix(value, "@") && r.bodyFile == "" {
+				r.bodyFile = value[1:]
+			}
+			if r.hasBody {
+				r.body += "&"
+			}
+			r.body += value
+			r.hasBody = true
+		case flag == "--url":
+			r.url = value
+		case flag == "-u" || flag == "--user":
+			r.user = value
+		case flag == "-b" || flag == "--cookie":
+			r.cookies = append(r.cookies, value)
+		case flag == "-F" || flag == "--form":
+			r.forms = append(r.forms, value)
+		case flag == "-A" || flag == "--user-agent":
+			r.headers.Set("User-Agent", value)
+		case flag == "-e" || flag == "--referer":
+			r.headers.Set("Referer", value)
+		}
+	}
+	if r.url == "" {
+		return nil, fmt.Errorf("no URL")
+	}
+	if get && r.hasBody {
+		// -G sends the data in the query string
+		if r.bodyFile != "" {
+			return nil, fmt.Errorf("query read from the file %s", r.bodyFile)
+		}
+		sep := "?"
+		if strings.Contains(r.url, "?") {
+			sep = "&"
+		}
+		r.url += sep + r.body
+		r.body, r.hasBody = "", false
+	}
+	if r.method == "" {
+		switch {
+		case head:
+			r.method = http.MethodHead
+		case r.hasBody || len(r.forms) > 0:
+			r.method = http.MethodPost
+		default:
+			r.method = http.MethodGet
+		}
+	}
+	return r, nil
+}
+
+// shortValueFlags are the short curl flags taking a value that valueFlags
+// leaves out, whose value is skipped.
+var shortValueFlags = map[string]bool{
+	"-C": true, "-E": true, "-K": true, "-P": true, "-Q": true, "-U": true,
+	"-Y": true, "-r": true, "-t": true, "-y": true, "-z": true,
+}
+
+// splitShortFlags splits the bundle of short flags arg, such as -sSL,
+// into separate flags, the rest of the bundle after a flag taking a value
+// being its value, as curl does: -sXPUT is -s -X PUT.
+func splitShortFlags(arg string) []string {
+	var flags []string
+	for i := 1; i < len(arg); i++ {
+		flag := "-" + arg[i:i+1]
+		flags = append(flags, flag)
+		if valueFlags[flag] || shortValueFlags[flag] {
+			if i+1 < len(arg) {
+				flags = append(flags, arg[i+1:])
+			}
+			break
+		}
+	}
+	return flags
+}
+
+// diff returns the differences between r, as documented, and want.
+func (r *curlRequest) diff(want *curlRequest) []string {
+	var diffs []string
+	if r.method != want.method {
+		diffs = append(diffs, fmt.Sprintf("method is %s, want %s", r.method, want.method))
+	}
+	if r.url != want.url {
+		diffs = append(diffs, fmt.Sprintf("URL is %q, want %q", r.url, want.url))
+	}
+
+	names := map[string]bool{}
+	for name := range r.headers {
+		names[name] = true
+	}
+	for name := range want.headers {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		got, wanted := r.headers[name], want.headers[name]
+		switch {
+		case wanted == nil:
+			diffs = append(diffs, fmt.Sprintf("unexpected header %s", name))
+		case got == nil:
+			diffs = append(diffs, fmt.Sprintf("missing header %s", name))
+		case strings.Join(got, ", ") != strings.Join(wanted, ", "):
+			diffs = append(diffs, fmt.Sprintf("header %s is %q, want %q", name, strings.Join(got, ", "), strings.Join(wanted, ", ")))
+		}
+	}
+
+	if r.body != want.body {
+		diffs = append(diffs, fmt.Sprintf("body is %q, want %q", r.body, want.body))
+	}
+	return diffs
+}
@@ -0,0 +1,139 @@
+package http2curl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func ExampleParseCurlCommand() {
+	req, err := ParseCurlCommand(`curl 'api.example.com/search' \
+  -u 'gopher:s3cr3t' \
+  -H 'Accept: application/json' \
+  -b 'session=abc' \
+  --data-urlencode 'q=go http' --data-urlencode 'lang=en'`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	fmt.Println(req.Method, req.URL)
+	fmt.Println(req.Header.Get("Authorization"))
+	fmt.Println(req.Header.Get("Cookie"))
+	fmt.Println(req.Header.Get("Content-Type"))
+	fmt.Println(string(body))
+
+	// Output:
+	// POST http://api.example.com/search
+	// Basic Z29waGVyOnMzY3IzdA==
+	// session=abc
+	// application/x-www-form-urlencoded
+	// q=go%20http&lang=en
+}
+
+func ExampleParseCurlCommand_form() {
+	req, _ := ParseCurlCommand(`curl -X PUT -F 'title=Gopher' -F "tags=go,mascot" https://api.example.com/pictures/1`)
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(req.Method, req.URL)
+	fmt.Println(req.FormValue("title"), req.FormValue("tags"))
+
+	// Output:
+	// PUT https://api.example.com/pictures/1
+	// Gopher go,mascot
+}
+
+func ExampleParseCurlScript() {
+	script := `#!/bin/sh
+# create then read an item
+curl -X POST \\
+  -d '{"name": "gopher",
+  "role": "mascot"}' \\
+  https://api.example.com/items
+echo created
+curl https://api.example.com/items/1
+`
+	reqs, _ := ParseCurlScript(script)
+	for _, req := range reqs {
+		fmt.Println(req.Method, req.URL, req.ContentLength)
+	}
+
+	_, err := ParseCurlScript("curl https://example.com\ncurl -d @body.json https://example.com")
+	fmt.Println(err)
+
+	// Output:
+	// POST https://api.example.com/items 38
+	// GET https://api.example.com/items/1 0
+	// http2curl: line 2: body read from the file body.json
+}
+
+func ExampleParseCurlCommand_bundled() {
+	for _, command := range []string{
+		`curl -sX PUT https://example.com/items/1 -d x=1`,
+		`curl -sSLG http://example.com/?a=1 -d b=2`,
+		`curl --data-raw '@gopher' -H 'Content-Type: text/plain' example.com`,
+	} {
+		req, err := ParseCurlCommand(command)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Printf("%s %s %q\n", req.Method, req.URL, body)
+	}
+	_, err := ParseCurlCommand(`curl -d @gopher.txt example.com`)
+	fmt.Println(err)
+
+	// Output:
+	// PUT https://example.com/items/1 "x=1"
+	// GET http://example.com/?a=1&b=2 ""
+	// POST http://example.com "@gopher"
+	// http2curl: body read from the file gopher.txt
+}
+
+func TestParseCurlCommandRoundTrip(t *testing.T) {
+	optionSets := map[string][]Option{
+		"default":  nil,
+		"OutputV2": {WithOutputVersion(OutputV2)},
+		"minimal":  {WithQuoteStyle(QuoteMinimal)},
+		"always":   {WithQuoteStyle(QuoteAlways)},
+	}
+	bodies := []string{"", "name=gopher&age=13", `{"quote": "it's"}`, "@not-a-file", "line 1\r\nline 2\n"}
+	for name, opts := range optionSets {
+		for _, body := range bodies {
+			method := http.MethodPost
+			if body == "" {
+				method = http.MethodGet
+			}
+			req, _ := http.NewRequest(method, "https://example.com/search?q=go&page=2", strings.NewReader(body))
+			req.Header.Set("Content-Type", "text/plain")
+			req.Header.Set("X-Trace", "a\tb 'c' \"d\" $e")
+			command, err := GetCurlCommandWithOptions(req, opts...)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			for _, command := range []string{command.String(), command.MultilineString()} {
+				got, err := ParseCurlCommand(command)
+				if err != nil {
+					t.Errorf("%s: ParseCurlCommand(%s): %v", name, command, err)
+					continue
+				}
+				gotBody, _ := ioutil.ReadAll(got.Body)
+				switch {
+				case got.Method != method:
+					t.Errorf("%s: %s: method is %s, want %s", name, command, got.Method, method)
+				case got.URL.String() != req.URL.String():
+					t.Errorf("%s: %s: URL is %s, want %s", name, command, got.URL, req.URL)
+				case got.Header.Get("X-Trace") != req.Header.Get("X-Trace") || got.Header.Get("Content-Type") != "text/plain":
+					t.Errorf("%s: %s: headers are %v, want %v", name, command, got.Header, req.Header)
+				case string(gotBody) != body:
+					t.Errorf("%s: %s: body is %q, want %q", name, command, gotBody, body)
+				}
+			}
+		}
+	}
+}
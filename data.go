@@ -0,0 +1,52 @@
+package http2curl
+
+import (
+	"errors"
+)
+
+// DataFlag is the curl flag used to send the request body.
+type DataFlag string
+
+const (
+	// DataAuto uses -d, or --data-raw when the body starts with '@' so
+	// that curl does not try to read it from a file. It is the default.
+	DataAuto DataFlag = ""
+	// Data uses -d.
+	Data DataFlag = "-d"
+	// DataRaw uses --data-raw, which never reads from a file.
+	DataRaw DataFlag = "--data-raw"
+	// DataBinary uses --data-binary.
+	DataBinary DataFlag = "--data-binary"
+	// DataASCII uses --data-ascii.
+	DataASCII DataFlag = "--data-ascii"
+)
+
+// errDataFile is returned when the chosen data flag would make curl read
+// the body from a file.
+var errDataFile = errors.New("http2curl: body starts with '@' and would be read from a file, use DataRaw")
+
+// WithDataFlag sets the curl flag used to send the request body.
+func WithDataFlag(flag DataFlag) Option {
+	return func(o *Options) { o.dataFlag = flag }
+}
+
+// dataFlagFor returns the flag to send body with.
+func (o *Options) dataFlagFor(body []byte) (string, error) {
+	if o.upstreamCompat && o.dataFlag == DataAuto {
+		return string(Data), nil
+	}
+	startsWithAt := len(body) > 0 && body[0] == '@'
+	switch o.dataFlag {
+	case DataAuto:
+		if startsWithAt {
+			return string(DataRaw), nil
+		}
+		return string(Data), nil
+	case DataRaw:
+		return string(DataRaw), nil
+	}
+	if startsWithAt {
+		return "", errDataFile
+	}
+	return string(o.dataFlag), nil
+}
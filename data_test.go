@@ -0,0 +1,26 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithDataFlag() {
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader("@me: hello"))
+
+	command, _ := Command(req, nil)
+	fmt.Println(command)
+
+	_, err := Command(req, nil, WithDataFlag(DataBinary))
+	fmt.Println(err)
+
+	req, _ = http.NewRequest("POST", "http://example.com/", strings.NewReader("hello"))
+	command, _ = Command(req, nil, WithDataFlag(DataBinary))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'POST' --data-raw '@me: hello' 'http://example.com/'
+	// http2curl: body starts with '@' and would be read from a file, use DataRaw
+	// curl -X 'POST' --data-binary 'hello' 'http://example.com/'
+}
@@ -0,0 +1,136 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Definition is a request described in JSON, so that requests can be kept
+// in version control and rendered as curl commands:
+//
+//	{
+//	  "method": "POST",
+//	  "url": "https://api.example.com/users",
+//	  "headers": {"Content-Type": "application/json"},
+//	  "body": "{\"name\": \"gopher\"}"
+//	}
+//
+// The body can instead be read from a file with bodyFile. The yamldef
+// package reads definitions in YAML.
+type Definition struct {
+	// Method defaults to GET.
+	Method  string            `yaml:"method" json:"method,omitempty"`
+	URL     string            `yaml:"url" json:"url"`
+	Headers map[string]string `yaml:"headers" json:"headers,omitempty"`
+	Body    string            `yaml:"body" json:"body,omitempty"`
+	// BodyFile is the path of a file holding the body, relative to the
+	// working directory.
+	BodyFile string `yaml:"bodyFile" json:"bodyFile,omitempty"`
+}
+
+// ParseDefinitions reads request definitions in JSON. The input holds a
+// single definition, a list of definitions, or several of them one after
+// the other.
+func ParseDefinitions(r io.Reader) ([]Definition, error) {
+	var defs []Definition
+	err := decodeDocuments(r, func(item json.RawMessage) error {
+		var def Definition
+		if err := json.Unmarshal(item, &def); err != nil {
+			return err
+		}
+		defs = append(defs, def)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid definition: %v", err)
+	}
+	return defs, nil
+}
+
+// decodeDocuments calls decode for each item of r, a JSON input holding a
+// single item, a list of items or several of them one after the other.
+func decodeDocuments(r io.Reader, decode func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var doc json.RawMessage
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		items := []json.RawMessage{doc}
+		if bytes.HasPrefix(doc, []byte("[")) {
+			items = nil
+			if err := json.Unmarshal(doc, &items); err != nil {
+				return err
+			}
+		}
+		for _, item := range items {
+			if err := decode(item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// FromDefinition reads request definitions in JSON, see
+// ParseDefinitions, and returns the requests they describe, ready to be
+// given to GetCurlCommands.
+func FromDefinition(r io.Reader) ([]*http.Request, error) {
+	defs, err := ParseDefinitions(r)
+	if err != nil {
+		return nil, err
+	}
+	reqs := make([]*http.Request, len(defs))
+	for i, def := range defs {
+		req, err := def.Request()
+		if err != nil {
+			return nil, fmt.Errorf("%v (definition %d)", err, i)
+		}
+		reqs[i] = req
+	}
+	return reqs, nil
+}
+
+// Request returns the request described by d.
+func (d Definition) Request() (*http.Request, error) {
+	if d.URL == "" {
+		return nil, errors.New("http2curl: definition has no url")
+	}
+	if d.Body != "" && d.BodyFile != "" {
+		return nil, errors.New("http2curl: definition has both body and bodyFile")
+	}
+
+	var body io.Reader
+	switch {
+	case d.BodyFile != "":
+		b, err := os.ReadFile(d.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("http2curl: definition body: %v", err)
+		}
+		body = bytes.NewReader(b)
+	case d.Body != "":
+		body = strings.NewReader(d.Body)
+	}
+
+	method := strings.ToUpper(d.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequest(method, d.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid definition: %v", err)
+	}
+	for name, value := range d.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
@@ -0,0 +1,44 @@
+package http2curl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleFromDefinition() {
+	definitions := `[
+  {
+    "method": "post",
+    "url": "https://api.example.com/users",
+    "headers": {"Content-Type": "application/json"},
+    "body": "{\"name\": \"gopher\"}"
+  },
+  {"url": "https://api.example.com/users?page=2"}
+]`
+	reqs, err := FromDefinition(strings.NewReader(definitions))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	commands, _ := GetCurlCommands(reqs)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'POST' -d '{"name": "gopher"}' -H 'Content-Type: application/json' 'https://api.example.com/users'
+	// curl -X 'GET' 'https://api.example.com/users?page=2'
+}
+
+func ExampleFromDefinition_single() {
+	reqs, _ := FromDefinition(strings.NewReader(`{"method": "DELETE", "url": "https://api.example.com/users/1"}`))
+	command, _ := GetCurlCommand(reqs[0])
+	fmt.Println(command)
+
+	_, err := FromDefinition(strings.NewReader(`{"method": "PUT"}`))
+	fmt.Println(err)
+
+	// Output:
+	// curl -X 'DELETE' 'https://api.example.com/users/1'
+	// http2curl: definition has no url (definition 0)
+}
@@ -0,0 +1,165 @@
+package http2curl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// docMarkerRe matches the Markdown comment naming the request documented
+// by the next curl command.
+var docMarkerRe = regexp.MustCompile(`^\s*<!--\s*http2curl:\s*(\S+)\s*-->\s*$`)
+
+// RequestLookup returns the request with the given ID, for instance from a
+// Catalog, see Catalog.Lookup, or by calling the constructor the
+// application uses.
+type RequestLookup func(id string) (*http.Request, error)
+
+// Lookup returns the request of the entry with the given ID.
+func (c *Catalog) Lookup(id string) (*http.Request, error) {
+	entry, ok := c.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("http2curl: no catalog entry %s", id)
+	}
+	return entry.Request()
+}
+
+// DocDrift is a curl command of a Markdown document that no longer matches
+// the request it documents.
+type DocDrift struct {
+	// Line is the line of the command in the document, starting at 1.
+	Line int
+	// ID is the ID of the documented request.
+	ID string
+	// Command is the command as documented.
+	Command string
+	// Want is the command generated for the request.
+	Want string
+	// Differences lists how the documented request differs.
+	Differences []string
+	// Err is set when the command could not be checked.
+	Err error
+}
+
+func (d DocDrift) String() string {
+	if d.Err != nil {
+		return fmt.Sprintf("line %d: %s: %v", d.Line, d.ID, d.Err)
+	}
+	return fmt.Sprintf("line %d: %s: %s", d.Line, d.ID, strings.Join(d.Differences, "; "))
+}
+
+// CheckDocs scans a Markdown document for curl commands in fenced code
+// blocks and compares them with the requests they document, returning the
+// commands that drifted. A command is checked when the code block is
+// preceded by a comment naming the request:
+//
+//	<!-- http2curl: users.create -->
+//	```sh
+//	curl -X POST https://api.example.com/users -d '{"name": "gopher"}'
+//	```
+//
+// Commands may span several lines with backslashes and start with a "$ "
+// prompt. They are read as written by hand: unquoted ? and * are read as
+// themselves, and variable references such as $TOKEN as the placeholders
+// ${TOKEN} of WithPlaceholder. The method, URL, headers and body are
+// compared, other flags are ignored. The requests are rendered with opts.
+func CheckDocs(markdown io.Reader, lookup RequestLookup, opts ...Option) ([]DocDrift, error) {
+	var (
+		drifts  []DocDrift
+		id      string // the ID for the next code block
+		fence   string // the fence of the current code block
+		command strings.Builder
+		start   int // the line of the command being read
+	)
+	check := func() {
+		if id != "" && command.Len() > 0 {
+			if drift, ok := checkDocCommand(id, command.String(), lookup, opts); !ok {
+				drift.Line = start
+				drifts = append(drifts, drift)
+			}
+		}
+		command.Reset()
+	}
+
+	scanner := bufio.NewScanner(markdown)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if m := docMarkerRe.FindStringSubmatch(line); m != nil {
+				id = m[1]
+			} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) {
+			check()
+			fence, id = "", ""
+			continue
+		}
+		if command.Len() == 0 {
+			trimmed = strings.TrimPrefix(trimmed, "$ ")
+			if !strings.HasPrefix(trimmed, "curl ") {
+				continue
+			}
+			start = n
+		}
+		command.WriteString(trimmed + "\n")
+		if !strings.HasSuffix(trimmed, `\`) {
+			// the command is complete, only the first one is checked
+			check()
+			id = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return drifts, nil
+}
+
+// checkDocCommand compares the documented command with the request id,
+// and reports whether they match.
+func checkDocCommand(id, command string, lookup RequestLookup, opts []Option) (DocDrift, bool) {
+	drift := DocDrift{ID: id, Command: strings.TrimSpace(command)}
+
+	argv, err := splitPastedShell(command)
+	if err != nil {
+		drift.Err = fmt.Errorf("cannot parse command: %v", err)
+		return drift, false
+	}
+	got, err := parseCurlArgs(argv)
+	if err != nil {
+		drift.Err = fmt.Errorf("cannot parse command: %v", err)
+		return drift, false
+	}
+
+	req, err := lookup(id)
+	if err != nil {
+		drift.Err = err
+		return drift, false
+	}
+	want, err := Command(req, nil, opts...)
+	if err != nil {
+		drift.Err = err
+		return drift, false
+	}
+	drift.Want = want.String()
+	wantArgv, err := splitShell(drift.Want)
+	if err != nil {
+		drift.Err = fmt.Errorf("cannot parse generated command: %v", err)
+		return drift, false
+	}
+	wanted, err := parseCurlArgs(wantArgv)
+	if err != nil {
+		drift.Err = fmt.Errorf("cannot parse generated command: %v", err)
+		return drift, false
+	}
+
+	drift.Differences = got.diff(wanted)
+	return drift, len(drift.Differences) == 0
+}
@@ -0,0 +1,65 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleCheckDocs() {
+	catalog, _ := LoadCatalog(strings.NewReader(exampleCatalog))
+
+	markdown := "# Users API\n" +
+		"\n" +
+		"<!-- http2curl: users.list -->\n" +
+		"```sh\n" +
+		"$ curl 'https://api.example.com/users?page=1'\n" +
+		"```\n" +
+		"\n" +
+		"<!-- http2curl: users.create -->\n" +
+		"```sh\n" +
+		"curl -X POST https://api.example.com/users \\\n" +
+		"  -H 'Content-Type: application/json' \\\n" +
+		"  -d '{\"name\": \"gopher\"}'\n" +
+		"```\n" +
+		"\n" +
+		"<!-- http2curl: users.delete -->\n" +
+		"```sh\n" +
+		"curl -X DELETE https://api.example.com/users/1\n" +
+		"```\n"
+
+	drifts, err := CheckDocs(strings.NewReader(markdown), catalog.Lookup)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, drift := range drifts {
+		fmt.Println(drift)
+	}
+
+	// Output:
+	// line 10: users.create: URL is "https://api.example.com/users", want "https://api.example.com/v2/users"; body is "{\"name\": \"gopher\"}", want "{\"name\": \"gopher\", \"role\": \"admin\"}"
+	// line 17: users.delete: http2curl: no catalog entry users.delete
+}
+
+func ExampleCheckDocs_handWritten() {
+	lookup := func(id string) (*http.Request, error) {
+		req, err := http.NewRequest("GET", "https://api.example.com/users?page=2", nil)
+		req.Header.Set("Authorization", "Bearer 0123456789")
+		return req, err
+	}
+
+	markdown := "<!-- http2curl: users.list -->\n" +
+		"```sh\n" +
+		"curl https://api.example.com/users?page=1 -H \"Authorization: Bearer $TOKEN\"\n" +
+		"```\n"
+
+	drifts, _ := CheckDocs(strings.NewReader(markdown), lookup, WithPlaceholder(regexp.MustCompile(`Bearer (\S+)`), "TOKEN"))
+	for _, drift := range drifts {
+		fmt.Println(drift)
+	}
+
+	// Output:
+	// line 3: users.list: URL is "https://api.example.com/users?page=1", want "https://api.example.com/users?page=2"
+}
@@ -0,0 +1,190 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// fetchForbiddenHeaders are the headers browsers do not let fetch set,
+// besides those starting with Proxy- or Sec-.
+var fetchForbiddenHeaders = map[string]bool{
+	"Accept-Charset":                 true,
+	"Accept-Encoding":                true,
+	"Access-Control-Request-Headers": true,
+	"Access-Control-Request-Method":  true,
+	"Connection":                     true,
+	"Content-Length":                 true,
+	"Cookie":                         true,
+	"Cookie2":                        true,
+	"Date":                           true,
+	"Dnt":                            true,
+	"Expect":                         true,
+	"Host":                           true,
+	"Keep-Alive":                     true,
+	"Origin":                         true,
+	"Referer":                        true,
+	"Te":                             true,
+	"Trailer":                        true,
+	"Transfer-Encoding":              true,
+	"Upgrade":                        true,
+	"Via":                            true,
+}
+
+// GetFetchSnippet returns a JavaScript fetch() call sending req, for
+// browsers and Node.js. JSON bodies are written as object literals passed
+// to JSON.stringify, bodies that are not valid UTF-8 are decoded from
+// base64. The headers browsers forbid, cookies included, are left out and
+// credentials are omitted, so that the snippet sends the same request
+// whatever the cookies of the page. It is generated from the same
+// arguments as the curl command, so options apply alike; placeholders
+// become references to JavaScript variables of the same name, and curl
+// flags, which have no equivalent, are reported to the loss callback.
+func GetFetchSnippet(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		b       strings.Builder
+		method  string
+		target  string
+		headers []string
+		body    string
+		hasBody bool
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			b.WriteString("// " + a.value + "\n")
+			continue
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by fetch")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			name, _, _ := strings.Cut(value.value, ":")
+			canonical := http.CanonicalHeaderKey(name)
+			if fetchForbiddenHeaders[canonical] || strings.HasPrefix(canonical, "Proxy-") || strings.HasPrefix(canonical, "Sec-") {
+				o.loss(LossDropped, "header "+canonical, "forbidden in browsers")
+				continue
+			}
+			headers = append(headers, value.value)
+		case a.value == "--cookie":
+			o.loss(LossDropped, "header Cookie", "forbidden in browsers")
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue:
+			o.loss(LossDropped, "body", "read from a file by curl")
+		case dataFlags[a.value]:
+			body, hasBody = value.value, true
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by fetch")
+		}
+	}
+	if method == "" {
+		method = http.MethodGet
+		if hasBody {
+			method = http.MethodPost
+		}
+	}
+
+	b.WriteString("fetch(" + o.jsString(target) + ", {\n")
+	b.WriteString("  method: " + o.jsString(method) + ",\n")
+	if len(headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for i, h := range headers {
+			name, v, _ := strings.Cut(h, ":")
+			b.WriteString("    " + jsonString(name) + ": " + o.jsString(strings.TrimLeft(v, " ")))
+			if i < len(headers)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("  },\n")
+	}
+	if hasBody {
+		b.WriteString("  body: " + o.jsBody(body) + ",\n")
+	}
+	b.WriteString(`  credentials: "omit"` + "\n")
+	b.WriteString("});")
+	return b.String(), nil
+}
+
+// jsBody returns the JavaScript expression of body.
+func (o *Options) jsBody(body string) string {
+	if !utf8.ValidString(body) {
+		return `Uint8Array.from(atob(` + jsonString(base64.StdEncoding.EncodeToString([]byte(body))) + `), c => c.charCodeAt(0))`
+	}
+	if literal, ok := o.jsLiteral(body); ok {
+		return "JSON.stringify(" + literal + ")"
+	}
+	return o.jsString(body)
+}
+
+// jsLiteral returns the JSON document body as a JavaScript literal, for a
+// property of an object literal, and whether body is such a document.
+func (o *Options) jsLiteral(body string) (string, bool) {
+	// literals are only used when no placeholder would be lost
+	if len(o.segments(body)) > 1 || sniffContentType([]byte(body)) != "application/json" {
+		return "", false
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(strings.TrimSpace(body)), "  ", "  "); err != nil {
+		return "", false
+	}
+	return indented.String(), true
+}
+
+// jsString returns a JavaScript expression for str, placeholders being
+// concatenated as variable references.
+func (o *Options) jsString(str string) string {
+	if len(o.placeholders) == 0 {
+		return jsonString(str)
+	}
+	var parts []string
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			parts = append(parts, seg.text)
+		} else {
+			parts = append(parts, jsonString(seg.text))
+		}
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+// jsonString returns str as a JSON string, which is a valid JavaScript
+// string literal.
+func jsonString(str string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(str)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,38 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleGetFetchSnippet() {
+	req, _ := http.NewRequest("POST", "https://example.com/items", strings.NewReader(`{"name":"gopher","tags":["go"]}`))
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session=abc")
+
+	snippet, _ := GetFetchSnippet(req,
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "token"),
+		WithLossCallback(func(e LossEvent) { fmt.Println(e) }),
+	)
+	fmt.Println(snippet)
+
+	// Output:
+	// header Cookie dropped: forbidden in browsers
+	// fetch("https://example.com/items", {
+	//   method: "POST",
+	//   headers: {
+	//     "Authorization": "Bearer " + token,
+	//     "Content-Type": "application/json"
+	//   },
+	//   body: JSON.stringify({
+	//     "name": "gopher",
+	//     "tags": [
+	//       "go"
+	//     ]
+	//   }),
+	//   credentials: "omit"
+	// });
+}
@@ -0,0 +1,187 @@
+package http2curl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileStore is a Store keeping its data in a directory: captures are
+// appended to a newline delimited JSON file per day, under captures/, and
+// catalog entries to catalog.ndjson, the last record of an entry winning.
+// It is safe for concurrent use within a process.
+type FileStore struct {
+	dir  string
+	opts *Options
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore keeping its data in dir, which is
+// created if needed. Captures are stored at the time told by the clock
+// set with WithClock among opts.
+func NewFileStore(dir string, opts ...Option) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "captures"), 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, opts: newOptions(opts)}, nil
+}
+
+// PutCapture implements Store.
+func (s *FileStore) PutCapture(c *Capture) (string, error) {
+	now, id := newCaptureID(s.opts)
+	stored := StoredCapture{ID: id, Time: now, Capture: c}
+	return id, s.appendRecord(s.captureFile(id), stored)
+}
+
+// GetCapture implements Store.
+func (s *FileStore) GetCapture(id string) (*StoredCapture, error) {
+	if len(id) < len("2006-01-02") {
+		return nil, ErrNotFound
+	}
+	var found *StoredCapture
+	err := s.readCaptures(s.captureFile(id), func(c StoredCapture) bool {
+		if c.ID == id {
+			found = &c
+			return false
+		}
+		return true
+	})
+	if errors.Is(err, os.ErrNotExist) || err == nil && found == nil {
+		return nil, ErrNotFound
+	}
+	return found, err
+}
+
+// ListCaptures implements Store.
+func (s *FileStore) ListCaptures(q CaptureQuery) ([]StoredCapture, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "captures", "*.ndjson"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var captures []StoredCapture
+	for _, file := range files {
+		// files hold a day each, skip those out of range
+		day := strings.TrimSuffix(filepath.Base(file), ".ndjson")
+		if !q.Since.IsZero() && day < q.Since.UTC().Format("2006-01-02") ||
+			!q.Until.IsZero() && day > q.Until.UTC().Format("2006-01-02") {
+			continue
+		}
+		err := s.readCaptures(file, func(c StoredCapture) bool {
+			if q.matches(c) {
+				captures = append(captures, c)
+			}
+			return q.Limit <= 0 || len(captures) < q.Limit
+		})
+		if err != nil {
+			return nil, err
+		}
+		if q.Limit > 0 && len(captures) >= q.Limit {
+			break
+		}
+	}
+	return captures, nil
+}
+
+// PutEntry implements Store.
+func (s *FileStore) PutEntry(entry CatalogEntry) error {
+	return s.appendRecord(filepath.Join(s.dir, "catalog.ndjson"), entry)
+}
+
+// GetEntry implements Store.
+func (s *FileStore) GetEntry(id string) (CatalogEntry, error) {
+	entries, err := s.ListEntries()
+	if err != nil {
+		return CatalogEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.ID() == id {
+			return entry, nil
+		}
+	}
+	return CatalogEntry{}, ErrNotFound
+}
+
+// ListEntries implements Store.
+func (s *FileStore) ListEntries() ([]CatalogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(filepath.Join(s.dir, "catalog.ndjson"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []CatalogEntry
+	index := map[string]int{}
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var entry CatalogEntry
+		if err := dec.Decode(&entry); err != nil {
+			return nil, err
+		}
+		if i, ok := index[entry.ID()]; ok {
+			entries[i] = entry
+			continue
+		}
+		index[entry.ID()] = len(entries)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// captureFile returns the file of the day of the capture with the given
+// ID.
+func (s *FileStore) captureFile(id string) string {
+	return filepath.Join(s.dir, "captures", id[:len("2006-01-02")]+".ndjson")
+}
+
+// appendRecord appends the JSON record of v to file.
+func (s *FileStore) appendRecord(file string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readCaptures calls fn with the captures of file, until it returns false.
+func (s *FileStore) readCaptures(file string, fn func(StoredCapture) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		var c StoredCapture
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return err
+		}
+		if !fn(c) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,40 @@
+package http2curl
+
+// WithTimingWriteOut makes curl print a latency breakdown (DNS lookup,
+// connect, time to first byte and total) after the response.
+func WithTimingWriteOut() Option {
+	return func(o *Options) {
+		o.flags.flag("-w", argValue, `\ndns:%{time_namelookup} connect:%{time_connect} ttfb:%{time_starttransfer} total:%{time_total}\n`)
+	}
+}
+
+// WithSaveResponse makes curl save the response headers to prefix.headers
+// and the response body to prefix.body.
+func WithSaveResponse(prefix string) Option {
+	return func(o *Options) {
+		o.flags.flag("-D", argValue, prefix+".headers")
+		o.flags.flag("-o", argValue, prefix+".body")
+	}
+}
+
+// ForScripting bundles the flags commonly used when running curl from a
+// script: fail on HTTP errors while still printing the body, stay silent
+// except for errors, and retry transient errors, refused connections
+// included, up to 3 times.
+func ForScripting() Option {
+	return func(o *Options) {
+		o.flags.addFlag("--fail-with-body")
+		o.flags.addFlag("-sS")
+		// --retry-connrefused has no effect without --retry
+		if !o.flags.hasFlag("--retry") {
+			o.flags.flag("--retry", argValue, "3")
+		}
+		o.flags.addFlag("--retry-connrefused")
+	}
+}
+
+// ForInteractive bundles the flags commonly used when debugging by hand,
+// currently just verbose output.
+func ForInteractive() Option {
+	return func(o *Options) { o.flags.addFlag("-v") }
+}
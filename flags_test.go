@@ -0,0 +1,46 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithTimingWriteOut() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	command, _ := Command(req, nil, WithTimingWriteOut())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -w '\ndns:%{time_namelookup} connect:%{time_connect} ttfb:%{time_starttransfer} total:%{time_total}\n' 'http://example.com/'
+}
+
+func ExampleWithSaveResponse() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	command, _ := Command(req, nil, WithSaveResponse("incident-42"))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -D 'incident-42.headers' -o 'incident-42.body' 'http://example.com/'
+}
+
+func ExampleForScripting() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	command, _ := Command(req, nil, ForScripting())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' --fail-with-body -sS --retry '3' --retry-connrefused 'http://example.com/'
+}
+
+func ExampleForInteractive() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	command, _ := Command(req, nil, ForInteractive())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -v 'http://example.com/'
+}
@@ -0,0 +1,52 @@
+package http2curl
+
+import (
+	"strconv"
+	"time"
+)
+
+// WithTimeFormat sets how times are rendered in the generated comments.
+// By default they are rendered in UTC with RFC 3339, whatever the local
+// time zone, so that golden files are stable across machines.
+func WithTimeFormat(format func(time.Time) string) Option {
+	return func(o *Options) { o.formatTime = format }
+}
+
+// WithSizeFormat sets how sizes are rendered in the generated comments. By
+// default they are rendered as a plain number of bytes, "1234 bytes",
+// without digit grouping or units that would depend on the environment.
+func WithSizeFormat(format func(bytes int64) string) Option {
+	return func(o *Options) { o.formatSize = format }
+}
+
+// WithTimestamp adds a comment above the command telling when the request
+// was captured, rendered with the time format.
+func WithTimestamp(t time.Time) Option {
+	return func(o *Options) { o.timestamp = t }
+}
+
+// FormatTime is the default time format: RFC 3339 in UTC.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// FormatSize is the default size format: the number of bytes.
+func FormatSize(bytes int64) string {
+	return strconv.FormatInt(bytes, 10) + " bytes"
+}
+
+// timeString renders t with the configured time format.
+func (o *Options) timeString(t time.Time) string {
+	if o.formatTime != nil {
+		return o.formatTime(t)
+	}
+	return FormatTime(t)
+}
+
+// sizeString renders n bytes with the configured size format.
+func (o *Options) sizeString(n int) string {
+	if o.formatSize != nil {
+		return o.formatSize(int64(n))
+	}
+	return FormatSize(int64(n))
+}
@@ -0,0 +1,38 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func ExampleWithTimestamp() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	captured := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+
+	command, _ := Command(req, nil, WithTimestamp(captured))
+	fmt.Println(command)
+
+	// Output:
+	// # captured at 2021-03-04T04:06:07Z
+	// curl -X 'GET' 'http://example.com/'
+}
+
+func ExampleWithSizeFormat() {
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader(strings.Repeat("x", 2048)))
+
+	command, _ := Command(req, nil,
+		WithAnalysis(),
+		WithSizeFormat(func(bytes int64) string { return fmt.Sprintf("%.1f KiB", float64(bytes)/1024) }),
+		WithTimeFormat(func(t time.Time) string { return t.Format(time.Kitchen) }),
+		WithTimestamp(time.Date(2021, 3, 4, 17, 6, 7, 0, time.UTC)),
+		WithDataFlag(DataBinary),
+	)
+	fmt.Println(strings.Join((*command)[:3], ""))
+
+	// Output:
+	// # captured at 5:06PM
+	// # request size: 2.0 KiB (headers 0.0 KiB, body 2.0 KiB)
+	// # cookies: 0
+}
@@ -1,3 +1,5 @@
 module github.com/gdey/http2curl/v2
 
-go 1.13
+go 1.18
+
+require gopkg.in/yaml.v3 v3.0.1
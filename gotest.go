@@ -0,0 +1,195 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// goTestSkipHeaders are the response headers left to the test server.
+var goTestSkipHeaders = map[string]bool{
+	"Connection": true, "Content-Length": true, "Date": true, "Transfer-Encoding": true,
+}
+
+// ToGoTest returns a _test.go file reproducing req as a Go test, with its
+// curl command in the comment of the test, so that a captured request
+// becomes a regression test. An httptest server checks that it receives
+// the method, path, headers and body of req, then answers resp, or 200
+// when resp is nil; the test sends req to it and checks the status and
+// body of the response. Replacing the handler of the server by the one
+// under test, or its URL by the service, tests them instead. The body of
+// resp is read and replaced by an in-memory copy. It is generated from
+// the same arguments as the curl command, so options apply alike:
+// redacted headers stay redacted, placeholders are read with os.Getenv,
+// and curl flags without an equivalent are reported to the loss callback.
+func ToGoTest(req *http.Request, resp *http.Response, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	command := o.render(args)
+	r := o.checkRequest(args, "Go", nil)
+	if r.bodyFile != "" {
+		o.loss(LossDropped, "body", "the test does not read the file "+r.bodyFile)
+	}
+	u, err := url.Parse(r.target)
+	if err != nil {
+		return "", err
+	}
+
+	status, respBody := http.StatusOK, []byte(nil)
+	var respHeaders [][2]string
+	if resp != nil {
+		status = resp.StatusCode
+		if resp.Body != nil && resp.Body != http.NoBody {
+			respBody, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+			if err != nil {
+				return "", err
+			}
+		}
+		names := make([]string, 0, len(resp.Header))
+		for name := range resp.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if goTestSkipHeaders[http.CanonicalHeaderKey(name)] {
+				continue
+			}
+			for _, v := range resp.Header[name] {
+				if o.redactHeaders[http.CanonicalHeaderKey(name)] {
+					v = redactedValue
+				}
+				respHeaders = append(respHeaders, [2]string{name, v})
+			}
+		}
+	}
+
+	var b strings.Builder
+	name := "Test"
+	for _, word := range strings.Split(checkName(req), "_") {
+		if word != "" {
+			name += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	fmt.Fprintf(&b, "// %s reproduces:\n//\n", name)
+	for _, line := range strings.Split(strings.TrimSuffix(command.String(), "\n"), "\n") {
+		b.WriteString("//\t" + goComment(line) + "\n")
+	}
+	fmt.Fprintf(&b, "func %s(t *testing.T) {\n", name)
+
+	b.WriteString("\tsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n")
+	fmt.Fprintf(&b, "\t\tif r.Method != %s {\n\t\t\tt.Errorf(\"method = %%s, want %%s\", r.Method, %s)\n\t\t}\n", goString(r.method), goString(r.method))
+	requestURI := o.goString(u.RequestURI())
+	fmt.Fprintf(&b, "\t\tif got := r.URL.RequestURI(); got != %s {\n\t\t\tt.Errorf(\"request URI = %%s, want %%s\", got, %s)\n\t\t}\n", requestURI, requestURI)
+	for _, h := range r.headers {
+		value := o.goString(h[1])
+		fmt.Fprintf(&b, "\t\tif got := r.Header.Get(%s); got != %s {\n\t\t\tt.Errorf(\"header %%s = %%q, want %%q\", %s, got, %s)\n\t\t}\n", goString(h[0]), value, goString(h[0]), value)
+	}
+	if r.hasBody && r.bodyFile == "" {
+		body := o.goString(r.body)
+		fmt.Fprintf(&b, "\t\tif body, _ := io.ReadAll(r.Body); string(body) != %s {\n\t\t\tt.Errorf(\"body = %%q, want %%q\", body, %s)\n\t\t}\n", body, body)
+	}
+	for _, h := range respHeaders {
+		fmt.Fprintf(&b, "\t\tw.Header().Add(%s, %s)\n", goString(h[0]), goString(h[1]))
+	}
+	fmt.Fprintf(&b, "\t\tw.WriteHeader(%d)\n", status)
+	if len(respBody) > 0 {
+		fmt.Fprintf(&b, "\t\tio.WriteString(w, %s)\n", goString(string(respBody)))
+	}
+	b.WriteString("\t}))\n\tdefer srv.Close()\n\n")
+
+	body := "nil"
+	if r.hasBody && r.bodyFile == "" {
+		body = "strings.NewReader(" + o.goString(r.body) + ")"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, srv.URL+%s, %s)\n", goString(r.method), requestURI, body)
+	b.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	for _, h := range r.headers {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", goString(h[0]), o.goString(h[1]))
+	}
+	b.WriteString("\tresp, err := srv.Client().Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n\tdefer resp.Body.Close()\n\n")
+	fmt.Fprintf(&b, "\tif resp.StatusCode != %d {\n\t\tt.Errorf(\"status = %%d, want %d\", resp.StatusCode)\n\t}\n", status, status)
+	switch {
+	case utf8.Valid(respBody):
+		fmt.Fprintf(&b, "\tif body, _ := io.ReadAll(resp.Body); string(body) != %s {\n\t\tt.Errorf(\"body = %%q, want %%q\", body, %s)\n\t}\n", goString(string(respBody)), goString(string(respBody)))
+	default:
+		fmt.Fprintf(&b, "\tif body, _ := io.ReadAll(resp.Body); len(body) != %d {\n\t\tt.Errorf(\"body of %%d bytes, want %d\", len(body))\n\t}\n", len(respBody), len(respBody))
+	}
+	b.WriteString("}\n")
+
+	var file strings.Builder
+	file.WriteString("package replay_test\n\nimport (\n")
+	imports := []string{"io", "net/http", "net/http/httptest", "testing"}
+	if r.hasBody && r.bodyFile == "" {
+		imports = append(imports, "strings")
+	}
+	if strings.Contains(b.String(), "os.Getenv(") {
+		imports = append(imports, "os")
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&file, "\t%q\n", path)
+	}
+	file.WriteString(")\n\n" + b.String())
+	return file.String(), nil
+}
+
+// goString returns a Go expression for str, placeholders being read from
+// the environment with os.Getenv.
+func (o *Options) goString(str string) string {
+	if len(o.placeholders) == 0 {
+		return goString(str)
+	}
+	var parts []string
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			parts = append(parts, "os.Getenv("+goString(seg.text)+")")
+		} else {
+			parts = append(parts, goString(seg.text))
+		}
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goComment returns line with the bytes Go does not accept in a comment,
+// invalid UTF-8, NUL and byte order marks, and the other control
+// characters but tab, escaped as in a string literal.
+func goComment(line string) string {
+	var b strings.Builder
+	for i, r := range line {
+		switch {
+		case r == utf8.RuneError && strings.HasPrefix(line[i:], "\xef\xbf\xbd"):
+			b.WriteRune(r)
+		case r == utf8.RuneError:
+			fmt.Fprintf(&b, `\x%02x`, line[i])
+		case !isControl(r) && r != '\ufeff':
+			b.WriteRune(r)
+		default:
+			b.WriteString(strings.Trim(strconv.QuoteRune(r), "'"))
+		}
+	}
+	return b.String()
+}
+
+// goString returns str as a Go string literal, raw when it holds double
+// quotes and can be.
+func goString(str string) string {
+	if strings.ContainsRune(str, '"') && strconv.CanBackquote(str) {
+		return "`" + str + "`"
+	}
+	return strconv.Quote(str)
+}
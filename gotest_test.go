@@ -0,0 +1,123 @@
+package http2curl
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func ExampleToGoTest() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/items?draft=1", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc123")
+	resp := &http.Response{
+		StatusCode: http.StatusConflict,
+		Header:     http.Header{"Content-Type": {"application/json"}, "Date": {"Mon, 01 Jan 2024 00:00:00 GMT"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":"exists"}`)),
+	}
+
+	test, _ := ToGoTest(req, resp, WithRedactedHeaders())
+	fmt.Print(test)
+
+	// Output:
+	// package replay_test
+	//
+	// import (
+	// 	"io"
+	// 	"net/http"
+	// 	"net/http/httptest"
+	// 	"strings"
+	// 	"testing"
+	// )
+	//
+	// // TestPostItems reproduces:
+	// //
+	// //	curl -X 'POST' -d '{"name":"gopher"}' -H 'Authorization: REDACTED' -H 'Content-Type: application/json' 'https://api.example.com/items?draft=1'
+	// func TestPostItems(t *testing.T) {
+	// 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// 		if r.Method != "POST" {
+	// 			t.Errorf("method = %s, want %s", r.Method, "POST")
+	// 		}
+	// 		if got := r.URL.RequestURI(); got != "/items?draft=1" {
+	// 			t.Errorf("request URI = %s, want %s", got, "/items?draft=1")
+	// 		}
+	// 		if got := r.Header.Get("Authorization"); got != "REDACTED" {
+	// 			t.Errorf("header %s = %q, want %q", "Authorization", got, "REDACTED")
+	// 		}
+	// 		if got := r.Header.Get("Content-Type"); got != "application/json" {
+	// 			t.Errorf("header %s = %q, want %q", "Content-Type", got, "application/json")
+	// 		}
+	// 		if body, _ := io.ReadAll(r.Body); string(body) != `{"name":"gopher"}` {
+	// 			t.Errorf("body = %q, want %q", body, `{"name":"gopher"}`)
+	// 		}
+	// 		w.Header().Add("Content-Type", "application/json")
+	// 		w.WriteHeader(409)
+	// 		io.WriteString(w, `{"error":"exists"}`)
+	// 	}))
+	// 	defer srv.Close()
+	//
+	// 	req, err := http.NewRequest("POST", srv.URL+"/items?draft=1", strings.NewReader(`{"name":"gopher"}`))
+	// 	if err != nil {
+	// 		t.Fatal(err)
+	// 	}
+	// 	req.Header.Set("Authorization", "REDACTED")
+	// 	req.Header.Set("Content-Type", "application/json")
+	// 	resp, err := srv.Client().Do(req)
+	// 	if err != nil {
+	// 		t.Fatal(err)
+	// 	}
+	// 	defer resp.Body.Close()
+	//
+	// 	if resp.StatusCode != 409 {
+	// 		t.Errorf("status = %d, want 409", resp.StatusCode)
+	// 	}
+	// 	if body, _ := io.ReadAll(resp.Body); string(body) != `{"error":"exists"}` {
+	// 		t.Errorf("body = %q, want %q", body, `{"error":"exists"}`)
+	// 	}
+	// }
+}
+
+func ExampleToGoTest_binaryBody() {
+	req, _ := http.NewRequest("PUT", "http://example.com/blob", strings.NewReader("\x08\xff\xfe\x00"))
+
+	test, _ := ToGoTest(req, nil)
+	for _, line := range strings.Split(test, "\n") {
+		if strings.HasPrefix(line, "//\t") || strings.Contains(line, "NewRequest") {
+			fmt.Println(line)
+		}
+	}
+
+	// Output:
+	// //	curl -X 'PUT' -d '\b\xff\xfe\x00' 'http://example.com/blob'
+	// 	req, err := http.NewRequest("PUT", srv.URL+"/blob", strings.NewReader("\b\xff\xfe\x00"))
+}
+
+func TestToGoTestCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type checks against the sources of the standard library")
+	}
+	fset := token.NewFileSet()
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	for _, body := range []string{`{"name":"gopher"}`, "\x08\xff\xfe\x00", "a\ufeffb\r\n"} {
+		req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(body))
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}
+		test, err := ToGoTest(req, resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parser.ParseFile(fset, "replay_test.go", test, 0)
+		if err != nil {
+			t.Fatalf("body %q: %v", body, err)
+		}
+		if _, err := conf.Check("replay_test", fset, []*ast.File{f}, nil); err != nil {
+			t.Errorf("body %q: %v", body, err)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR is a HTTP Archive 1.2 file, to be encoded as JSON.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR file.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator names the application that wrote a HAR file.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a request and its response.
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	// Tags are the tags of the request, see WithTag, as a custom field.
+	Tags map[string]string `json:"_tags,omitempty"`
+}
+
+// HARRequest is the request of a HAREntry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is the response of a HAREntry.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header or query parameter.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie is a cookie sent with a request or set by a response.
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// HARPostData is the body of a HARRequest. Params holds the fields of
+// form bodies.
+type HARPostData struct {
+	MimeType string     `json:"mimeType"`
+	Text     string     `json:"text"`
+	Params   []HARParam `json:"params,omitempty"`
+}
+
+// HARParam is a field of a form body.
+type HARParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// HARContent is the body of a HARResponse. Bodies that are not valid
+// UTF-8 are base64 encoded.
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// HARTimings are the timings of a HAREntry, all zero as they are not
+// known.
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHAR returns a HAR file holding entries, created by http2curl.
+func NewHAR(entries ...HAREntry) *HAR {
+	if entries == nil {
+		entries = []HAREntry{}
+	}
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "http2curl", Version: "2"},
+		Entries: entries,
+	}}
+}
+
+// ToHAREntry returns the HAR entry of req and its response resp, which
+// may be nil when there is none, for browser devtools and other HAR
+// tooling. The request is generated from the same arguments as the curl
+// command, so options apply alike; placeholders are written as ${NAME}
+// and curl flags are reported to the loss callback. The body of resp is
+// read and replaced by an in-memory copy. The entry starts at the time
+// set by WithTimestamp, or now.
+func ToHAREntry(req *http.Request, resp *http.Response, opts ...Option) (*HAREntry, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// HAR requires arrays, even empty
+	var (
+		method      string
+		target      string
+		headers     = []HARNameValue{}
+		cookies     = []HARCookie{}
+		postData    *HARPostData
+		contentType string
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by HAR")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			name, v, _ := strings.Cut(value.value, ":")
+			v = o.harString(strings.TrimLeft(v, " "))
+			if strings.EqualFold(name, "Content-Type") {
+				contentType = v
+			}
+			headers = append(headers, HARNameValue{Name: name, Value: v})
+		case a.value == "--cookie":
+			v := o.harString(value.value)
+			headers = append(headers, HARNameValue{Name: "Cookie", Value: v})
+			for _, kv := range strings.Split(v, ";") {
+				name, v, _ := strings.Cut(strings.TrimSpace(kv), "=")
+				cookies = append(cookies, HARCookie{Name: name, Value: v})
+			}
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			o.loss(LossDropped, "body", "HAR cannot refer to the file "+value.value[1:])
+		case dataFlags[a.value]:
+			text := value.value
+			if !utf8.ValidString(text) {
+				o.loss(LossRewritten, "body", "not valid UTF-8, HAR request bodies are text")
+			}
+			postData = &HARPostData{Text: o.harString(text)}
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by HAR")
+		}
+	}
+	if method == "" {
+		method = http.MethodGet
+		if postData != nil {
+			method = http.MethodPost
+		}
+	}
+	bodySize := 0
+	if postData != nil {
+		bodySize = len(postData.Text)
+		postData.MimeType = contentType
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if fields, ok := splitQuery(postData.Text); ok && mediaType == "application/x-www-form-urlencoded" {
+			for _, kv := range fields {
+				postData.Params = append(postData.Params, HARParam{Name: kv[0], Value: kv[1]})
+			}
+		}
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	query := []HARNameValue{}
+	if fields, ok := splitQuery(u.RawQuery); ok {
+		for _, kv := range fields {
+			query = append(query, HARNameValue{Name: kv[0], Value: o.harString(kv[1])})
+		}
+	}
+
+	started := o.timestamp
+	if started.IsZero() {
+		started = o.now()
+	}
+	entry := &HAREntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Tags:            o.tagsFor(req.Context()),
+		Request: HARRequest{
+			Method:      method,
+			URL:         o.harString(target),
+			HTTPVersion: harVersion(req.Proto),
+			Cookies:     cookies,
+			Headers:     headers,
+			QueryString: query,
+			PostData:    postData,
+			HeadersSize: -1,
+			BodySize:    bodySize,
+		},
+		Response: HARResponse{
+			HTTPVersion: harVersion(""),
+			Cookies:     []HARCookie{},
+			Headers:     []HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+	if resp != nil {
+		if err := o.harResponse(&entry.Response, resp); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// harResponse fills r from resp, redacting headers as requested.
+func (o *Options) harResponse(r *HARResponse, resp *http.Response) error {
+	r.Status = resp.StatusCode
+	r.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)))
+	if r.StatusText == "" {
+		r.StatusText = http.StatusText(resp.StatusCode)
+	}
+	r.HTTPVersion = harVersion(resp.Proto)
+
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		redacted := o.redactHeaders[http.CanonicalHeaderKey(name)]
+		for _, v := range resp.Header[name] {
+			if redacted {
+				v = redactedValue
+			}
+			r.Headers = append(r.Headers, HARNameValue{Name: name, Value: v})
+		}
+	}
+	for _, c := range resp.Cookies() {
+		cookie := HARCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+		if o.redactHeaders["Set-Cookie"] {
+			cookie.Value = redactedValue
+		}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+		}
+		r.Cookies = append(r.Cookies, cookie)
+	}
+	r.RedirectURL = resp.Header.Get("Location")
+
+	r.Content.MimeType = resp.Header.Get("Content-Type")
+	if resp.Body == nil || resp.Body == http.NoBody {
+		r.BodySize = 0
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.BodySize = len(body)
+	r.Content.Size = len(body)
+	if utf8.Valid(body) {
+		r.Content.Text = string(body)
+	} else {
+		r.Content.Text = base64.StdEncoding.EncodeToString(body)
+		r.Content.Encoding = "base64"
+	}
+	return nil
+}
+
+// FromHAR reads a HAR file, as saved by browser devtools or proxies, and
+// returns the requests of its entries, ready to be given to
+// GetCurlCommands. See HARRequest.HTTPRequest.
+func FromHAR(r io.Reader) ([]*http.Request, error) {
+	har, err := readHAR(r)
+	if err != nil {
+		return nil, err
+	}
+	reqs := make([]*http.Request, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		req, err := entry.Request.HTTPRequest()
+		if err != nil {
+			return nil, fmt.Errorf("%v (entry %d)", err, i)
+		}
+		reqs[i] = req
+	}
+	return reqs, nil
+}
+
+// readHAR decodes the HAR file read from r.
+func readHAR(r io.Reader) (*HAR, error) {
+	var har HAR
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("http2curl: invalid HAR file: %v", err)
+	}
+	return &har, nil
+}
+
+// HTTPRequest returns the request r describes. The pseudo-headers of HTTP/2, such as
+// :authority, and Content-Length are left out, the Host header sets the
+// Host of the request. Form bodies saved as params without text are
+// encoded back.
+func (r HARRequest) HTTPRequest() (*http.Request, error) {
+	var body io.Reader
+	if data := r.PostData; data != nil {
+		text := data.Text
+		if text == "" && len(data.Params) > 0 {
+			form := url.Values{}
+			for _, p := range data.Params {
+				form.Add(p.Name, p.Value)
+			}
+			text = form.Encode()
+		}
+		body = strings.NewReader(text)
+	}
+	req, err := http.NewRequest(r.Method, r.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid HAR request: %v", err)
+	}
+	for _, h := range r.Headers {
+		switch {
+		case strings.HasPrefix(h.Name, ":"), strings.EqualFold(h.Name, "Content-Length"):
+		case strings.EqualFold(h.Name, "Host"):
+			req.Host = h.Value
+		default:
+			req.Header.Add(h.Name, h.Value)
+		}
+	}
+	if data := r.PostData; data != nil && data.MimeType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", data.MimeType)
+	}
+	return req, nil
+}
+
+// HARScript reads a HAR file and returns a bash script of the curl
+// commands of its entries, in order, each annotated with when it was
+// sent and the response it got, so that a session saved from a browser
+// can be replayed without typing the commands. Entries that fail are
+// left out of the script and reported in a *MultiError.
+func HARScript(r io.Reader, opts ...Option) (string, error) {
+	har, err := readHAR(r)
+	if err != nil {
+		return "", err
+	}
+	var (
+		b    strings.Builder
+		errs MultiError
+	)
+	b.WriteString("#!/usr/bin/env bash\n")
+	source := "a HAR file"
+	if creator := har.Log.Creator; creator.Name != "" {
+		source = strings.TrimSpace("a HAR file of " + creator.Name + " " + creator.Version)
+	}
+	fmt.Fprintf(&b, "# %d request(s) from %s\n", len(har.Log.Entries), source)
+	for i, entry := range har.Log.Entries {
+		req, err := entry.Request.HTTPRequest()
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		command, err := Command(req, nil, opts...)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		fmt.Fprintf(&b, "\n# %d. %s %s", i+1, entry.Request.Method, entry.Request.URL)
+		if entry.StartedDateTime != "" {
+			b.WriteString(" at " + entry.StartedDateTime)
+		}
+		if status := entry.Response.Status; status != 0 {
+			fmt.Fprintf(&b, ", answered %d %s in %s ms", status, entry.Response.StatusText, strconv.FormatFloat(entry.Time, 'f', -1, 64))
+		} else {
+			b.WriteString(", not answered")
+		}
+		b.WriteString("\n" + command.String() + "\n")
+	}
+	return b.String(), errs.errOrNil()
+}
+
+// harVersion returns the HTTP version of a HAR request or response.
+func harVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// harString returns str with placeholders as ${NAME} references.
+func (o *Options) harString(str string) string {
+	if len(o.placeholders) == 0 {
+		return str
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("${" + seg.text + "}")
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func ExampleToHAREntry() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/login?next=%2Fhome", strings.NewReader("user=gopher&pass=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	resp := &http.Response{
+		Status:     "303 See Other",
+		StatusCode: http.StatusSeeOther,
+		Proto:      "HTTP/1.1",
+		Header: http.Header{
+			"Location":   {"/home"},
+			"Set-Cookie": {"session=xyz; Path=/; HttpOnly"},
+		},
+		Body: http.NoBody,
+	}
+
+	entry, _ := ToHAREntry(req, resp,
+		WithRedactedHeaders("Authorization", "Set-Cookie"),
+		WithTimestamp(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	enc.Encode(NewHAR(*entry))
+
+	// Output:
+	// {
+	//   "log": {
+	//     "version": "1.2",
+	//     "creator": {
+	//       "name": "http2curl",
+	//       "version": "2"
+	//     },
+	//     "entries": [
+	//       {
+	//         "startedDateTime": "2024-03-01T12:00:00Z",
+	//         "time": 0,
+	//         "request": {
+	//           "method": "POST",
+	//           "url": "https://api.example.com/login?next=%2Fhome",
+	//           "httpVersion": "HTTP/1.1",
+	//           "cookies": [],
+	//           "headers": [
+	//             {
+	//               "name": "Authorization",
+	//               "value": "REDACTED"
+	//             },
+	//             {
+	//               "name": "Content-Type",
+	//               "value": "application/x-www-form-urlencoded"
+	//             }
+	//           ],
+	//           "queryString": [
+	//             {
+	//               "name": "next",
+	//               "value": "/home"
+	//             }
+	//           ],
+	//           "postData": {
+	//             "mimeType": "application/x-www-form-urlencoded",
+	//             "text": "user=gopher&pass=secret",
+	//             "params": [
+	//               {
+	//                 "name": "user",
+	//                 "value": "gopher"
+	//               },
+	//               {
+	//                 "name": "pass",
+	//                 "value": "secret"
+	//               }
+	//             ]
+	//           },
+	//           "headersSize": -1,
+	//           "bodySize": 23
+	//         },
+	//         "response": {
+	//           "status": 303,
+	//           "statusText": "See Other",
+	//           "httpVersion": "HTTP/1.1",
+	//           "cookies": [
+	//             {
+	//               "name": "session",
+	//               "value": "REDACTED",
+	//               "path": "/",
+	//               "httpOnly": true
+	//             }
+	//           ],
+	//           "headers": [
+	//             {
+	//               "name": "Location",
+	//               "value": "/home"
+	//             },
+	//             {
+	//               "name": "Set-Cookie",
+	//               "value": "REDACTED"
+	//             }
+	//           ],
+	//           "content": {
+	//             "size": 0,
+	//             "mimeType": ""
+	//           },
+	//           "redirectURL": "/home",
+	//           "headersSize": -1,
+	//           "bodySize": 0
+	//         },
+	//         "cache": {},
+	//         "timings": {
+	//           "send": 0,
+	//           "wait": 0,
+	//           "receive": 0
+	//         }
+	//       }
+	//     ]
+	//   }
+	// }
+}
+
+func ExampleHARScript() {
+	har := `{"log": {"version": "1.2", "creator": {"name": "Firefox", "version": "115.0"}, "entries": [
+  {
+    "startedDateTime": "2024-03-01T12:00:00.000Z",
+    "time": 84.5,
+    "request": {
+      "method": "POST",
+      "url": "https://api.example.com/login",
+      "headers": [{"name": ":authority", "value": "api.example.com"}, {"name": "content-length", "value": "11"}],
+      "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "gopher"}]}
+    },
+    "response": {"status": 303, "statusText": "See Other"}
+  },
+  {
+    "startedDateTime": "2024-03-01T12:00:00.100Z",
+    "request": {"method": "GET", "url": "https://api.example.com/home", "headers": [{"name": "cookie", "value": "session=xyz"}]},
+    "response": {"status": 0}
+  }
+]}}`
+
+	script, err := HARScript(strings.NewReader(har))
+	fmt.Print(script)
+	fmt.Println(err)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// # 2 request(s) from a HAR file of Firefox 115.0
+	//
+	// # 1. POST https://api.example.com/login at 2024-03-01T12:00:00.000Z, answered 303 See Other in 84.5 ms
+	// curl -X 'POST' -d 'user=gopher' -H 'Content-Type: application/x-www-form-urlencoded' 'https://api.example.com/login'
+	//
+	// # 2. GET https://api.example.com/home at 2024-03-01T12:00:00.100Z, not answered
+	// curl -X 'GET' -H 'Cookie: session=xyz' 'https://api.example.com/home'
+	// <nil>
+}
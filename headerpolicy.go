@@ -0,0 +1,135 @@
+package http2curl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// InvalidHeaderPolicy tells how headers holding invalid UTF-8 or
+// forbidden characters are rendered, see WithInvalidHeaderPolicy.
+type InvalidHeaderPolicy int
+
+const (
+	// InvalidHeaderKeep renders headers as they are, control characters
+	// in values being written with $'...' escapes from OutputV3 on. Names
+	// curl would misread, starting with @ or holding a colon or a control
+	// character, are an error.
+	InvalidHeaderKeep InvalidHeaderPolicy = iota
+	// InvalidHeaderPercentEncode percent-encodes the invalid bytes.
+	InvalidHeaderPercentEncode
+	// InvalidHeaderReplace replaces the invalid bytes with U+FFFD in
+	// values, and with _ in names.
+	InvalidHeaderReplace
+	// InvalidHeaderError makes headers with invalid bytes an error
+	// telling the header and the offending byte.
+	InvalidHeaderError
+)
+
+func (p InvalidHeaderPolicy) String() string {
+	switch p {
+	case InvalidHeaderKeep:
+		return "keep"
+	case InvalidHeaderPercentEncode:
+		return "percent-encode"
+	case InvalidHeaderReplace:
+		return "replace"
+	case InvalidHeaderError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// WithInvalidHeaderPolicy sets how headers holding invalid bytes are
+// rendered, as curl rejects them, or sends them mangled, with errors
+// hard to relate to the header. Names are invalid outside of the token
+// characters of RFC 9110, values when they are not valid UTF-8 or hold
+// control characters, tabs aside. They are kept as they are by default.
+func WithInvalidHeaderPolicy(policy InvalidHeaderPolicy) Option {
+	return func(o *Options) { o.invalidHeaderPolicy = policy }
+}
+
+// applyHeaderPolicy returns the name and values of the header name with
+// the values values, rewritten according to the invalid header policy.
+func (o *Options) applyHeaderPolicy(name string, values []string) (string, []string, error) {
+	if o.invalidHeaderPolicy == InvalidHeaderKeep {
+		return name, values, nil
+	}
+	if i := invalidHeaderByte(name, isTokenChar); i >= 0 {
+		if o.invalidHeaderPolicy == InvalidHeaderError {
+			return "", nil, fmt.Errorf("http2curl: invalid byte %q at %d in header name %q", name[i], i, name)
+		}
+		fixed := o.fixHeaderBytes(name, isTokenChar, "_")
+		o.loss(LossRewritten, "header "+name, "invalid name "+o.headerFix()+" to "+fixed)
+		name = fixed
+	}
+	var fixed []string
+	for j, v := range values {
+		i := invalidHeaderByte(v, isValueChar)
+		if i < 0 {
+			continue
+		}
+		if o.invalidHeaderPolicy == InvalidHeaderError {
+			return "", nil, fmt.Errorf("http2curl: invalid byte %q at %d in header %s", v[i], i, name)
+		}
+		if fixed == nil {
+			fixed = append([]string{}, values...)
+		}
+		fixed[j] = o.fixHeaderBytes(v, isValueChar, "\uFFFD")
+	}
+	if fixed == nil {
+		return name, values, nil
+	}
+	o.loss(LossRewritten, "header "+name, "invalid bytes "+o.headerFix())
+	return name, fixed, nil
+}
+
+// headerFix tells how invalid header bytes are fixed.
+func (o *Options) headerFix() string {
+	if o.invalidHeaderPolicy == InvalidHeaderPercentEncode {
+		return "percent-encoded"
+	}
+	return "replaced"
+}
+
+// invalidHeaderByte returns the index of the first byte of s that is not
+// valid, part of an invalid UTF-8 sequence or rejected by valid, or -1.
+func invalidHeaderByte(s string, valid func(r rune) bool) int {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 || !valid(r) {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// fixHeaderBytes returns s with its invalid bytes percent-encoded or
+// replaced by replacement, according to the invalid header policy.
+func (o *Options) fixHeaderBytes(s string, valid func(r rune) bool, replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case !(r == utf8.RuneError && size == 1) && valid(r):
+			b.WriteString(s[i : i+size])
+		case o.invalidHeaderPolicy == InvalidHeaderPercentEncode:
+			for _, c := range []byte(s[i : i+size]) {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		default:
+			b.WriteString(replacement)
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isTokenChar reports whether r may appear in a header name.
+func isTokenChar(r rune) bool {
+	return r < utf8.RuneSelf && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r))
+}
+
+// isValueChar reports whether r may appear in a header value.
+func isValueChar(r rune) bool { return !isControl(r) }
@@ -0,0 +1,26 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithInvalidHeaderPolicy() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header["X-Name"] = []string{"caf\xe9\r\n"}
+	req.Header["X Trace"] = []string{"1"}
+
+	for _, policy := range []InvalidHeaderPolicy{InvalidHeaderPercentEncode, InvalidHeaderReplace, InvalidHeaderError} {
+		command, err := Command(req, nil, WithInvalidHeaderPolicy(policy))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'GET' -H 'X%20Trace: 1' -H 'X-Name: caf%E9%0D%0A' 'http://example.com/'
+	// curl -X 'GET' -H 'X_Trace: 1' -H 'X-Name: caf���' 'http://example.com/'
+	// http2curl: invalid byte ' ' at 1 in header name "X Trace"
+}
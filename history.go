@@ -0,0 +1,60 @@
+package http2curl
+
+import (
+	"net/http"
+)
+
+// sensitiveHeaders are the headers known to carry credentials.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"X-Amz-Security-Token",
+}
+
+// sensitiveParams are the query parameters known to carry credentials,
+// including those of AWS and Google Cloud pre-signed URLs.
+var sensitiveParams = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"key",
+	"password",
+	"secret",
+	"signature",
+	"token",
+	"X-Amz-Credential",
+	"X-Amz-Security-Token",
+	"X-Amz-Signature",
+	"X-Goog-Credential",
+	"X-Goog-Signature",
+}
+
+// WithLeadingSpace prefixes the command with a space, so shells configured
+// with HISTCONTROL=ignorespace (bash) or HIST_IGNORE_SPACE (zsh) keep it out
+// of their history.
+func WithLeadingSpace() Option {
+	return func(o *Options) { o.leadingSpace = true }
+}
+
+// WithHistoryComment appends a "# contains credentials" comment to
+// commands sending credentials, in headers or in the URL, as a reminder
+// not to leave them in the shell history.
+func WithHistoryComment() Option {
+	return func(o *Options) { o.historyComment = true }
+}
+
+// hasCredentials reports whether req sends credentials.
+func hasCredentials(req *http.Request) bool {
+	if req.URL.User != nil {
+		return true
+	}
+	for _, name := range sensitiveHeaders {
+		if req.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
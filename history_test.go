@@ -0,0 +1,22 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithHistoryComment() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	command, _ := Command(req, nil, WithLeadingSpace(), WithHistoryComment())
+	fmt.Printf("%q\n", command.String())
+
+	req, _ = http.NewRequest("GET", "http://example.com/public", nil)
+	command, _ = Command(req, nil, WithLeadingSpace(), WithHistoryComment())
+	fmt.Printf("%q\n", command.String())
+
+	// Output:
+	// " curl -X 'GET' -H 'Authorization: Bearer token' 'http://example.com/' # contains credentials"
+	// " curl -X 'GET' 'http://example.com/public'"
+}
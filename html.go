@@ -0,0 +1,122 @@
+package http2curl
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// htmlToken is one escaped argument with its highlighting class.
+type htmlToken struct {
+	Class string
+	Text  string
+}
+
+// htmlCommand is a rendered command as shown in the HTML export.
+type htmlCommand struct {
+	Tokens []htmlToken
+	Plain  string
+}
+
+// htmlGroup holds the commands sent to a single host.
+type htmlGroup struct {
+	Host     string
+	Commands []htmlCommand
+}
+
+var htmlClasses = map[argKind]string{
+	argComment:         "comment",
+	argPrompt:          "prompt",
+	argProgram:         "program",
+	argFlag:            "flag",
+	argMethod:          "method",
+	argHeader:          "header",
+	argBody:            "body",
+	argValue:           "value",
+	argRaw:             "value",
+	argURL:             "url",
+	argPipe:            "flag",
+	argTrailingComment: "comment",
+}
+
+var htmlPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>curl commands</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+pre { background: #272822; color: #f8f8f2; padding: 1em; white-space: pre-wrap; word-break: break-all; }
+.command { position: relative; }
+.command button { position: absolute; top: .5em; right: .5em; }
+.comment { color: #75715e; }
+.program { font-weight: bold; }
+.flag { color: #66d9ef; }
+.method { color: #fd971f; }
+.header { color: #ae81ff; }
+.body { color: #e6db74; }
+.url { color: #a6e22e; }
+</style>
+</head>
+<body>
+{{- range .}}
+<h2>{{.Host}}</h2>
+{{- range .Commands}}
+<div class="command">
+<button type="button" data-command="{{.Plain}}" onclick="navigator.clipboard.writeText(this.dataset.command)">Copy</button>
+<pre>{{range $i, $t := .Tokens}}{{if $i}} {{end}}<span class="{{$t.Class}}">{{$t.Text}}</span>{{end}}</pre>
+</div>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// ExportHTML writes a standalone HTML page showing the curl commands for
+// reqs, highlighted and grouped by host, each with a copy-to-clipboard
+// button. Hosts are sorted; commands keep the order of reqs. Requests
+// that fail are left out of the page and reported in a *MultiError.
+func ExportHTML(w io.Writer, reqs []*http.Request, opts ...Option) error {
+	o := newOptions(opts)
+
+	var errs MultiError
+	byHost := map[string]*htmlGroup{}
+	var hosts []string
+	for i, req := range reqs {
+		args, err := buildArgs(req, o)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+
+		var cmd htmlCommand
+		var plain []string
+		for _, a := range args {
+			token := o.token(a)
+			cmd.Tokens = append(cmd.Tokens, htmlToken{Class: htmlClasses[a.kind], Text: token})
+			plain = append(plain, token)
+		}
+		cmd.Plain = strings.Join(plain, " ")
+
+		host := req.URL.Host
+		group, ok := byHost[host]
+		if !ok {
+			group = &htmlGroup{Host: host}
+			byHost[host] = group
+			hosts = append(hosts, host)
+		}
+		group.Commands = append(group.Commands, cmd)
+	}
+
+	sort.Strings(hosts)
+	groups := make([]*htmlGroup, len(hosts))
+	for i, host := range hosts {
+		groups[i] = byHost[host]
+	}
+	if err := htmlPage.Execute(w, groups); err != nil {
+		return err
+	}
+	return errs.errOrNil()
+}
@@ -0,0 +1,31 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleExportHTML() {
+	req1, _ := http.NewRequest("GET", "http://b.example.com/", nil)
+	req2, _ := http.NewRequest("DELETE", "http://a.example.com/items/1", nil)
+	req3, _ := http.NewRequest("POST", "http://b.example.com/", strings.NewReader(`<b>"hi"</b>`))
+
+	var buf bytes.Buffer
+	if err := ExportHTML(&buf, []*http.Request{req1, req2, req3}); err != nil {
+		panic(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "<h2>") || strings.HasPrefix(line, "<pre>") {
+			fmt.Println(line)
+		}
+	}
+
+	// Output:
+	// <h2>a.example.com</h2>
+	// <pre><span class="program">curl</span> <span class="flag">-X</span> <span class="method">&#39;DELETE&#39;</span> <span class="url">&#39;http://a.example.com/items/1&#39;</span></pre>
+	// <h2>b.example.com</h2>
+	// <pre><span class="program">curl</span> <span class="flag">-X</span> <span class="method">&#39;GET&#39;</span> <span class="url">&#39;http://b.example.com/&#39;</span></pre>
+	// <pre><span class="program">curl</span> <span class="flag">-X</span> <span class="method">&#39;POST&#39;</span> <span class="flag">-d</span> <span class="body">&#39;&lt;b&gt;&#34;hi&#34;&lt;/b&gt;&#39;</span> <span class="url">&#39;http://b.example.com/&#39;</span></pre>
+}
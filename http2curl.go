@@ -2,11 +2,14 @@ package http2curl
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -20,7 +23,15 @@ func (c *CurlCommand) append(newSlice ...string) {
 
 // String returns a ready to copy/paste command
 func (c *CurlCommand) String() string {
-	return strings.Join(*c, " ")
+	var b strings.Builder
+	for i, token := range *c {
+		// tokens ending a line, such as comments, need no separator
+		if i > 0 && !strings.HasSuffix((*c)[i-1], "\n") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(token)
+	}
+	return b.String()
 }
 
 // nopCloser is used to create a new io.ReadCloser for req.Body
@@ -35,49 +46,411 @@ func bashEscape(str string) string {
 func (nopCloser) Close() error { return nil }
 
 // GetCurlCommand returns a CurlCommand corresponding to an http.Request
-func GetCurlCommand(req *http.Request) (*CurlCommand, error) { return Command(req, nil) }
+func GetCurlCommand(req *http.Request) (*CurlCommand, error) { return GetCurlCommandWithOptions(req) }
 
 // Command returns a CurlCommand corresponding to the http.Request and http.CookieJar
-func Command(req *http.Request, jar http.CookieJar) (*CurlCommand, error) {
-	command := CurlCommand{}
+func Command(req *http.Request, jar http.CookieJar, opts ...Option) (*CurlCommand, error) {
+	if jar != nil {
+		opts = append(opts[:len(opts):len(opts)], WithCookieJar(jar))
+	}
+	return GetCurlCommandWithOptions(req, opts...)
+}
+
+// GetCurlCommandWithOptions returns a CurlCommand corresponding to an
+// http.Request, generated according to opts. When req.GetBody is set, as
+// done by http.NewRequest for in-memory bodies, the body is read from a
+// copy it returns and req.Body is left untouched; otherwise req.Body is
+// read and replaced by a fresh copy. NewCapture tells which was done.
+func GetCurlCommandWithOptions(req *http.Request, opts ...Option) (*CurlCommand, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.selfCheck {
+		if err := o.verify(args); err != nil {
+			return nil, err
+		}
+	}
+	command := o.render(args)
+	return &command, nil
+}
+
+// argKind tells the renderer what a command line argument holds.
+type argKind int
+
+const (
+	argComment argKind = iota
+	argPrompt
+	argProgram
+	argFlag
+	argMethod
+	argHeader
+	argBody
+	argValue
+	argRaw
+	argURL
+	argPipe
+	argTrailingComment
+)
+
+// arg is a single, not yet escaped, command line argument.
+type arg struct {
+	kind  argKind
+	value string
+}
+
+// argList is the unescaped form of a CurlCommand.
+type argList []arg
+
+func (a *argList) add(kind argKind, value string) {
+	*a = append(*a, arg{kind: kind, value: value})
+}
+
+// addFlag adds the flag value, which takes no value, unless it is there
+// already, as several options may ask for it.
+func (a *argList) addFlag(value string) {
+	if !a.hasFlag(value) {
+		a.add(argFlag, value)
+	}
+}
+
+// hasFlag reports whether the flag value is there.
+func (a argList) hasFlag(value string) bool {
+	for _, x := range a {
+		if x.kind == argFlag && x.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// comment adds a shell comment line, rendered before the command.
+func (a *argList) comment(text string) {
+	a.add(argComment, strings.NewReplacer("\r", " ", "\n", " ").Replace(text))
+}
+
+// flag adds a flag followed by its value.
+func (a *argList) flag(name string, kind argKind, value string) {
+	a.add(argFlag, name)
+	a.add(kind, value)
+}
+
+// BodySource tells how the body of a request was read.
+type BodySource string
+
+const (
+	// BodyNone is used for requests without a body.
+	BodyNone BodySource = ""
+	// BodyGetBody is used when the body was read from a copy obtained with
+	// req.GetBody, leaving req.Body untouched.
+	BodyGetBody BodySource = "GetBody"
+	// BodyReplaced is used when req.Body was read and replaced by an
+	// in-memory copy, because req.GetBody was not set or failed.
+	BodyReplaced BodySource = "Body"
+)
+
+// readBody returns the body of req, see readBodySource.
+func readBody(ctx context.Context, req *http.Request) ([]byte, error) {
+	body, _, err := readBodySource(ctx, req)
+	return body, err
+}
+
+// readBodySource returns the body of req and how it was read. When
+// req.GetBody is set, the body is read from a fresh copy and req.Body is
+// left as it is, which keeps retries and streaming callers working.
+// Otherwise, or when the copy is empty, req.Body is read and a fresh copy
+// is left in its place.
+// Reading stops when ctx is done; what was read so far is then put back in
+// front of the remaining body.
+func readBodySource(ctx context.Context, req *http.Request) ([]byte, BodySource, error) {
+	if req.GetBody != nil {
+		// the body may have been consumed already, by a failed attempt
+		if rc, err := req.GetBody(); err == nil {
+			body, err := ioutil.ReadAll(ctxReader{ctx: ctx, r: rc})
+			rc.Close()
+			if err == nil && len(body) > 0 {
+				return body, BodyGetBody, nil
+			}
+		}
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, BodyNone, nil
+	}
+	body, err := ioutil.ReadAll(ctxReader{ctx: ctx, r: req.Body})
+	if err != nil {
+		req.Body = nopCloser{io.MultiReader(bytes.NewReader(body), req.Body)}
+		return nil, BodyReplaced, err
+	}
+	req.Body = nopCloser{bytes.NewBuffer(body)}
+	return body, BodyReplaced, nil
+}
 
-	command.append("curl")
+// buildArgs returns the arguments of the curl command for req.
+func buildArgs(req *http.Request, o *Options) (argList, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if req.URL == nil {
+		return nil, errors.New("http2curl: invalid request, req.URL is nil")
+	}
 
-	command.append("-X", bashEscape(req.Method))
+	// notes are rendered as comments above the command
+	var args, notes argList
 
-	if req.Body != nil {
-		body, err := ioutil.ReadAll(req.Body)
+	for _, comment := range o.comments {
+		notes.comment(comment)
+	}
+	if tags := o.tagsFor(req.Context()); len(tags) > 0 {
+		notes.comment("tags: " + strings.Join(formatTags(tags), " "))
+	}
+	if !o.timestamp.IsZero() {
+		notes.comment("captured at " + o.timeString(o.timestamp))
+	}
+	if o.analyze {
+		analysis, err := analyze(o.context(), req)
 		if err != nil {
 			return nil, err
 		}
-		req.Body = nopCloser{bytes.NewBuffer(body)}
-		if len(body) > 0 {
-			bodyEscaped := bashEscape(string(body))
-			command.append("-d", bodyEscaped)
+		for _, line := range analysis.lines(o) {
+			notes.comment(line)
+		}
+	}
+
+	if o.leadingSpace {
+		args.add(argProgram, " curl")
+	} else {
+		args.add(argProgram, "curl")
+	}
+
+	if o.upstreamCompat {
+		if _, scheme := upstreamURL(req); scheme == "https" {
+			args.add(argFlag, "-k")
 		}
 	}
 
+	body, err := readBody(o.context(), req)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 && req.ContentLength > 0 {
+		if o.outputVersion >= OutputV3 {
+			notes.comment(fmt.Sprintf("body of %s unavailable", o.sizeString(int(req.ContentLength))))
+		}
+		o.loss(LossDropped, "body", "already read and not available from GetBody")
+	}
+
+	// work on a copy, options may rewrite the method, headers and URL
+	req = req.Clone(req.Context())
+
+	body = o.applyMethodOverride(req, body, &notes)
+	if err := o.checkMethod(req); err != nil {
+		return nil, err
+	}
+	if err := o.checkURL(req); err != nil {
+		return nil, err
+	}
+	if warning := o.methodWarning(req.Method); warning != "" {
+		notes.comment(warning)
+	}
+
+	var bodyArgs argList
+	if len(body) > 0 && o.bodyFile != "" {
+		bodyArgs.flag(string(DataBinary), argValue, "@"+o.bodyFile)
+	} else if len(body) > 0 {
+		if o.bodyLimit > 0 && len(body) > o.bodyLimit {
+			notes.comment(fmt.Sprintf("body truncated from %s to %s", o.sizeString(len(body)), o.sizeString(o.bodyLimit)))
+			o.loss(LossTruncated, "body", fmt.Sprintf("cut from %s to %s", o.sizeString(len(body)), o.sizeString(o.bodyLimit)))
+			body = body[:o.bodyLimit]
+		}
+		body = o.indentJSON(body)
+		flag, err := o.dataFlagFor(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyArgs.flag(flag, argBody, string(body))
+	}
+
+	switch {
+	case o.headFlag && req.Method == http.MethodHead:
+		// curl -X HEAD waits for a response body that never comes
+		args.add(argFlag, "--head")
+	case o.outputVersion < 2 || !impliedMethod(req.Method, len(body) > 0):
+		args.flag("-X", argMethod, req.Method)
+	}
+	if o.outputVersion < 2 {
+		args = append(args, bodyArgs...)
+	}
+
 	var keys []string
 
+	trailers := make([]string, 0, len(req.Trailer))
+	for name := range req.Trailer {
+		trailers = append(trailers, name)
+	}
+	sort.Strings(trailers)
+	for _, name := range trailers {
+		o.loss(LossDropped, "trailer "+name, "curl cannot send trailers")
+	}
+
 	// Lets add our cookes to the mix
-	if jar != nil {
-		// make a copy
-		req = req.Clone(req.Context())
-		for _, cookie := range jar.Cookies(req.URL) {
+	if o.jar != nil {
+		for _, cookie := range o.jar.Cookies(req.URL) {
 			req.AddCookie(cookie)
 		}
 	}
 
+	if o.netrc != "" {
+		o.stripBasicAuth(req)
+	}
+
+	o.applyHeaderOverrides(req)
+
+	o.redact(req)
+	o.applyRedactor(req)
+
+	if err := o.applyReplaySafety(req); err != nil {
+		return nil, err
+	}
+
+	if warning := o.contentTypeWarning(req, body); warning != "" {
+		notes.comment(warning)
+	}
+	if warning := o.signedURLWarningFor(req.URL); warning != "" {
+		notes.comment(warning)
+	}
+	if advice := o.compressionNote(req); advice != "" {
+		notes.comment(advice)
+	}
+
+	if o.contentLength && len(body) > 0 && req.Header.Get("Content-Length") == "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	cookieArgs, cookieFlags := o.cookieArgs(req)
+
 	for k := range req.Header {
+		if cookieFlags && k == "Cookie" {
+			continue
+		}
+		if o.headerFilter != nil && !o.headerFilter(http.CanonicalHeaderKey(k)) {
+			o.loss(LossDropped, "header "+k, "filtered out")
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
+	var lines []string
 	for _, k := range keys {
-		command.append("-H", bashEscape(fmt.Sprintf("%s: %s", k, strings.Join(req.Header[k], " "))))
+		k, values, err := o.applyHeaderPolicy(k, req.Header[k])
+		if err != nil {
+			return nil, err
+		}
+		// curl reads "-H @file" from a file, a colon would move the
+		// boundary between name and value
+		if strings.HasPrefix(k, "@") || strings.ContainsRune(k, ':') || strings.IndexFunc(k, isControl) >= 0 {
+			return nil, fmt.Errorf("http2curl: invalid header name %q", k)
+		}
+		if o.escapesControl() && strings.IndexFunc(strings.Join(values, ""), isControl) >= 0 {
+			notes.comment("header " + k + " holds control characters, written with $'...' escapes")
+		}
+		if o.headerPerValue {
+			for _, v := range values {
+				lines = append(lines, fmt.Sprintf("%s: %s", o.headerName(k), v))
+			}
+			continue
+		}
+		if len(values) > 1 {
+			o.loss(LossRewritten, "header "+k, fmt.Sprintf("%d values joined with spaces", len(values)))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", o.headerName(k), strings.Join(values, " ")))
 	}
+	headerArgs, err := o.spillHeaders(lines, &notes)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, headerArgs...)
+	args = append(args, cookieArgs...)
 
-	command.append(bashEscape(req.URL.String()))
+	if o.outputVersion >= 2 {
+		args = append(args, bodyArgs...)
+	}
 
-	return &command, nil
+	if o.netrc != "" {
+		args.flag("--netrc-file", argRaw, "~/.netrc-"+o.netrc)
+	}
+
+	args = append(args, o.flags...)
+
+	o.normalizeQuery(req.URL)
+	requestURL := req.URL.String()
+	if o.upstreamCompat {
+		requestURL, _ = upstreamURL(req)
+		if requestURL != req.URL.String() {
+			o.loss(LossRewritten, "url", "rebuilt from the host and path of the request")
+		}
+	}
+	// a URL starting with a dash would be taken for an option
+	if strings.HasPrefix(requestURL, "-") {
+		args.flag("--url", argURL, requestURL)
+	} else {
+		args.add(argURL, requestURL)
+	}
+
+	if o.upstreamCompat || o.compressedFlag && acceptsCompression(req) {
+		args.add(argFlag, "--compressed")
+	}
+
+	args = append(args, o.pipe...)
+
+	if o.historyComment && hasCredentials(req) {
+		args.add(argTrailingComment, "contains credentials")
+	}
+
+	notes = append(notes, o.prompts...)
+	args = append(notes, args...)
+	if err := o.checkShell(args); err != nil {
+		return nil, err
+	}
+	if err := o.checkControl(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// render escapes args into a CurlCommand according to o.
+func (o *Options) render(args argList) CurlCommand {
+	command := CurlCommand{}
+	for _, a := range args {
+		token := o.token(a)
+		if o.color {
+			token = colorize(a.kind, token)
+		}
+		command.append(token)
+	}
+	return command
+}
+
+// token returns the escaped, uncolored form of a.
+func (o *Options) token(a arg) string {
+	switch a.kind {
+	case argComment:
+		return o.shell.comment(a.value, false)
+	case argTrailingComment:
+		return o.shell.comment(a.value, true)
+	case argPrompt:
+		return a.value + "\n"
+	case argProgram:
+		return o.shell.program(a.value)
+	case argFlag, argRaw, argPipe:
+		return a.value
+	case argHeader:
+		// control characters would show as a broken command line, or
+		// move the cursor of the terminal displaying it
+		if o.escapesControl() && strings.IndexFunc(a.value, isControl) >= 0 {
+			return o.quoteControl(a.value)
+		}
+	}
+	return o.quote(a.value)
 }
@@ -163,3 +163,76 @@ func ExampleCommand_with_cookies() {
 	// Output: curl -X 'PUT' -d '{"hello":"world","answer":42}' -H 'Content-Type: application/json' -H 'Cookie: cookie1=value1; cookie2=value2' -H 'X-Auth-Token: private-token' 'http://www.example.com/abc/def.ghi?jlk=mno&pqr=stu'
 
 }
+
+func ExampleGetCurlCommand_dashURL() {
+	req, _ := http.NewRequest("GET", "http://www.example.com/", nil)
+	req.URL = &url.URL{Path: "-o/etc/passwd"}
+
+	command, _ := GetCurlCommand(req)
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' --url '-o/etc/passwd'
+}
+
+func ExampleGetCurlCommand_hostileValues() {
+	req, _ := http.NewRequest("-v", "http://www.example.com/", bytes.NewBufferString("@/etc/passwd"))
+	req.Header.Set("X-Evil", "-o /tmp/x")
+	req.Header["@/etc/passwd"] = []string{"x"}
+
+	_, err := GetCurlCommand(req)
+	fmt.Println(err)
+
+	delete(req.Header, "@/etc/passwd")
+	command, _ := GetCurlCommand(req)
+	fmt.Println(command)
+
+	// Output:
+	// http2curl: invalid header name "@/etc/passwd"
+	// curl -X '-v' --data-raw '@/etc/passwd' -H 'X-Evil: -o /tmp/x' 'http://www.example.com/'
+}
+
+func ExampleGetCurlCommandWithOptions() {
+	req, _ := http.NewRequest("POST", "http://example.com/upload", strings.NewReader(strings.Repeat("0123456789", 10)))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("User-Agent", "Go-http-client/1.1")
+	req.Header.Set("X-Request-Id", "42")
+
+	command, _ := GetCurlCommandWithOptions(req,
+		WithQuoteStyle(QuoteMinimal),
+		WithHeaderFilter(func(name string) bool { return name != "User-Agent" }),
+		WithBodyLimit(16),
+	)
+	fmt.Println(command)
+
+	// Output:
+	// # body truncated from 100 bytes to 16 bytes
+	// curl -X POST -d 0123456789012345 -H 'Content-Type: text/plain' -H 'X-Request-Id: 42' http://example.com/upload
+}
+
+func ExampleGetCurlCommand_consumedBody() {
+	// a retried request: its body was read by the first attempt
+	req, _ := http.NewRequest("PUT", "http://example.com/", strings.NewReader(`{"n":1}`))
+	ioutil.ReadAll(req.Body)
+
+	command, _ := GetCurlCommand(req)
+	fmt.Println(command)
+
+	// no GetBody to fall back on
+	req, _ = http.NewRequest("PUT", "http://example.com/", ioutil.NopCloser(strings.NewReader(`{"n":1}`)))
+	req.ContentLength = 7
+	ioutil.ReadAll(req.Body)
+
+	command, _ = GetCurlCommandWithOptions(req, WithLossCallback(func(e LossEvent) { fmt.Println(e) }))
+	fmt.Println(command)
+	// from OutputV3 on, the command notes it
+	command, _ = GetCurlCommandWithOptions(req, WithOutputVersion(OutputV3))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'PUT' -d '{"n":1}' 'http://example.com/'
+	// body dropped: already read and not available from GetBody
+	// curl -X 'PUT' 'http://example.com/'
+	// # body of 7 bytes unavailable
+	// curl -X 'PUT' 'http://example.com/'
+}
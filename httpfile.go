@@ -0,0 +1,179 @@
+package http2curl
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// httpFileRequest is a request as written in the .http files of editor
+// HTTP clients.
+type httpFileRequest struct {
+	comments []string
+	method   string
+	target   string
+	headers  []string // "Name: value"
+	body     string
+	bodyFile string
+	hasBody  bool
+}
+
+// httpFileDialect tells how a client writes .http files.
+type httpFileDialect struct {
+	tool string
+	// variable renders a reference to the variable name.
+	variable func(name string) string
+	// named starts each request with a ### line naming it.
+	named bool
+	// rewrite, when set, changes each request before it is written.
+	rewrite func(r *httpFileRequest)
+}
+
+// WriteRESTClientFile writes reqs in the .http format of the REST Client
+// extension of VS Code: each request is its request line, its headers, a
+// blank line and its body, and requests are separated by ### lines, so
+// captures can be sent again from the editor. The requests are generated
+// from the same arguments as the curl commands, so options apply alike;
+// notes become # comments, placeholders become {{$processEnv NAME}}
+// references to environment variables, and curl flags are reported to the
+// loss callback. Requests that fail are left out of the file and reported
+// in a *MultiError.
+func WriteRESTClientFile(w io.Writer, reqs []*http.Request, opts ...Option) error {
+	o := newOptions(opts)
+	return o.writeHTTPFile(w, reqs, httpFileDialect{
+		tool:     "REST Client",
+		variable: func(name string) string { return "{{$processEnv " + name + "}}" },
+	})
+}
+
+// writeHTTPFile writes reqs as a .http file in dialect d.
+func (o *Options) writeHTTPFile(w io.Writer, reqs []*http.Request, d httpFileDialect) error {
+	var (
+		b     strings.Builder
+		errs  MultiError
+		first = true
+	)
+	for i, req := range reqs {
+		args, err := buildArgs(req, o)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		r := o.httpFileRequest(args, d.tool)
+		if d.rewrite != nil {
+			d.rewrite(&r)
+		}
+		switch {
+		case d.named:
+			if !first {
+				b.WriteString("\n")
+			}
+			b.WriteString("### " + r.method + " " + req.URL.EscapedPath() + "\n")
+		case !first:
+			b.WriteString("\n###\n\n")
+		}
+		first = false
+
+		str := func(s string) string { return o.httpFileString(s, d.variable) }
+		for _, comment := range r.comments {
+			b.WriteString("# " + comment + "\n")
+		}
+		b.WriteString(r.method + " " + str(r.target) + "\n")
+		for _, h := range r.headers {
+			b.WriteString(str(h) + "\n")
+		}
+		switch {
+		case r.bodyFile != "":
+			b.WriteString("\n< " + r.bodyFile + "\n")
+		case r.hasBody:
+			b.WriteString("\n" + str(r.body))
+			if !strings.HasSuffix(r.body, "\n") {
+				b.WriteString("\n")
+			}
+		}
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	return errs.errOrNil()
+}
+
+// httpFileRequest reads the request of args for tool.
+func (o *Options) httpFileRequest(args argList, tool string) httpFileRequest {
+	var (
+		r       httpFileRequest
+		cookies []string
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			r.comments = append(r.comments, a.value)
+			continue
+		case argURL:
+			r.target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by "+tool)
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			r.method = value.value
+		case a.value == "--head":
+			r.method = http.MethodHead
+		case a.value == "-H":
+			r.headers = append(r.headers, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			r.bodyFile, r.hasBody = value.value[1:], true
+		case dataFlags[a.value]:
+			if !utf8.ValidString(value.value) {
+				o.loss(LossDropped, "body", "not valid UTF-8, "+tool+" bodies are text")
+				continue
+			}
+			r.body, r.hasBody = value.value, true
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by "+tool)
+		}
+	}
+	if len(cookies) > 0 {
+		r.headers = append(r.headers, "Cookie: "+strings.Join(cookies, "; "))
+	}
+	if r.method == "" {
+		r.method = http.MethodGet
+		if r.hasBody {
+			r.method = http.MethodPost
+		}
+	}
+	return r
+}
+
+// httpFileString returns str with placeholders rendered by variable.
+func (o *Options) httpFileString(str string, variable func(name string) string) string {
+	if len(o.placeholders) == 0 {
+		return str
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString(variable(seg.text))
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	return b.String()
+}
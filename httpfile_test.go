@@ -0,0 +1,34 @@
+package http2curl
+
+import (
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func ExampleWriteRESTClientFile() {
+	login, _ := http.NewRequest("POST", "https://api.example.com/login", strings.NewReader(`{"user":"gopher"}`))
+	login.Header.Set("Content-Type", "application/json")
+
+	me, _ := http.NewRequest("GET", "https://api.example.com/me", nil)
+	me.Header.Set("Authorization", "Bearer abc123")
+
+	WriteRESTClientFile(os.Stdout, []*http.Request{login, me},
+		WithComment("replayed from staging"),
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"),
+	)
+
+	// Output:
+	// # replayed from staging
+	// POST https://api.example.com/login
+	// Content-Type: application/json
+	//
+	// {"user":"gopher"}
+	//
+	// ###
+	//
+	// # replayed from staging
+	// GET https://api.example.com/me
+	// Authorization: Bearer {{$processEnv TOKEN}}
+}
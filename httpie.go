@@ -0,0 +1,275 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// httpieFlags maps the curl flags without a value to their HTTPie
+// equivalent, empty when HTTPie behaves so by default.
+var httpieFlags = map[string]string{
+	"-k":               "--verify=no",
+	"--insecure":       "--verify=no",
+	"-v":               "-v",
+	"--compressed":     "",
+	"-f":               "--check-status",
+	"--fail":           "--check-status",
+	"--fail-with-body": "--check-status",
+}
+
+// GetHTTPieCommand returns an HTTPie command sending the request of the
+// curl command of req, meant to be read: query parameters become ==
+// items, and the fields of JSON objects and form bodies become = and :=
+// items. Other bodies are sent with --raw. HTTPie adds headers of its own,
+// such as Accept for JSON bodies, so the request is not sent byte for
+// byte. It is generated from the same arguments as the curl command, so
+// options apply alike; curl flags without an equivalent are reported to
+// the loss callback and left out.
+func GetHTTPieCommand(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		httpie  argList
+		flags   argList
+		method  string
+		target  string
+		headers []string
+		cookies []string
+		body    *arg
+		file    string
+		tail    argList
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argPrompt:
+			httpie = append(httpie, a)
+			continue
+		case argURL:
+			target = a.value
+			continue
+		case argPipe, argTrailingComment:
+			tail = append(tail, args[i:]...)
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			headers = append(headers, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			file = value.value[1:]
+		case dataFlags[a.value]:
+			body = &args[i]
+		default:
+			flag, ok := httpieFlags[a.value]
+			if !ok {
+				o.loss(LossDropped, "flag "+a.value, "not supported by HTTPie")
+			} else if flag != "" {
+				flags.add(argFlag, flag)
+			}
+		}
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, "Cookie: "+strings.Join(cookies, "; "))
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	program := "http"
+	if u.Scheme == "https" {
+		program = "https"
+	}
+	if o.leadingSpace {
+		program = " " + program
+	}
+	httpie.add(argProgram, program)
+	httpie = append(httpie, flags...)
+
+	var contentType string
+	for _, h := range headers {
+		if name, v, _ := strings.Cut(h, ":"); strings.EqualFold(name, "Content-Type") {
+			contentType = strings.TrimSpace(v)
+		}
+	}
+	items, form, raw := o.httpieBody(body, contentType)
+	switch {
+	case form:
+		httpie.add(argFlag, "--form")
+	case raw:
+		httpie.flag("--raw", argBody, body.value)
+	}
+
+	implied := http.MethodGet
+	if body != nil || file != "" {
+		implied = http.MethodPost
+	}
+	if method != "" && method != implied {
+		httpie.add(argMethod, method)
+	}
+
+	// the query is moved to == items when it reads back the same
+	query := u.RawQuery
+	u.Scheme, u.RawQuery, u.ForceQuery = "", "", false
+	location := strings.TrimPrefix(u.String(), "//")
+	if params, ok := splitQuery(query); ok && query != "" {
+		httpie.add(argURL, location)
+		for _, kv := range params {
+			httpie.add(argRaw, o.quote(httpieKey(kv[0])+"=="+kv[1]))
+		}
+	} else {
+		if query != "" {
+			location += "?" + query
+		}
+		httpie.add(argURL, location)
+	}
+
+	for _, h := range headers {
+		name, v, _ := strings.Cut(h, ":")
+		if form && strings.EqualFold(name, "Content-Type") && strings.HasPrefix(contentType, "application/x-www-form-urlencoded") ||
+			len(items) > 0 && !form && strings.EqualFold(name, "Content-Type") && contentType == "application/json" {
+			// set by HTTPie for the body items
+			continue
+		}
+		if strings.TrimSpace(v) == "" {
+			httpie.add(argRaw, o.quote(httpieKey(name)+";"))
+			continue
+		}
+		httpie.add(argRaw, o.quoteHeaderItem(h))
+	}
+	httpie = append(httpie, items...)
+	if file != "" {
+		httpie.add(argPipe, "<")
+		httpie.add(argValue, file)
+	}
+	httpie = append(httpie, tail...)
+
+	command := o.render(httpie)
+	return command.String(), nil
+}
+
+// httpieBody returns the request items of a JSON object or form body,
+// whether it is a form, and whether the body must be sent raw instead.
+func (o *Options) httpieBody(body *arg, contentType string) (argList, bool, bool) {
+	if body == nil {
+		return nil, false, false
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	data := []byte(body.value)
+	var items argList
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		fields, ok := splitQuery(body.value)
+		if !ok || len(fields) == 0 {
+			return nil, false, true
+		}
+		for _, kv := range fields {
+			items.add(argRaw, o.quote(httpieKey(kv[0])+"="+kv[1]))
+		}
+		return items, true, false
+	case mediaType == "application/json" || mediaType == "" && sniffContentType(data) == "application/json":
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil || len(fields) == 0 {
+			return nil, false, true
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var s string
+			if err := json.Unmarshal(fields[key], &s); err == nil {
+				items.add(argRaw, o.quote(httpieKey(key)+"="+s))
+				continue
+			}
+			var compact bytes.Buffer
+			json.Compact(&compact, fields[key])
+			items.add(argRaw, o.quote(httpieKey(key)+":="+compact.String()))
+		}
+		return items, false, false
+	}
+	return nil, false, true
+}
+
+// splitQuery returns the decoded key and value pairs of query, in order,
+// and whether HTTPie items can express them: every pair must have a value
+// and decode.
+func splitQuery(query string) ([][2]string, bool) {
+	if query == "" {
+		return nil, true
+	}
+	var pairs [][2]string
+	for _, kv := range strings.Split(query, "&") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, false
+		}
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, false
+		}
+		if value, err = url.QueryUnescape(value); err != nil {
+			return nil, false
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs, true
+}
+
+// httpieKey escapes the characters HTTPie takes for item separators.
+func httpieKey(key string) string {
+	return strings.NewReplacer(`\`, `\\`, `:`, `\:`, `=`, `\=`, `@`, `\@`, `;`, `\;`).Replace(key)
+}
+
+// quoteHeaderItem quotes the header line "Name: value" as the HTTPie item
+// "Name:value", placeholders being matched against the header line.
+func (o *Options) quoteHeaderItem(line string) string {
+	name, value, _ := strings.Cut(line, ":")
+	item := httpieKey(name) + ":" + strings.TrimLeft(value, " ")
+	if len(o.placeholders) == 0 {
+		return o.quote(item)
+	}
+	// the space after the colon is only dropped from the text before the
+	// first placeholder
+	var b strings.Builder
+	segments := o.segments(line)
+	for i, seg := range segments {
+		switch {
+		case seg.isVar:
+			b.WriteString(o.shell.variable(seg.text))
+		case i == 0:
+			name, value, _ := strings.Cut(seg.text, ":")
+			b.WriteString(o.quoteLiteral(httpieKey(name) + ":" + strings.TrimLeft(value, " ")))
+		default:
+			b.WriteString(o.quoteLiteral(seg.text))
+		}
+	}
+	return b.String()
+}
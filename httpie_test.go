@@ -0,0 +1,36 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleGetHTTPieCommand() {
+	req, _ := http.NewRequest("PUT", "https://example.com/items/1?draft=true&note=a%20b", strings.NewReader(`{"name":"gopher","tags":["go"],"age":13}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	command, _ := GetHTTPieCommand(req, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"))
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("POST", "http://example.com/login", strings.NewReader("user=gopher&pass=a%26b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	command, _ = GetHTTPieCommand(req)
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("POST", "http://example.com/notes", strings.NewReader("plain text"))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("X-Empty", "")
+	command, _ = GetHTTPieCommand(req, ForScripting(), WithLossCallback(func(e LossEvent) { fmt.Println(e) }))
+	fmt.Println(command)
+
+	// Output:
+	// https 'PUT' 'example.com/items/1' 'draft==true' 'note==a b' 'Authorization:Bearer '"${TOKEN}" 'age:=13' 'name=gopher' 'tags:=["go"]'
+	// http --form 'example.com/login' 'user=gopher' 'pass=a&b'
+	// flag -sS dropped: not supported by HTTPie
+	// flag --retry dropped: not supported by HTTPie
+	// flag --retry-connrefused dropped: not supported by HTTPie
+	// http --check-status --raw 'plain text' 'example.com/notes' 'Content-Type:text/plain' 'X-Empty;'
+}
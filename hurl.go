@@ -0,0 +1,171 @@
+package http2curl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// hurlOptions maps the curl flags without a value to the entries of the
+// [Options] section of Hurl.
+var hurlOptions = map[string]string{
+	"-k":           "insecure: true",
+	"--insecure":   "insecure: true",
+	"--compressed": "compressed: true",
+	"-L":           "location: true",
+	"--location":   "location: true",
+	"-v":           "verbose: true",
+	"-s":           "",
+	"-sS":          "",
+}
+
+// GetHurlEntry returns the Hurl entry sending req, for hurl and its test
+// mode. When resp is not nil, the entry asserts the status of the
+// response, so that captures become smoke tests to run in CI. JSON bodies
+// are written as they are, other text bodies as multiline strings and
+// binary bodies in base64. It is generated from the same arguments as the
+// curl command, so options apply alike; notes become # comments,
+// placeholders become {{NAME}} variables, to be set with --variable, and
+// curl flags without an equivalent option are reported to the loss
+// callback.
+func GetHurlEntry(req *http.Request, resp *http.Response, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		b        strings.Builder
+		method   string
+		target   string
+		headers  []string
+		cookies  []string
+		options  []string
+		body     string
+		bodyFile string
+		hasBody  bool
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			b.WriteString("# " + a.value + "\n")
+			continue
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by Hurl")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch option, ok := hurlOptions[a.value]; {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			headers = append(headers, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			bodyFile, hasBody = value.value[1:], true
+		case dataFlags[a.value]:
+			body, hasBody = value.value, true
+		case ok:
+			if option != "" {
+				options = append(options, option)
+			}
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by Hurl")
+		}
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, "Cookie: "+strings.Join(cookies, "; "))
+	}
+	if method == "" {
+		method = http.MethodGet
+		if hasBody {
+			method = http.MethodPost
+		}
+	}
+
+	b.WriteString(method + " " + o.hurlValue(target) + "\n")
+	for _, h := range headers {
+		name, v, _ := strings.Cut(h, ":")
+		b.WriteString(o.hurlValue(name) + ": " + o.hurlValue(strings.TrimLeft(v, " ")) + "\n")
+	}
+	if len(options) > 0 {
+		b.WriteString("[Options]\n")
+		for _, option := range options {
+			b.WriteString(option + "\n")
+		}
+	}
+	switch trimmed := strings.TrimSpace(body); {
+	case bodyFile != "":
+		b.WriteString("file," + bodyFile + ";\n")
+	case !hasBody:
+	case len(o.placeholders) == 0 && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		b.WriteString(trimmed + "\n")
+	case utf8.ValidString(body) && !strings.Contains(body, "```"):
+		b.WriteString("```\n" + o.hurlBody(body))
+		if !strings.HasSuffix(body, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n")
+	default:
+		b.WriteString("base64," + base64.StdEncoding.EncodeToString([]byte(body)) + ";\n")
+	}
+	if resp != nil {
+		b.WriteString(fmt.Sprintf("\nHTTP %d\n", resp.StatusCode))
+	}
+	return b.String(), nil
+}
+
+// hurlEscaper escapes the characters that end or comment out Hurl values.
+var hurlEscaper = strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// hurlValue escapes a Hurl URL or header value, with placeholders as
+// {{NAME}} variables.
+func (o *Options) hurlValue(str string) string {
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("{{" + seg.text + "}}")
+		} else {
+			b.WriteString(hurlEscaper.Replace(seg.text))
+		}
+	}
+	return b.String()
+}
+
+// hurlBody returns a multiline string body with placeholders as
+// {{NAME}} variables.
+func (o *Options) hurlBody(str string) string {
+	if len(o.placeholders) == 0 {
+		return str
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("{{" + seg.text + "}}")
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	return b.String()
+}
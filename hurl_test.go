@@ -0,0 +1,44 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleGetHurlEntry() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/orders#new", strings.NewReader(`{"item": "gopher", "qty": 2}`))
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp := &http.Response{StatusCode: http.StatusCreated}
+
+	entry, _ := GetHurlEntry(req, resp, WithCompressedFlag(), ForInteractive())
+	fmt.Print(entry)
+	fmt.Println("--")
+
+	req, _ = http.NewRequest("PUT", "https://api.example.com/notes/1", strings.NewReader("line 1\nline 2"))
+	req.Header.Set("Authorization", "Bearer abc123")
+	entry, _ = GetHurlEntry(req, nil, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "token"))
+	fmt.Print(entry)
+
+	// Output:
+	// POST https://api.example.com/orders\#new
+	// Accept-Encoding: gzip
+	// Authorization: Bearer abc123
+	// Content-Type: application/json
+	// [Options]
+	// verbose: true
+	// compressed: true
+	// {"item": "gopher", "qty": 2}
+	//
+	// HTTP 201
+	// --
+	// PUT https://api.example.com/notes/1
+	// Authorization: Bearer {{token}}
+	// ```
+	// line 1
+	// line 2
+	// ```
+}
@@ -0,0 +1,96 @@
+package http2curl
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// JetBrainsEnv is the content of an http-client.env.json file: the values
+// of the variables of each environment.
+type JetBrainsEnv map[string]map[string]string
+
+// WithJetBrainsVariables makes WriteJetBrainsHTTPFile hoist the scheme and
+// host of the URLs, and the values of the headers listed by
+// WithRedactedHeaders, into {{variables}}. The origins are named host,
+// host_2 and so on, the headers after their lower-cased name, such as
+// {{x_api_key}}.
+func WithJetBrainsVariables() Option {
+	return func(o *Options) { o.jetBrainsVariables = true }
+}
+
+// WriteJetBrainsHTTPFile writes reqs in the .http format of the HTTP
+// Client of IntelliJ IDEA, GoLand and the other JetBrains IDEs: each
+// request starts with a ### line naming it after its method and path,
+// followed by its request line, its headers, a blank line and its body.
+// The requests are generated from the same arguments as the curl
+// commands, so options apply alike; notes become # comments, placeholders
+// become {{NAME}} variables, and curl flags are reported to the loss
+// callback. It returns a stub of the matching http-client.env.json with a
+// "dev" environment: hoisted hosts get their value, secrets and
+// placeholders are left empty, to be filled in http-client.private.env.json.
+// Requests that fail are left out of the file and reported in a
+// *MultiError.
+func WriteJetBrainsHTTPFile(w io.Writer, reqs []*http.Request, opts ...Option) (JetBrainsEnv, error) {
+	o := newOptions(opts)
+	vars := map[string]string{}
+	for _, p := range o.placeholders {
+		vars[p.name] = ""
+	}
+	d := httpFileDialect{
+		tool:     "the JetBrains HTTP Client",
+		variable: func(name string) string { return "{{" + name + "}}" },
+		named:    true,
+	}
+	if o.jetBrainsVariables {
+		origins := map[string]string{}
+		d.rewrite = func(r *httpFileRequest) {
+			u, err := url.Parse(r.target)
+			if err != nil || u.Host == "" {
+				return
+			}
+			origin := u.Scheme + "://" + u.Host
+			name, ok := origins[origin]
+			if !ok {
+				name = "host"
+				if len(origins) > 0 {
+					name += "_" + strconv.Itoa(len(origins)+1)
+				}
+				origins[origin] = name
+				vars[name] = origin
+			}
+			r.target = "{{" + name + "}}" + strings.TrimPrefix(r.target, origin)
+
+			for i, h := range r.headers {
+				key, _, _ := strings.Cut(h, ":")
+				if !o.jetBrainsSecret(key) {
+					continue
+				}
+				name := strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+				vars[name] = ""
+				r.headers[i] = key + ": {{" + name + "}}"
+			}
+		}
+	}
+
+	err := o.writeHTTPFile(w, reqs, d)
+	return JetBrainsEnv{"dev": vars}, err
+}
+
+// jetBrainsSecret reports whether the value of the header name is hoisted
+// into a variable: the headers redacted by WithRedactedHeaders, or the
+// ones it redacts by default.
+func (o *Options) jetBrainsSecret(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	if len(o.redactHeaders) > 0 {
+		return o.redactHeaders[name]
+	}
+	for _, s := range sensitiveHeaders {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func ExampleWriteJetBrainsHTTPFile() {
+	login, _ := http.NewRequest("POST", "https://api.example.com/login", strings.NewReader("user=gopher"))
+	login.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	me, _ := http.NewRequest("GET", "https://api.example.com/me?fields=name", nil)
+	me.Header.Set("Authorization", "Bearer abc123")
+
+	status, _ := http.NewRequest("GET", "http://status.example.com/", nil)
+
+	env, _ := WriteJetBrainsHTTPFile(os.Stdout, []*http.Request{login, me, status}, WithJetBrainsVariables())
+
+	b, _ := json.MarshalIndent(env, "", "  ")
+	fmt.Println(string(b))
+
+	// Output:
+	// ### POST /login
+	// POST {{host}}/login
+	// Content-Type: application/x-www-form-urlencoded
+	//
+	// user=gopher
+	//
+	// ### GET /me
+	// GET {{host}}/me?fields=name
+	// Authorization: {{authorization}}
+	//
+	// ### GET /
+	// GET {{host_2}}/
+	// {
+	//   "dev": {
+	//     "authorization": "",
+	//     "host": "https://api.example.com",
+	//     "host_2": "http://status.example.com"
+	//   }
+	// }
+}
@@ -0,0 +1,56 @@
+package http2curl
+
+// LossKind tells how the generator changed the request.
+type LossKind int
+
+const (
+	// LossDropped is reported when a part of the request is left out of
+	// the command.
+	LossDropped LossKind = iota
+	// LossTruncated is reported when a value is cut short.
+	LossTruncated
+	// LossRewritten is reported when a value is changed or replaced.
+	LossRewritten
+)
+
+func (k LossKind) String() string {
+	switch k {
+	case LossDropped:
+		return "dropped"
+	case LossTruncated:
+		return "truncated"
+	case LossRewritten:
+		return "rewritten"
+	}
+	return "unknown"
+}
+
+// LossEvent describes a part of the request the command does not
+// reproduce faithfully.
+type LossEvent struct {
+	Kind LossKind
+	// Field is the part of the request, such as "body", "url" or
+	// "header Authorization".
+	Field string
+	// Detail explains the change.
+	Detail string
+}
+
+func (e LossEvent) String() string {
+	return e.Field + " " + e.Kind.String() + ": " + e.Detail
+}
+
+// WithLossCallback calls fn each time the generated command drops,
+// truncates or rewrites a part of the request, be it on purpose, as with
+// redaction, or because curl cannot express it, as with trailers. It makes
+// it possible to measure how faithful generated commands are.
+func WithLossCallback(fn func(LossEvent)) Option {
+	return func(o *Options) { o.lossCallback = fn }
+}
+
+// loss reports a LossEvent to the loss callback, if any.
+func (o *Options) loss(kind LossKind, field, detail string) {
+	if o.lossCallback != nil {
+		o.lossCallback(LossEvent{Kind: kind, Field: field, Detail: detail})
+	}
+}
@@ -0,0 +1,34 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithLossCallback() {
+	req, _ := http.NewRequest("POST", "https://example.com/upload", strings.NewReader("line 1\nline 2\n"))
+	req.Header.Add("Accept", "text/plain")
+	req.Header.Add("Accept", "application/json")
+	req.Trailer = http.Header{"Checksum": nil}
+
+	lost := 0
+	command, _ := Command(req, nil,
+		WithDryRunHeader("X-Dry-Run"),
+		WithLossCallback(func(e LossEvent) {
+			lost++
+			fmt.Println(e)
+		}),
+	)
+	fmt.Println(lost, "losses")
+	fmt.Println(command)
+
+	// Output:
+	// trailer Checksum dropped: curl cannot send trailers
+	// header X-Dry-Run rewritten: set to mark the request as a dry run
+	// header Accept rewritten: 2 values joined with spaces
+	// 3 losses
+	// curl -X 'POST' -d 'line 1
+	// line 2
+	// ' -H 'Accept: text/plain application/json' -H 'X-Dry-Run: true' 'https://example.com/upload'
+}
@@ -0,0 +1,76 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithMethodCheck calls check with the method of each request, PATCH,
+// QUERY, PROPFIND or any other verb, and fails the generation with the
+// error it returns, to restrict commands to the methods an API accepts.
+func WithMethodCheck(check func(method string) error) Option {
+	return func(o *Options) { o.methodCheck = check }
+}
+
+// WithMethodWarnings adds a warning comment above the commands using a
+// method curl treats specially: HEAD sent with -X, which makes curl wait
+// for a response body, CONNECT, which curl sends as is instead of opening
+// a tunnel, and standard methods in lower case, which curl sends as
+// written while servers compare methods case-sensitively.
+func WithMethodWarnings() Option {
+	return func(o *Options) { o.methodWarnings = true }
+}
+
+// standardMethods are the methods of RFC 9110 and RFC 5789.
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// checkMethod checks the method of req with the method check. From
+// OutputV3 on, an empty method is defaulted to GET, as net/http does, and
+// methods which are not valid tokens are rejected.
+func (o *Options) checkMethod(req *http.Request) error {
+	if o.outputVersion >= OutputV3 {
+		if req.Method == "" {
+			req.Method = http.MethodGet
+		}
+		if err := validMethod(req.Method); err != nil {
+			return err
+		}
+	}
+	if o.methodCheck != nil {
+		if err := o.methodCheck(req.Method); err != nil {
+			return fmt.Errorf("http2curl: method %s: %w", req.Method, err)
+		}
+	}
+	return nil
+}
+
+// validMethod returns an error when method is not a valid token.
+func validMethod(method string) error {
+	for _, c := range method {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return fmt.Errorf("http2curl: invalid method %q; methods are tokens such as GET or PATCH, without spaces or separators like %q", method, c)
+		}
+	}
+	return nil
+}
+
+// methodWarning returns a warning about how curl sends method.
+func (o *Options) methodWarning(method string) string {
+	if !o.methodWarnings {
+		return ""
+	}
+	switch upper := strings.ToUpper(method); {
+	case method == http.MethodHead && !o.headFlag:
+		return "warning: curl -X HEAD waits for a response body that never comes, use --head instead"
+	case method == http.MethodConnect:
+		return "warning: curl sends CONNECT as a plain request, use --proxytunnel to open a tunnel through a proxy"
+	case method != upper && standardMethods[upper]:
+		return fmt.Sprintf("warning: method %s is sent as written, servers may not take it for %s", method, upper)
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package http2curl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func ExampleWithMethodWarnings() {
+	for _, method := range []string{"PROPFIND", "QUERY", "HEAD", "patch"} {
+		req, _ := http.NewRequest(method, "http://example.com/files/", strings.NewReader(`<propfind xmlns="DAV:"/>`))
+		if method == "HEAD" {
+			req, _ = http.NewRequest(method, "http://example.com/files/", nil)
+		}
+		command, err := GetCurlCommandWithOptions(req, WithMethodWarnings(), WithSelfCheck())
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'PROPFIND' -d '<propfind xmlns="DAV:"/>' 'http://example.com/files/'
+	// curl -X 'QUERY' -d '<propfind xmlns="DAV:"/>' 'http://example.com/files/'
+	// # warning: curl -X HEAD waits for a response body that never comes, use --head instead
+	// curl -X 'HEAD' 'http://example.com/files/'
+	// # warning: method patch is sent as written, servers may not take it for PATCH
+	// curl -X 'patch' -d '<propfind xmlns="DAV:"/>' 'http://example.com/files/'
+}
+
+func ExampleWithMethodCheck() {
+	allowed := map[string]bool{"GET": true, "POST": true, "PATCH": true}
+	check := func(method string) error {
+		if !allowed[method] {
+			return errors.New("not allowed by the API")
+		}
+		return nil
+	}
+
+	req, _ := http.NewRequest("DELETE", "http://example.com/users/42", nil)
+	_, err := GetCurlCommandWithOptions(req, WithMethodCheck(check))
+	fmt.Println(err)
+
+	req = &http.Request{Method: "BAD METHOD", URL: req.URL, Header: http.Header{}}
+	_, err = GetCurlCommandWithOptions(req, WithOutputVersion(OutputV3))
+	fmt.Println(err)
+
+	// Output:
+	// http2curl: method DELETE: not allowed by the API
+	// http2curl: invalid method "BAD METHOD"; methods are tokens such as GET or PATCH, without spaces or separators like ' '
+}
+
+func TestCheckMethodVersions(t *testing.T) {
+	u := &url.URL{Scheme: "http", Host: "example.com", Path: "/"}
+	tests := []struct {
+		method  string
+		version int
+		want    string
+	}{
+		{"", OutputV1, "curl -X '' 'http://example.com/'"},
+		{"FOO BAR", OutputV2, "curl -X 'FOO BAR' 'http://example.com/'"},
+		{"", OutputV3, "curl 'http://example.com/'"},
+		{"FOO BAR", OutputV3, `http2curl: invalid method "FOO BAR"; methods are tokens such as GET or PATCH, without spaces or separators like ' '`},
+	}
+	for _, tt := range tests {
+		req := &http.Request{Method: tt.method, URL: u, Header: http.Header{}}
+		got := ""
+		command, err := GetCurlCommandWithOptions(req, WithOutputVersion(tt.version))
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = command.String()
+		}
+		if got != tt.want {
+			t.Errorf("method %q at version %d: got %s, want %s", tt.method, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseCurlArgsMethods(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"curl", "-X", "PROPFIND", "http://example.com/"}, "PROPFIND"},
+		{[]string{"curl", "-XQUERY", "-d", "q", "http://example.com/"}, "QUERY"},
+		{[]string{"curl", "--request", "MKCOL", "http://example.com/"}, "MKCOL"},
+		{[]string{"curl", "-I", "http://example.com/"}, "HEAD"},
+		{[]string{"curl", "-d", "q", "http://example.com/"}, "POST"},
+	}
+	for _, test := range tests {
+		r, err := parseCurlArgs(test.argv)
+		if err != nil {
+			t.Errorf("parseCurlArgs(%q): %v", test.argv, err)
+			continue
+		}
+		if r.method != test.want {
+			t.Errorf("parseCurlArgs(%q) method is %s, want %s", test.argv, r.method, test.want)
+		}
+	}
+}
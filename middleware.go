@@ -0,0 +1,150 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Middleware logs the curl command reproducing each request received by
+// a server:
+//
+//	m := &http2curl.Middleware{Writer: os.Stderr}
+//	http.ListenAndServe(":8080", m.Handler(mux))
+//
+// The URL of the command is rebuilt from the scheme of the connection, or
+// the X-Forwarded-Proto header set by a proxy, r.Host and r.RequestURI.
+// The body is read in memory and replaced by a copy for the handler.
+// Failing to generate a command never fails the request. A Middleware
+// must not be copied after first use.
+type Middleware struct {
+	// Writer, when set, receives the commands, one per line.
+	Writer io.Writer
+	// Logger, when set, is called with the Capture of each request. It may
+	// be called concurrently.
+	Logger func(*Capture)
+	// Filter, when set, selects the requests to log.
+	Filter func(*http.Request) bool
+	// Options are used to generate the commands.
+	Options []Option
+	// Hardened applies the Hardened option after Options, for servers
+	// exposed to untrusted clients, and limits MaxBodyBytes to 1 MiB.
+	Hardened bool
+	// MaxBodyBytes, when positive, leaves out the requests whose body is
+	// larger, so that it is not read in memory.
+	MaxBodyBytes int64
+
+	mu sync.Mutex // serializes writes to Writer
+}
+
+// Handler returns a handler logging the requests before passing them to
+// next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (m.Writer != nil || m.Logger != nil) && (m.Filter == nil || m.Filter(r)) {
+			m.log(r)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hardenedMaxBodyBytes is the largest MaxBodyBytes of a hardened
+// Middleware.
+const hardenedMaxBodyBytes = 1 << 20
+
+func (m *Middleware) log(r *http.Request) {
+	if m.bodyTooLarge(r) {
+		return
+	}
+	u, err := serverURL(r)
+	if err != nil {
+		return
+	}
+	// the clone shares the body, which NewCapture replaces by a copy
+	req := r.Clone(r.Context())
+	req.URL = u
+	req.RequestURI = ""
+	// curl sets it from the body it sends, which options may change
+	req.Header.Del("Content-Length")
+	opts := m.Options
+	if r.RequestURI == "*" {
+		opts = append(opts[:len(opts):len(opts)], func(o *Options) { o.flags.flag("--request-target", argValue, "*") })
+	}
+	if m.Hardened {
+		opts = append(opts[:len(opts):len(opts)], Hardened())
+	}
+	capture, err := NewCapture(req, opts...)
+	r.Body = req.Body
+	if err != nil {
+		return
+	}
+	capture.Time = newOptions(opts).now()
+	if m.Writer != nil {
+		m.mu.Lock()
+		fmt.Fprintln(m.Writer, capture.Command)
+		m.mu.Unlock()
+	}
+	if m.Logger != nil {
+		m.Logger(capture)
+	}
+}
+
+// bodyTooLarge reports whether the body of r is larger than allowed,
+// reading at most the allowed size and one byte. The body is then replaced
+// so that the handler still reads all of it.
+func (m *Middleware) bodyTooLarge(r *http.Request) bool {
+	max := m.MaxBodyBytes
+	if m.Hardened && (max <= 0 || max > hardenedMaxBodyBytes) {
+		max = hardenedMaxBodyBytes
+	}
+	if max <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+	if r.ContentLength > max {
+		return true
+	}
+	prefix, err := io.ReadAll(io.LimitReader(r.Body, max+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+	return err != nil || int64(len(prefix)) > max
+}
+
+// serverURL returns the URL a client used to send the server request r.
+// The URL of an OPTIONS * request is the root of its host, log sending *
+// with --request-target.
+func serverURL(r *http.Request) (*url.URL, error) {
+	if r.URL.IsAbs() {
+		// absolute-form, as sent to proxies
+		u := *r.URL
+		return &u, nil
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		// proxies chained with a comma separated list, the first is the client's
+		proto = strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+		if proto == "http" || proto == "https" {
+			scheme = proto
+		}
+	}
+	requestURI := r.RequestURI
+	if requestURI == "" {
+		requestURI = r.URL.RequestURI()
+	}
+	if requestURI == "*" {
+		requestURI = "/"
+	}
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	return url.Parse(scheme + "://" + host + requestURI)
+}
@@ -0,0 +1,179 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// FromMitmproxy reads flows saved by mitmproxy and returns their requests,
+// ready to be given to GetCurlCommands. Both the native flow files, as
+// written by mitmdump -w or the save command, and the JSON flow lists of
+// mitmweb are read; HAR files exported by mitmproxy are read with FromHAR.
+// Flows other than HTTP ones, such as TCP or DNS flows, are skipped.
+// Bodies that mitmproxy did not keep are left empty, as in JSON lists.
+func FromMitmproxy(r io.Reader) ([]*http.Request, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var flows []interface{}
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FromHAR(bytes.NewReader(trimmed))
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var list []interface{}
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("http2curl: invalid mitmproxy flows: %v", err)
+		}
+		flows = list
+	default:
+		for rest := data; len(bytes.TrimSpace(rest)) > 0; {
+			var flow interface{}
+			if flow, rest, err = parseTNetString(bytes.TrimLeft(rest, "\r\n")); err != nil {
+				return nil, fmt.Errorf("http2curl: invalid mitmproxy flows: %v (flow %d)", err, len(flows))
+			}
+			flows = append(flows, flow)
+		}
+	}
+
+	var reqs []*http.Request
+	for i, flow := range flows {
+		f, ok := flow.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("http2curl: invalid mitmproxy flow (flow %d)", i)
+		}
+		if kind := flowString(f["type"]); kind != "" && kind != "http" {
+			continue
+		}
+		fr, ok := f["request"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("http2curl: mitmproxy flow without request (flow %d)", i)
+		}
+		req, err := flowRequest(fr)
+		if err != nil {
+			return nil, fmt.Errorf("%v (flow %d)", err, i)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// flowRequest returns the request of the request state fr of a flow.
+func flowRequest(fr map[string]interface{}) (*http.Request, error) {
+	scheme, host, path := flowString(fr["scheme"]), flowString(fr["host"]), flowString(fr["path"])
+	if authority := flowString(fr["authority"]); authority != "" {
+		host = authority
+	} else if port, ok := flowInt(fr["port"]); ok && !(scheme == "http" && port == 80 || scheme == "https" && port == 443) {
+		host = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	}
+	var body io.Reader
+	// mitmweb lists have no content, native flows may have none either
+	if content, ok := fr["content"].([]byte); ok && len(content) > 0 {
+		body = bytes.NewReader(content)
+	}
+	req, err := http.NewRequest(flowString(fr["method"]), scheme+"://"+host+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid mitmproxy request: %v", err)
+	}
+	headers, _ := fr["headers"].([]interface{})
+	for _, h := range headers {
+		pair, ok := h.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("http2curl: invalid mitmproxy header %v", h)
+		}
+		name, value := flowString(pair[0]), flowString(pair[1])
+		switch {
+		case name == "" || name[0] == ':', http.CanonicalHeaderKey(name) == "Content-Length":
+		case http.CanonicalHeaderKey(name) == "Host":
+			req.Host = value
+		default:
+			req.Header.Add(name, value)
+		}
+	}
+	return req, nil
+}
+
+// flowString returns v, a string or bytes of a flow, as a string.
+func flowString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// flowInt returns v, a number of a flow, as an int64.
+func flowInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case float64:
+		// JSON numbers
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// parseTNetString parses the tnetstring at the start of data, as written
+// by mitmproxy, and returns its value and the data after it. Byte strings
+// are []byte, text strings string, integers int64, floats float64,
+// lists []interface{} and dictionaries map[string]interface{}.
+func parseTNetString(data []byte) (interface{}, []byte, error) {
+	colon := bytes.IndexByte(data, ':')
+	if colon <= 0 || colon > 10 {
+		return nil, nil, fmt.Errorf("no length")
+	}
+	n, err := strconv.Atoi(string(data[:colon]))
+	if err != nil || n < 0 || colon+1+n >= len(data) {
+		return nil, nil, fmt.Errorf("invalid length %q", data[:colon])
+	}
+	payload, kind, rest := data[colon+1:colon+1+n], data[colon+1+n], data[colon+2+n:]
+	switch kind {
+	case ',':
+		return payload, rest, nil
+	case ';':
+		return string(payload), rest, nil
+	case '#':
+		i, err := strconv.ParseInt(string(payload), 10, 64)
+		return i, rest, err
+	case '^':
+		f, err := strconv.ParseFloat(string(payload), 64)
+		return f, rest, err
+	case '!':
+		return string(payload) == "true", rest, nil
+	case '~':
+		return nil, rest, nil
+	case ']':
+		list := []interface{}{}
+		for len(payload) > 0 {
+			var v interface{}
+			if v, payload, err = parseTNetString(payload); err != nil {
+				return nil, nil, err
+			}
+			list = append(list, v)
+		}
+		return list, rest, nil
+	case '}':
+		dict := map[string]interface{}{}
+		for len(payload) > 0 {
+			var k, v interface{}
+			if k, payload, err = parseTNetString(payload); err != nil {
+				return nil, nil, err
+			}
+			if v, payload, err = parseTNetString(payload); err != nil {
+				return nil, nil, err
+			}
+			dict[flowString(k)] = v
+		}
+		return dict, rest, nil
+	}
+	return nil, nil, fmt.Errorf("invalid type %q", kind)
+}
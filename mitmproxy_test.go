@@ -0,0 +1,80 @@
+package http2curl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tnetstring encodes v as mitmproxy writes flows.
+func tnetstring(v interface{}) string {
+	var payload, kind string
+	switch v := v.(type) {
+	case []byte:
+		payload, kind = string(v), ","
+	case string:
+		payload, kind = v, ";"
+	case int:
+		payload, kind = strconv.Itoa(v), "#"
+	case nil:
+		kind = "~"
+	case []interface{}:
+		for _, e := range v {
+			payload += tnetstring(e)
+		}
+		kind = "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			payload += tnetstring(k) + tnetstring(v[k])
+		}
+		kind = "}"
+	}
+	return strconv.Itoa(len(payload)) + ":" + payload + kind
+}
+
+func ExampleFromMitmproxy() {
+	flow := func(kind, method, path string, port int, content []byte) map[string]interface{} {
+		return map[string]interface{}{
+			"type": kind,
+			"request": map[string]interface{}{
+				"method":  []byte(method),
+				"scheme":  []byte("https"),
+				"host":    "api.example.com",
+				"port":    port,
+				"path":    []byte(path),
+				"headers": []interface{}{[]interface{}{[]byte("Host"), []byte("api.example.com")}, []interface{}{[]byte("Accept"), []byte("*/*")}},
+				"content": content,
+			},
+		}
+	}
+	// as written by mitmdump -w
+	flows := tnetstring(flow("http", "GET", "/items?page=2", 443, nil)) +
+		tnetstring(map[string]interface{}{"type": "tcp"}) +
+		tnetstring(flow("http", "POST", "/items", 8443, []byte(`{"name":"gopher"}`)))
+
+	reqs, err := FromMitmproxy(strings.NewReader(flows))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	commands, _ := GetCurlCommands(reqs)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+
+	// mitmweb flow lists
+	reqs, _ = FromMitmproxy(strings.NewReader(`[{"type": "http", "request": {"method": "DELETE", "scheme": "http", "host": "10.0.0.1", "port": 8080, "path": "/items/1", "headers": [["User-Agent", "curl/8.0"]]}}]`))
+	command, _ := GetCurlCommand(reqs[0])
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -H 'Accept: */*' 'https://api.example.com/items?page=2'
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Accept: */*' 'https://api.example.com:8443/items'
+	// curl -X 'DELETE' -H 'User-Agent: curl/8.0' 'http://10.0.0.1:8080/items/1'
+}
@@ -0,0 +1,362 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// checkRequest is a request as configured in a monitoring check.
+type checkRequest struct {
+	method   string
+	target   string
+	headers  [][2]string
+	body     string
+	bodyFile string
+	hasBody  bool
+	insecure bool
+	redirect bool
+}
+
+// checkRequest reads the request of args for the monitoring tool, with
+// placeholders rendered by variable, or left as they are when it is nil.
+func (o *Options) checkRequest(args argList, tool string, variable func(name string) string) checkRequest {
+	var (
+		r       checkRequest
+		cookies []string
+	)
+	str := func(s string) string {
+		if variable == nil {
+			return s
+		}
+		return o.httpFileString(s, variable)
+	}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argURL:
+			r.target = str(a.value)
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by "+tool)
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch a.value {
+		case "-X":
+			r.method = value.value
+		case "--head":
+			r.method = http.MethodHead
+		case "-H":
+			name, v, _ := strings.Cut(value.value, ":")
+			r.headers = append(r.headers, [2]string{name, str(strings.TrimLeft(v, " "))})
+		case "--cookie":
+			cookies = append(cookies, str(value.value))
+		case "-k", "--insecure":
+			r.insecure = true
+		case "-L", "--location":
+			r.redirect = true
+		case "--url", "-s", "-sS":
+		default:
+			switch {
+			case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+				r.bodyFile, r.hasBody = value.value[1:], true
+			case dataFlags[a.value]:
+				r.body, r.hasBody = str(value.value), true
+			default:
+				o.loss(LossDropped, "flag "+a.value, "not supported by "+tool)
+			}
+		}
+	}
+	if len(cookies) > 0 {
+		r.headers = append(r.headers, [2]string{"Cookie", strings.Join(cookies, "; ")})
+	}
+	if r.method == "" {
+		r.method = http.MethodGet
+		if r.hasBody {
+			r.method = http.MethodPost
+		}
+	}
+	return r
+}
+
+// checkName names the check of req after its method and path, such as
+// post_users_id for POST /users/id.
+func checkName(req *http.Request) string {
+	name := strings.ToLower(req.Method)
+	for _, segment := range strings.FieldsFunc(req.URL.Path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		name += "_" + strings.ToLower(segment)
+	}
+	return name
+}
+
+// GetBlackboxModule returns the configuration of a Prometheus Blackbox
+// exporter module probing with req, named after its method and path, for
+// the modules section of blackbox.yml. The URL, which Blackbox takes as
+// the target of the scrape, is written in a comment above the module.
+// When resp is not nil, its status is the only one accepted, otherwise any
+// 2xx status is. Redirects are followed only when -L is among the flags,
+// as curl does. It is generated from the same arguments as the curl
+// command, so options apply alike; placeholders are written as ${NAME},
+// for the configuration to be expanded before use, and curl flags without
+// an equivalent are reported to the loss callback.
+func GetBlackboxModule(req *http.Request, resp *http.Response, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	r := o.checkRequest(args, "Blackbox", func(name string) string { return "${" + name + "}" })
+	if r.bodyFile != "" {
+		o.loss(LossDropped, "body", "Blackbox cannot read the file "+r.bodyFile)
+	}
+
+	var b strings.Builder
+	b.WriteString("# target: " + r.target + "\n")
+	b.WriteString(yamlString(checkName(req)) + ":\n")
+	b.WriteString("  prober: http\n")
+	b.WriteString("  http:\n")
+	b.WriteString("    method: " + yamlString(r.method) + "\n")
+	if len(r.headers) > 0 {
+		headers := map[string]string{}
+		for _, h := range r.headers {
+			headers[h[0]] = h[1]
+		}
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b.WriteString("    headers:\n")
+		for _, name := range names {
+			b.WriteString("      " + yamlString(name) + ": " + yamlString(headers[name]) + "\n")
+		}
+	}
+	if r.body != "" {
+		b.WriteString("    body: " + yamlString(r.body) + "\n")
+	}
+	if resp != nil {
+		b.WriteString("    valid_status_codes:\n")
+		b.WriteString("      - " + strconv.Itoa(resp.StatusCode) + "\n")
+	}
+	b.WriteString("    follow_redirects: " + strconv.FormatBool(r.redirect) + "\n")
+	if r.insecure {
+		b.WriteString("    tls_config:\n")
+		b.WriteString("      insecure_skip_verify: true\n")
+	}
+	return b.String(), nil
+}
+
+// yamlPlainRe matches the strings written as plain YAML scalars: they
+// start with a letter or a slash, hold no comment or mapping indicator and
+// do not end with a space.
+var yamlPlainRe = regexp.MustCompile(`^[A-Za-z/][\x20-\x7e]*$`)
+
+// yamlString returns s as a YAML scalar: plain when it reads back as the
+// same string, single-quoted when it is printable, and double-quoted with
+// escapes otherwise.
+func yamlString(s string) string {
+	switch lower := strings.ToLower(s); {
+	case yamlPlainRe.MatchString(s) && !strings.Contains(s, ": ") && !strings.Contains(s, " #") &&
+		!strings.HasSuffix(s, ":") && !strings.HasSuffix(s, " ") &&
+		lower != "true" && lower != "false" && lower != "yes" && lower != "no" &&
+		lower != "on" && lower != "off" && lower != "null" && lower != "y" && lower != "n":
+		return s
+	case utf8.ValidString(s) && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) < 0:
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	// JSON strings are double-quoted YAML scalars
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ChecklyCheck is an API check of Checkly, to be encoded as JSON.
+type ChecklyCheck struct {
+	Name      string         `json:"name"`
+	CheckType string         `json:"checkType"`
+	Frequency int            `json:"frequency"`
+	Activated bool           `json:"activated"`
+	Request   ChecklyRequest `json:"request"`
+}
+
+// ChecklyRequest is the request of a ChecklyCheck.
+type ChecklyRequest struct {
+	Method          string             `json:"method"`
+	URL             string             `json:"url"`
+	Headers         []ChecklyKeyValue  `json:"headers"`
+	Body            string             `json:"body,omitempty"`
+	BodyType        string             `json:"bodyType"`
+	FollowRedirects bool               `json:"followRedirects"`
+	SkipSSL         bool               `json:"skipSSL"`
+	Assertions      []ChecklyAssertion `json:"assertions"`
+}
+
+// ChecklyKeyValue is a header of a ChecklyRequest.
+type ChecklyKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ChecklyAssertion is an assertion on the response of a ChecklyCheck.
+type ChecklyAssertion struct {
+	Source     string `json:"source"`
+	Comparison string `json:"comparison"`
+	Target     string `json:"target"`
+}
+
+// ToChecklyCheck returns a Checkly API check sending req every 10
+// minutes, named after its method and path. When resp is not nil, the
+// check asserts its status, otherwise that the status is below 400.
+// Redirects are followed only when -L is among the flags, as curl does.
+// It is generated from the same arguments as the curl command, so options
+// apply alike; placeholders become {{NAME}} environment variables, and
+// curl flags without an equivalent are reported to the loss callback.
+func ToChecklyCheck(req *http.Request, resp *http.Response, opts ...Option) (*ChecklyCheck, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return nil, err
+	}
+	r := o.checkRequest(args, "Checkly", func(name string) string { return "{{" + name + "}}" })
+	if r.bodyFile != "" {
+		o.loss(LossDropped, "body", "Checkly cannot read the file "+r.bodyFile)
+	}
+
+	request := ChecklyRequest{
+		Method:          r.method,
+		URL:             r.target,
+		Headers:         []ChecklyKeyValue{},
+		Body:            r.body,
+		BodyType:        "NONE",
+		FollowRedirects: r.redirect,
+		SkipSSL:         r.insecure,
+		Assertions:      []ChecklyAssertion{{Source: "STATUS_CODE", Comparison: "LESS_THAN", Target: "400"}},
+	}
+	contentType := ""
+	for _, h := range r.headers {
+		if strings.EqualFold(h[0], "Content-Type") {
+			contentType = h[1]
+		}
+		request.Headers = append(request.Headers, ChecklyKeyValue{Key: h[0], Value: h[1]})
+	}
+	switch {
+	case r.body == "":
+	case strings.Contains(contentType, "json"):
+		request.BodyType = "JSON"
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		request.BodyType = "FORM"
+	case strings.Contains(contentType, "graphql"):
+		request.BodyType = "GRAPHQL"
+	default:
+		request.BodyType = "RAW"
+	}
+	if resp != nil {
+		request.Assertions = []ChecklyAssertion{{Source: "STATUS_CODE", Comparison: "EQUALS", Target: strconv.Itoa(resp.StatusCode)}}
+	}
+	return &ChecklyCheck{
+		Name:      req.Method + " " + req.URL.EscapedPath(),
+		CheckType: "API",
+		Frequency: 10,
+		Activated: true,
+		Request:   request,
+	}, nil
+}
+
+// GetTerraformHTTPData returns a Terraform data "http" block sending req,
+// named after its method and path, to check an endpoint from a Terraform
+// run. When resp is not nil, a postcondition checks its status. It is
+// generated from the same arguments as the curl command, so options apply
+// alike; placeholders become references to Terraform variables, such as
+// var.TOKEN, bodies read by curl from a file are read with file(), and
+// curl flags without an equivalent are reported to the loss callback.
+func GetTerraformHTTPData(req *http.Request, resp *http.Response, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	r := o.checkRequest(args, "Terraform", nil)
+	if r.redirect {
+		o.loss(LossDropped, "flag -L", "Terraform always follows redirects")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "data \"http\" %s {\n", hclString(checkName(req)))
+	fmt.Fprintf(&b, "  url    = %s\n", o.hclString(r.target))
+	fmt.Fprintf(&b, "  method = %s\n", hclString(r.method))
+	if len(r.headers) > 0 {
+		b.WriteString("\n  request_headers = {\n")
+		sort.SliceStable(r.headers, func(i, j int) bool { return r.headers[i][0] < r.headers[j][0] })
+		// aligned as terraform fmt does
+		width := 0
+		for _, h := range r.headers {
+			if n := len(hclString(h[0])); n > width {
+				width = n
+			}
+		}
+		for _, h := range r.headers {
+			fmt.Fprintf(&b, "    %-*s = %s\n", width, hclString(h[0]), o.hclString(h[1]))
+		}
+		b.WriteString("  }\n")
+	}
+	switch {
+	case r.bodyFile != "":
+		fmt.Fprintf(&b, "\n  request_body = file(%s)\n", hclString(r.bodyFile))
+	case r.hasBody:
+		fmt.Fprintf(&b, "\n  request_body = %s\n", o.hclString(r.body))
+	}
+	if r.insecure {
+		b.WriteString("\n  insecure = true\n")
+	}
+	if resp != nil {
+		b.WriteString("\n  lifecycle {\n    postcondition {\n")
+		fmt.Fprintf(&b, "      condition     = self.status_code == %d\n", resp.StatusCode)
+		fmt.Fprintf(&b, "      error_message = %s\n", hclString(fmt.Sprintf("%s %s did not return %d", r.method, req.URL.EscapedPath(), resp.StatusCode)))
+		b.WriteString("    }\n  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// hclEscaper escapes the sequences starting Terraform interpolations and
+// directives.
+var hclEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// hclString quotes s as a Terraform string.
+func hclString(s string) string {
+	return hclEscaper.Replace(jsonString(s))
+}
+
+// hclString quotes str as a Terraform string, with placeholders as
+// references to Terraform variables.
+func (o *Options) hclString(str string) string {
+	var b strings.Builder
+	b.WriteString(`"`)
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("${var." + seg.text + "}")
+		} else {
+			quoted := hclString(seg.text)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func ExampleGetBlackboxModule() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/health", strings.NewReader(`{"deep":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc123")
+	resp := &http.Response{StatusCode: http.StatusOK}
+
+	module, _ := GetBlackboxModule(req, resp, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"))
+	fmt.Print(module)
+
+	// Output:
+	// # target: https://api.example.com/v1/health
+	// post_v1_health:
+	//   prober: http
+	//   http:
+	//     method: POST
+	//     headers:
+	//       Authorization: Bearer ${TOKEN}
+	//       Content-Type: application/json
+	//     body: '{"deep":true}'
+	//     valid_status_codes:
+	//       - 200
+	//     follow_redirects: false
+}
+
+func ExampleToChecklyCheck() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/health", strings.NewReader(`{"deep":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	check, _ := ToChecklyCheck(req, nil, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"))
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	enc.Encode(check)
+
+	// Output:
+	// {
+	//   "name": "POST /v1/health",
+	//   "checkType": "API",
+	//   "frequency": 10,
+	//   "activated": true,
+	//   "request": {
+	//     "method": "POST",
+	//     "url": "https://api.example.com/v1/health",
+	//     "headers": [
+	//       {
+	//         "key": "Authorization",
+	//         "value": "Bearer {{TOKEN}}"
+	//       },
+	//       {
+	//         "key": "Content-Type",
+	//         "value": "application/json"
+	//       }
+	//     ],
+	//     "body": "{\"deep\":true}",
+	//     "bodyType": "JSON",
+	//     "followRedirects": false,
+	//     "skipSSL": false,
+	//     "assertions": [
+	//       {
+	//         "source": "STATUS_CODE",
+	//         "comparison": "LESS_THAN",
+	//         "target": "400"
+	//       }
+	//     ]
+	//   }
+	// }
+}
+
+func ExampleGetTerraformHTTPData() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/health", strings.NewReader(`{"template":"${name}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc123")
+	resp := &http.Response{StatusCode: http.StatusOK}
+
+	data, _ := GetTerraformHTTPData(req, resp, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "token"))
+	fmt.Print(data)
+
+	// Output:
+	// data "http" "post_v1_health" {
+	//   url    = "https://api.example.com/v1/health"
+	//   method = "POST"
+	//
+	//   request_headers = {
+	//     "Authorization" = "Bearer ${var.token}"
+	//     "Content-Type"  = "application/json"
+	//   }
+	//
+	//   request_body = "{\"template\":\"$${name}\"}"
+	//
+	//   lifecycle {
+	//     postcondition {
+	//       condition     = self.status_code == 200
+	//       error_message = "POST /v1/health did not return 200"
+	//     }
+	//   }
+	// }
+}
+
+func TestYAMLString(t *testing.T) {
+	for s, want := range map[string]string{
+		"application/json": "application/json",
+		"Bearer ${TOKEN}":  "Bearer ${TOKEN}",
+		"":                 "''",
+		"true":             "'true'",
+		"200":              "'200'",
+		"a: b":             "'a: b'",
+		"a #b":             "'a #b'",
+		"it's":             "it's",
+		"'quoted'":         "'''quoted'''",
+		"-1":               "'-1'",
+		"a\nb":             `"a\nb"`,
+		"tab\there":        `"tab\there"`,
+	} {
+		if got := yamlString(s); got != want {
+			t.Errorf("yamlString(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
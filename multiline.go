@@ -0,0 +1,53 @@
+package http2curl
+
+import "strings"
+
+// MultilineString returns the command split over several lines joined by
+// backslash continuations, ready to paste into a terminal: the program
+// and its method first, then each flag with its value on a line of its
+// own, one -H per line, and the URL last. It only works on commands
+// rendered without WithColor.
+func (c *CurlCommand) MultilineString() string {
+	return c.MultilineStringFor(posixShell{})
+}
+
+// MultilineStringFor is like MultilineString for commands rendered for
+// the shell s, whose continuation ends the lines.
+func (c *CurlCommand) MultilineStringFor(s Shell) string {
+	tokens := *c
+	var b strings.Builder
+	i := 0
+	// leading comments and prompts are already lines of their own
+	for ; i < len(tokens) && strings.HasSuffix(tokens[i], "\n"); i++ {
+		b.WriteString(tokens[i])
+	}
+
+	var lines [][]string
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case len(lines) == 0:
+			lines = append(lines, []string{token})
+		case token == "|" || strings.HasPrefix(token, "#") || strings.HasPrefix(token, "& REM "):
+			// pipelines and trailing comments stay after the URL
+			lines[len(lines)-1] = append(lines[len(lines)-1], tokens[i:]...)
+			i = len(tokens)
+		case valueFlags[token] && i+1 < len(tokens):
+			line := []string{token, tokens[i+1]}
+			i++
+			if (token == "-X" || token == "--request") && len(lines) == 1 {
+				lines[0] = append(lines[0], line...)
+				continue
+			}
+			lines = append(lines, line)
+		default:
+			lines = append(lines, []string{token})
+		}
+	}
+	joined := make([]string, len(lines))
+	for i, line := range lines {
+		joined[i] = s.Join(line)
+	}
+	b.WriteString(strings.Join(joined, s.Continuation()+"\n  "))
+	return b.String()
+}
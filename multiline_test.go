@@ -0,0 +1,30 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleCurlCommand_MultilineString() {
+	req, _ := http.NewRequest("PUT", "http://example.com/items/1", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "42")
+
+	command, _ := Command(req, nil, WithAnalysis(), ForScripting(), WithHistoryComment())
+	fmt.Println(command.MultilineString())
+
+	// Output:
+	// # request size: 67 bytes (headers 50 bytes, body 17 bytes)
+	// # cookies: 0
+	// # largest header: Content-Type (32 bytes)
+	// curl -X 'PUT' \
+	//   -d '{"name":"gopher"}' \
+	//   -H 'Content-Type: application/json' \
+	//   -H 'X-Request-Id: 42' \
+	//   --fail-with-body \
+	//   -sS \
+	//   --retry '3' \
+	//   --retry-connrefused \
+	//   'http://example.com/items/1'
+}
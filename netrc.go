@@ -0,0 +1,61 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var netrcNameRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// WithNetrc removes basic-auth credentials, from the Authorization header
+// and from the URL, and makes curl read them from ~/.netrc-<service>
+// instead, keeping passwords out of the shell history. Use NetrcEntry to
+// generate the matching line of that file. The service name may only
+// contain letters, digits, '.', '_' and '-'.
+func WithNetrc(service string) Option {
+	return func(o *Options) {
+		if !netrcNameRe.MatchString(service) {
+			o.err = fmt.Errorf("http2curl: invalid netrc service name %q", service)
+			return
+		}
+		o.netrc = service
+	}
+}
+
+// NetrcEntry returns the .netrc line holding the basic-auth credentials of
+// req, taken from its Authorization header or from its URL.
+func NetrcEntry(req *http.Request) (string, error) {
+	user, pass, ok := req.BasicAuth()
+	if !ok && req.URL.User != nil {
+		user = req.URL.User.Username()
+		pass, _ = req.URL.User.Password()
+		ok = true
+	}
+	if !ok {
+		return "", fmt.Errorf("http2curl: request has no basic-auth credentials")
+	}
+	return fmt.Sprintf("machine %s login %s password %s\n", req.URL.Hostname(), netrcQuote(user), netrcQuote(pass)), nil
+}
+
+// netrcQuote quotes tokens that would otherwise be split, a syntax curl
+// understands since 7.84.
+func netrcQuote(token string) string {
+	if token != "" && !strings.ContainsAny(token, " \t\n\"\\") {
+		return token
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(token) + `"`
+}
+
+// stripBasicAuth removes basic-auth credentials from req.
+func (o *Options) stripBasicAuth(req *http.Request) {
+	if _, _, ok := req.BasicAuth(); ok {
+		req.Header.Del("Authorization")
+		o.loss(LossDropped, "header Authorization", "credentials left to the netrc file")
+	}
+	if req.URL.User != nil {
+		o.loss(LossDropped, "url", "credentials left to the netrc file")
+	}
+	req.URL.User = nil
+}
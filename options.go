@@ -0,0 +1,297 @@
+package http2curl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Option changes how a CurlCommand is generated.
+type Option func(*Options)
+
+// Options holds the settings used while generating a CurlCommand.
+// The zero value reproduces the default output; use the With* helpers
+// to change it.
+type Options struct {
+	wrapWidth           int
+	color               bool
+	analyze             bool
+	flags               argList
+	ctx                 context.Context
+	netrc               string
+	leadingSpace        bool
+	historyComment      bool
+	quoteStyle          QuoteStyle
+	dataFlag            DataFlag
+	selfCheck           bool
+	pipe                argList
+	placeholders        []placeholder
+	prompts             argList
+	formatTime          func(time.Time) string
+	formatSize          func(int64) string
+	timestamp           time.Time
+	spillBytes          int
+	spill               func([]byte) (string, error)
+	curlVersion         [3]int
+	lossCallback        func(LossEvent)
+	cookieFlags         bool
+	jar                 http.CookieJar
+	headerFilter        func(string) bool
+	bodyLimit           int
+	headFlag            bool
+	methodCheck         func(method string) error
+	methodWarnings      bool
+	jetBrainsVariables  bool
+	sortedQuery         bool
+	normalizedQuery     bool
+	tags                map[string]string
+	methodOverride      MethodOverride
+	headerCasing        map[string]string
+	signedURLWarning    bool
+	headerPerValue      bool
+	contentLength       bool
+	prettyJSON          bool
+	compressionAdvice   bool
+	compressedFlag      bool
+	outputVersion       int
+	upstreamCompat      bool
+	cacheBust           bool
+	dryRunHeader        string
+	checkContentType    bool
+	fixContentType      bool
+	headerOverrides     map[string]string
+	redactHeaders       map[string]bool
+	redactor            func(string, string) (string, bool)
+	comments            []string
+	shell               shellSyntax
+	rejectControl       bool
+	bodyFile            string
+	clock               Clock
+	random              io.Reader
+	invalidHeaderPolicy InvalidHeaderPolicy
+	scheme              string
+
+	// err is reported when generating, for options given invalid values
+	err error
+}
+
+// ResolveOptions applies opts in order and returns the resulting settings,
+// so tools can record which settings produced a command.
+func ResolveOptions(opts ...Option) *Options { return newOptions(opts) }
+
+// Resolved returns the settings as a map keyed by setting name. Settings
+// left at their default value are included too.
+func (o *Options) Resolved() map[string]interface{} {
+	flags := []string{}
+	for _, a := range o.flags {
+		flags = append(flags, a.value)
+	}
+	headerOverrides := map[string]string{}
+	for name, value := range o.headerOverrides {
+		headerOverrides[name] = value
+	}
+	headerCasing := []string{}
+	for _, name := range o.headerCasing {
+		headerCasing = append(headerCasing, name)
+	}
+	sort.Strings(headerCasing)
+	redactHeaders := []string{}
+	for name := range o.redactHeaders {
+		redactHeaders = append(redactHeaders, name)
+	}
+	sort.Strings(redactHeaders)
+	spillBytes := -1
+	if o.spill != nil {
+		spillBytes = o.spillBytes
+	}
+	curlVersion := ""
+	if o.curlVersion != [3]int{} {
+		curlVersion = fmt.Sprintf("%d.%d.%d", o.curlVersion[0], o.curlVersion[1], o.curlVersion[2])
+	}
+	timestamp := ""
+	if !o.timestamp.IsZero() {
+		timestamp = o.timeString(o.timestamp)
+	}
+	prompts := []string{}
+	for _, a := range o.prompts {
+		prompts = append(prompts, a.value)
+	}
+	pipe := []string{}
+	for _, a := range o.pipe {
+		pipe = append(pipe, a.value)
+	}
+	placeholders := []string{}
+	for _, p := range o.placeholders {
+		placeholders = append(placeholders, p.name+"="+p.re.String())
+	}
+	return map[string]interface{}{
+		"wrap_width":            o.wrapWidth,
+		"color":                 o.color,
+		"analysis":              o.analyze,
+		"flags":                 flags,
+		"pipe":                  pipe,
+		"netrc":                 o.netrc,
+		"leading_space":         o.leadingSpace,
+		"history_comment":       o.historyComment,
+		"quote_style":           o.quoteStyle.String(),
+		"data_flag":             string(o.dataFlag),
+		"self_check":            o.selfCheck,
+		"placeholders":          placeholders,
+		"prompts":               prompts,
+		"custom_time_format":    o.formatTime != nil,
+		"custom_size_format":    o.formatSize != nil,
+		"timestamp":             timestamp,
+		"header_spill_bytes":    spillBytes,
+		"curl_version":          curlVersion,
+		"loss_callback":         o.lossCallback != nil,
+		"cookie_flags":          o.cookieFlags,
+		"cookie_jar":            o.jar != nil,
+		"header_filter":         o.headerFilter != nil,
+		"body_limit":            o.bodyLimit,
+		"head_flag":             o.headFlag,
+		"method_check":          o.methodCheck != nil,
+		"method_warnings":       o.methodWarnings,
+		"jetbrains_variables":   o.jetBrainsVariables,
+		"sorted_query":          o.sortedQuery,
+		"normalized_query":      o.normalizedQuery,
+		"tags":                  formatTags(o.tags),
+		"method_override":       o.methodOverride.String(),
+		"header_casing":         headerCasing,
+		"signed_url_warning":    o.signedURLWarning,
+		"header_per_value":      o.headerPerValue,
+		"content_length":        o.contentLength,
+		"pretty_json":           o.prettyJSON,
+		"compression_advice":    o.compressionAdvice,
+		"compressed_flag":       o.compressedFlag,
+		"output_version":        o.outputVersion,
+		"upstream_compat":       o.upstreamCompat,
+		"cache_bust":            o.cacheBust,
+		"dry_run_header":        o.dryRunHeader,
+		"check_content_type":    o.checkContentType,
+		"fix_content_type":      o.fixContentType,
+		"header_overrides":      headerOverrides,
+		"redacted_headers":      redactHeaders,
+		"redactor":              o.redactor != nil,
+		"comments":              append([]string{}, o.comments...),
+		"shell":                 o.shell.name(),
+		"reject_control":        o.rejectControl,
+		"body_file":             o.bodyFile,
+		"clock":                 o.clock != nil,
+		"random":                o.random != nil,
+		"invalid_header_policy": o.invalidHeaderPolicy.String(),
+		"scheme":                o.scheme,
+	}
+}
+
+// MarshalJSON encodes the resolved settings as a JSON object.
+func (o *Options) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Resolved())
+}
+
+func newOptions(opts []Option) *Options {
+	o := &Options{outputVersion: OutputV1, shell: posixShell{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithCookieJar adds the cookies of jar for the request URL to the
+// command, as Command does with its jar argument.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(o *Options) { o.jar = jar }
+}
+
+// WithHeaderFilter only keeps the headers for which keep returns true. It
+// is given canonical header names, after the other options have added or
+// changed headers.
+func WithHeaderFilter(keep func(name string) bool) Option {
+	return func(o *Options) { o.headerFilter = keep }
+}
+
+// WithBodyLimit truncates request bodies to maxBytes, with a comment above
+// the command telling the original size. It keeps commands for large
+// uploads readable, at the cost of not replaying them faithfully.
+func WithBodyLimit(maxBytes int) Option {
+	return func(o *Options) {
+		if maxBytes <= 0 {
+			o.err = fmt.Errorf("http2curl: invalid body limit %d", maxBytes)
+			return
+		}
+		o.bodyLimit = maxBytes
+	}
+}
+
+// WithWrapLongValues splits quoted values longer than width bytes across
+// continuation lines. The pieces are quoted separately and joined with a
+// backslash-newline, so the shell concatenates them back into the exact
+// original value. A width of zero or less disables wrapping.
+func WithWrapLongValues(width int) Option {
+	return func(o *Options) { o.wrapWidth = width }
+}
+
+// context returns the context generation runs under.
+func (o *Options) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// quote escapes str for the shell, wrapping it if requested and turning
+// placeholders into variable references.
+func (o *Options) quote(str string) string {
+	if len(o.placeholders) == 0 {
+		return o.quoteLiteral(str)
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString(o.shell.variable(seg.text))
+		} else {
+			b.WriteString(o.quoteLiteral(seg.text))
+		}
+	}
+	return b.String()
+}
+
+// quoteLiteral escapes str, which holds no placeholder, for the shell.
+func (o *Options) quoteLiteral(str string) string {
+	_, posix := o.shell.(posixShell)
+	if !posix || o.wrapWidth <= 0 || len(str) <= o.wrapWidth {
+		return o.shell.quote(o.quoteStyle, str)
+	}
+	var parts []string
+	for _, chunk := range splitWidth(str, o.wrapWidth) {
+		parts = append(parts, o.shell.quote(o.quoteStyle, chunk))
+	}
+	// no indentation after the continuation, leading blanks would split the word
+	return strings.Join(parts, "\\\n")
+}
+
+// splitWidth cuts str into chunks of at most width bytes without
+// splitting a multi-byte rune.
+func splitWidth(str string, width int) []string {
+	var chunks []string
+	for len(str) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(str[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			// a single rune wider than width, keep it whole
+			_, cut = utf8.DecodeRuneInString(str)
+		}
+		chunks = append(chunks, str[:cut])
+		str = str[cut:]
+	}
+	return append(chunks, str)
+}
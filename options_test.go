@@ -0,0 +1,47 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithWrapLongValues() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("Cookie", "session=0123456789abcdefghijklmnopqrstuvwxyz")
+
+	command, _ := Command(req, nil, WithWrapLongValues(20))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -H 'Cookie: session=0123'\
+	// '456789abcdefghijklmn'\
+	// 'opqrstuvwxyz' 'http://example.com/'
+}
+
+func ExampleWithWrapLongValues_singleQuote() {
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader("it's a long body"))
+
+	command, _ := Command(req, nil, WithWrapLongValues(8))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'POST' -d 'it'\''s a l'\
+	// 'ong body' 'http://e'\
+	// 'xample.c'\
+	// 'om/'
+}
+
+func ExampleOptions_Resolved() {
+	o := ResolveOptions(WithWrapLongValues(80), ForInteractive())
+	resolved := o.Resolved()
+	fmt.Println(resolved["wrap_width"], resolved["flags"])
+
+	b, _ := json.Marshal(resolved["flags"])
+	fmt.Println(string(b))
+
+	// Output:
+	// 80 [-v]
+	// ["-v"]
+}
@@ -0,0 +1,119 @@
+package http2curl
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// methodOverrideHeaders are the headers used to tunnel a method through
+// POST requests, by order of preference.
+var methodOverrideHeaders = []string{"X-HTTP-Method-Override", "X-HTTP-Method", "X-Method-Override"}
+
+// MethodOverride tells how requests tunneling their method through a
+// method override header or a _method form field are rendered.
+type MethodOverride int
+
+const (
+	// MethodOverrideKeep renders the request as it is.
+	MethodOverrideKeep MethodOverride = iota
+	// MethodOverrideComment renders the request as it is, with a comment
+	// telling the effective method.
+	MethodOverrideComment
+	// MethodOverrideNormalize sends the effective method itself, without
+	// the override header or form field, for gateways that ignore or
+	// reject overrides.
+	MethodOverrideNormalize
+)
+
+func (m MethodOverride) String() string {
+	switch m {
+	case MethodOverrideKeep:
+		return "keep"
+	case MethodOverrideComment:
+		return "comment"
+	case MethodOverrideNormalize:
+		return "normalize"
+	}
+	return "unknown"
+}
+
+// WithMethodOverride sets how requests overriding their method, with an
+// X-HTTP-Method-Override header or the like, or with a _method field in a
+// form body, are rendered. They are kept as they are by default.
+func WithMethodOverride(mode MethodOverride) Option {
+	return func(o *Options) { o.methodOverride = mode }
+}
+
+// applyMethodOverride applies the method override mode to req and its
+// body, and returns the body to send.
+func (o *Options) applyMethodOverride(req *http.Request, body []byte, notes *argList) []byte {
+	if o.methodOverride == MethodOverrideKeep {
+		return body
+	}
+	method, header := "", ""
+	for _, name := range methodOverrideHeaders {
+		if v := req.Header.Get(name); v != "" {
+			method, header = strings.ToUpper(strings.TrimSpace(v)), name
+			break
+		}
+	}
+	field := false
+	if method == "" {
+		if v, ok := formMethod(req, body); ok {
+			method, field = strings.ToUpper(v), true
+		}
+	}
+	if method == "" || method == req.Method {
+		return body
+	}
+
+	source := header + " header"
+	if field {
+		source = "_method form field"
+	}
+	if o.methodOverride == MethodOverrideComment {
+		notes.comment("effective method: " + method + " (" + source + ")")
+		return body
+	}
+
+	o.loss(LossRewritten, "method", req.Method+" normalized to "+method+" from the "+source)
+	req.Method = method
+	if field {
+		return removeFormField(body, "_method")
+	}
+	req.Header.Del(header)
+	return body
+}
+
+// formMethod returns the _method field of a form body.
+func formMethod(req *http.Request, body []byte) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/x-www-form-urlencoded" {
+		return "", false
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil || values.Get("_method") == "" {
+		return "", false
+	}
+	return values.Get("_method"), true
+}
+
+// removeFormField removes the name fields from a form body, leaving the
+// other fields as they are.
+func removeFormField(body []byte, name string) []byte {
+	var kept [][]byte
+	for _, pair := range bytes.Split(body, []byte("&")) {
+		key := string(pair)
+		if i := strings.IndexByte(key, '='); i >= 0 {
+			key = key[:i]
+		}
+		if k, err := url.QueryUnescape(key); err == nil && k == name {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return bytes.Join(kept, []byte("&"))
+}
@@ -0,0 +1,33 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithMethodOverride() {
+	req, _ := http.NewRequest("POST", "http://example.com/items/1", nil)
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+
+	command, _ := Command(req, nil, WithMethodOverride(MethodOverrideComment))
+	fmt.Println(command)
+	command, _ = Command(req, nil, WithMethodOverride(MethodOverrideNormalize))
+	fmt.Println(command)
+
+	// Output:
+	// # effective method: DELETE (X-HTTP-Method-Override header)
+	// curl -X 'POST' -H 'X-Http-Method-Override: DELETE' 'http://example.com/items/1'
+	// curl -X 'DELETE' 'http://example.com/items/1'
+}
+
+func ExampleWithMethodOverride_form() {
+	req, _ := http.NewRequest("POST", "http://example.com/items/1", strings.NewReader("_method=PUT&name=gopher"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	command, _ := Command(req, nil, WithMethodOverride(MethodOverrideNormalize))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'PUT' -d 'name=gopher' -H 'Content-Type: application/x-www-form-urlencoded' 'http://example.com/items/1'
+}
@@ -0,0 +1,96 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// paginationParams are the query parameters recognized by
+// PaginationScript, by order of preference.
+var paginationParams = []string{"cursor", "page", "offset"}
+
+// pageSizeParams are the query parameters giving the size of a page, used
+// to step offsets.
+var pageSizeParams = []string{"limit", "per_page", "page_size", "size", "count"}
+
+// PaginationScript returns a bash loop template fetching every page of
+// req, for requests whose URL has a cursor, page or offset query
+// parameter. The parameter is replaced by a shell variable advanced after
+// each response; the stop conditions rely on jq and are meant to be
+// adjusted to the API.
+func PaginationScript(req *http.Request, opts ...Option) (string, error) {
+	query := req.URL.Query()
+	param := ""
+	for _, p := range paginationParams {
+		if _, ok := query[p]; ok {
+			param = p
+			break
+		}
+	}
+	if param == "" {
+		return "", fmt.Errorf("http2curl: no pagination parameter (%s) in URL", strings.Join(paginationParams, ", "))
+	}
+
+	re := regexp.MustCompile(`[?&]` + regexp.QuoteMeta(param) + `=([^&#]*)`)
+	opts = append(opts[:len(opts):len(opts)], func(o *Options) {
+		o.placeholders = append(o.placeholders, placeholder{re: re, name: param})
+	})
+	command, err := Command(req, nil, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\nset -euo pipefail\n\n")
+	switch param {
+	case "cursor":
+		fmt.Fprintf(&b, "cursor=%s\n", bashEscape(query.Get(param)))
+	case "page":
+		fmt.Fprintf(&b, "page=%s\n", startValue(query.Get(param), 1))
+	case "offset":
+		fmt.Fprintf(&b, "offset=%s\n", startValue(query.Get(param), 0))
+		step := 0
+		for _, p := range pageSizeParams {
+			if n, err := strconv.Atoi(query.Get(p)); err == nil && n > 0 {
+				step = n
+				break
+			}
+		}
+		if step == 0 {
+			b.WriteString("# set step to the page size of the API\n")
+			step = 100
+		}
+		fmt.Fprintf(&b, "step=%d\n", step)
+	}
+
+	b.WriteString("while :; do\n")
+	fmt.Fprintf(&b, "  response=$(%s)\n", command)
+	b.WriteString("  printf '%s\\n' \"$response\"\n")
+	switch param {
+	case "cursor":
+		b.WriteString("  # adjust the path of the next cursor to the API\n")
+		b.WriteString("  cursor=$(printf '%s' \"$response\" | jq -r '.next_cursor // empty')\n")
+		b.WriteString("  [ -n \"$cursor\" ] || break\n")
+	case "page":
+		b.WriteString("  # stop on an empty page, adjust to the API\n")
+		b.WriteString("  [ \"$(printf '%s' \"$response\" | jq 'length')\" -gt 0 ] || break\n")
+		b.WriteString("  page=$((page + 1))\n")
+	case "offset":
+		b.WriteString("  # stop on an empty page, adjust to the API\n")
+		b.WriteString("  [ \"$(printf '%s' \"$response\" | jq 'length')\" -gt 0 ] || break\n")
+		b.WriteString("  offset=$((offset + step))\n")
+	}
+	b.WriteString("done\n")
+	return b.String(), nil
+}
+
+// startValue returns value when it is a number, def otherwise.
+func startValue(value string, def int) string {
+	if n, err := strconv.Atoi(value); err == nil {
+		return strconv.Itoa(n)
+	}
+	return strconv.Itoa(def)
+}
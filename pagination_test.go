@@ -0,0 +1,81 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func ExamplePaginationScript() {
+	req, _ := http.NewRequest("GET", "http://example.com/items?page=1&per_page=50", nil)
+
+	script, _ := PaginationScript(req)
+	fmt.Print(script)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// set -euo pipefail
+	//
+	// page=1
+	// while :; do
+	//   response=$(curl -X 'GET' 'http://example.com/items?page='"${page}"'&per_page=50')
+	//   printf '%s\n' "$response"
+	//   # stop on an empty page, adjust to the API
+	//   [ "$(printf '%s' "$response" | jq 'length')" -gt 0 ] || break
+	//   page=$((page + 1))
+	// done
+}
+
+func ExamplePaginationScript_offset() {
+	req, _ := http.NewRequest("GET", "http://example.com/items?limit=20&offset=40", nil)
+
+	script, _ := PaginationScript(req)
+	fmt.Print(script)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// set -euo pipefail
+	//
+	// offset=40
+	// step=20
+	// while :; do
+	//   response=$(curl -X 'GET' 'http://example.com/items?limit=20&offset='"${offset}")
+	//   printf '%s\n' "$response"
+	//   # stop on an empty page, adjust to the API
+	//   [ "$(printf '%s' "$response" | jq 'length')" -gt 0 ] || break
+	//   offset=$((offset + step))
+	// done
+}
+
+func ExamplePaginationScript_cursor() {
+	req, _ := http.NewRequest("GET", "http://example.com/events?cursor=", nil)
+	req.Header.Set("Accept", "application/json")
+
+	script, _ := PaginationScript(req)
+	fmt.Print(script)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// set -euo pipefail
+	//
+	// cursor=''
+	// while :; do
+	//   response=$(curl -X 'GET' -H 'Accept: application/json' 'http://example.com/events?cursor='"${cursor}")
+	//   printf '%s\n' "$response"
+	//   # adjust the path of the next cursor to the API
+	//   cursor=$(printf '%s' "$response" | jq -r '.next_cursor // empty')
+	//   [ -n "$cursor" ] || break
+	// done
+}
+
+func TestPaginationScriptKeepsOptions(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://example.com/items?page=1", nil)
+	opts := make([]Option, 1, 2)
+	opts[0] = WithQuoteStyle(QuoteMinimal)
+	if _, err := PaginationScript(req, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if spare := opts[:2][1]; spare != nil {
+		t.Error("PaginationScript wrote into the backing array of the options")
+	}
+}
@@ -0,0 +1,200 @@
+package http2curl
+
+import (
+	"strings"
+)
+
+// valueFlags are the curl flags followed by a value.
+var valueFlags = map[string]bool{
+	"-X": true, "--request": true,
+	"-H": true, "--header": true,
+	"-d": true, "--data": true, "--data-raw": true, "--data-binary": true, "--data-ascii": true, "--data-urlencode": true,
+	"-F": true, "--form": true,
+	"-b": true, "--cookie": true, "-c": true, "--cookie-jar": true,
+	"-u": true, "--user": true,
+	"-A": true, "--user-agent": true,
+	"-e": true, "--referer": true,
+	"-o": true, "--output": true, "-D": true, "--dump-header": true,
+	"-w": true, "--write-out": true,
+	"-x": true, "--proxy": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true,
+	"--retry": true, "--retry-delay": true, "--retry-max-time": true,
+	"--url": true, "--request-target": true, "--netrc-file": true, "--resolve": true, "--connect-to": true,
+	"--cacert": true, "--cert": true, "--key": true,
+	"--interface": true, "--doh-url": true,
+	"--limit-rate": true, "--max-redirs": true, "--proxy-user": true, "--noproxy": true,
+	"--dns-servers": true, "--local-port": true, "--unix-socket": true, "--keepalive-time": true,
+	"--expect100-timeout": true, "--tls-max": true, "--ciphers": true, "--pinnedpubkey": true,
+	"-T": true, "--upload-file": true, "--form-string": true, "--json": true, "--url-query": true,
+	"--oauth2-bearer": true, "--aws-sigv4": true, "--config": true, "--variable": true,
+	"--capath": true, "--cert-type": true, "--key-type": true, "--pass": true, "--crlfile": true,
+	"--pubkey": true, "--hostpubmd5": true, "--hostpubsha256": true, "--engine": true,
+	"--tls13-ciphers": true, "--curves": true, "--tlsuser": true, "--tlspassword": true, "--tlsauthtype": true,
+	"--proxy-header": true, "--proxy-cacert": true, "--proxy-capath": true, "--proxy-cert": true,
+	"--proxy-cert-type": true, "--proxy-key": true, "--proxy-key-type": true, "--proxy-pass": true,
+	"--proxy-ciphers": true, "--proxy-tls13-ciphers": true, "--proxy-crlfile": true,
+	"--proxy-pinnedpubkey": true, "--proxy-service-name": true, "--proxy-tlsuser": true,
+	"--proxy-tlspassword": true, "--proxy-tlsauthtype": true, "--preproxy": true,
+	"--socks4": true, "--socks4a": true, "--socks5": true, "--socks5-hostname": true,
+	"--socks5-gssapi-service": true, "--service-name": true, "--login-options": true, "--sasl-authzid": true,
+	"--delegation": true, "--krb": true, "--abstract-unix-socket": true,
+	"--dns-interface": true, "--dns-ipv4-addr": true, "--dns-ipv6-addr": true,
+	"--alt-svc": true, "--hsts": true, "--haproxy-clientip": true,
+	"--happy-eyeballs-timeout-ms": true, "--keepalive-cnt": true, "--ip-tos": true,
+	"--continue-at": true, "--range": true, "--time-cond": true,
+	"--speed-limit": true, "--speed-time": true, "--max-filesize": true, "--rate": true,
+	"--proto": true, "--proto-default": true, "--proto-redir": true,
+	"--output-dir": true, "--create-file-mode": true, "--etag-compare": true, "--etag-save": true,
+	"--trace": true, "--trace-ascii": true, "--trace-config": true, "--libcurl": true,
+	"--stderr": true, "--parallel-max": true, "--quote": true, "--telnet-option": true,
+	"--ftp-port": true, "--ftp-account": true, "--ftp-alternative-to-user": true, "--ftp-method": true,
+	"--ftp-ssl-ccc-mode": true, "--mail-auth": true, "--mail-from": true, "--mail-rcpt": true,
+	"--tftp-blksize": true, "--random-file": true, "--egd-file": true, "--ipfs-gateway": true,
+}
+
+// dataFlags are the curl flags sending a request body.
+var dataFlags = map[string]bool{
+	"-d": true, "--data": true, "--data-raw": true, "--data-binary": true, "--data-ascii": true, "--data-urlencode": true,
+}
+
+// CommandParts is a CurlCommand split into its components, for instance
+// to store them in separate fields of a log record. Command puts them back
+// together.
+type CommandParts struct {
+	// Prelude holds the leading comments and prompts, lines of their own.
+	Prelude CurlCommand
+	// Program holds the program and its -X flag.
+	Program CurlCommand
+	// Body holds the data flag and its value.
+	Body CurlCommand
+	// Headers holds the -H flags and their values.
+	Headers CurlCommand
+	// Flags holds the other flags.
+	Flags CurlCommand
+	// URL holds the URL, preceded by --url when needed.
+	URL CurlCommand
+	// Tail holds what follows the URL: pipelines and trailing comments.
+	Tail CurlCommand
+
+	// order lists the parts found by Parts, by index in fields, in the
+	// order they came in.
+	order []int
+}
+
+// fields returns the parts of p, in the order of OutputV1.
+func (p *CommandParts) fields() []*CurlCommand {
+	return []*CurlCommand{&p.Prelude, &p.Program, &p.Body, &p.Headers, &p.Flags, &p.URL, &p.Tail}
+}
+
+// add appends tokens to the part at index part of fields.
+func (p *CommandParts) add(part int, tokens ...string) {
+	*p.fields()[part] = append(*p.fields()[part], tokens...)
+	for _, i := range p.order {
+		if i == part {
+			return
+		}
+	}
+	p.order = append(p.order, part)
+}
+
+// Parts splits c into its components. It only works on commands rendered
+// without WithColor.
+func (c *CurlCommand) Parts() CommandParts {
+	const (
+		prelude = iota
+		program
+		body
+		headers
+		flags
+		target
+		tail
+	)
+	var p CommandParts
+	tokens := *c
+	inProgram := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case !inProgram && strings.HasSuffix(token, "\n"):
+			// leading comments and prompts are lines of their own
+			p.add(prelude, token)
+		case !inProgram:
+			p.add(program, token)
+			inProgram = true
+		case token == "|" || strings.HasPrefix(token, "#"):
+			p.add(tail, tokens[i:]...)
+			return p
+		case valueFlags[token] && i+1 < len(tokens):
+			pair := []string{token, tokens[i+1]}
+			i++
+			switch {
+			case token == "-X" || token == "--request":
+				p.add(program, pair...)
+			case token == "-H" || token == "--header":
+				p.add(headers, pair...)
+			case dataFlags[token]:
+				p.add(body, pair...)
+			case token == "--url":
+				p.add(target, pair...)
+			default:
+				p.add(flags, pair...)
+			}
+		case strings.HasPrefix(token, "-"):
+			p.add(flags, token)
+		default:
+			p.add(target, token)
+		}
+	}
+	return p
+}
+
+// Command reassembles the parts into a CurlCommand, in the order Parts
+// found them, so that commands of any output version come out as they
+// were. Parts it did not find, or all of them for CommandParts built by
+// hand, are put in the order of OutputV1.
+func (p CommandParts) Command() *CurlCommand {
+	order := append([]int{}, p.order...)
+	for part := range p.fields() {
+		at := -1
+		for j, i := range order {
+			if i == part {
+				at = -2
+				break
+			}
+			if i > part && at == -1 {
+				at = j
+			}
+		}
+		switch at {
+		case -2:
+		case -1:
+			order = append(order, part)
+		default:
+			order = append(order[:at], append([]int{part}, order[at:]...)...)
+		}
+	}
+	var c CurlCommand
+	fields := p.fields()
+	for _, i := range order {
+		c = append(c, *fields[i]...)
+	}
+	return &c
+}
+
+// HeadersOnly returns the -H flags of c and their values.
+func (c *CurlCommand) HeadersOnly() *CurlCommand {
+	p := c.Parts()
+	return &p.Headers
+}
+
+// BodyOnly returns the data flag of c and its value, if any.
+func (c *CurlCommand) BodyOnly() *CurlCommand {
+	p := c.Parts()
+	return &p.Body
+}
+
+// URLOnly returns the URL of c.
+func (c *CurlCommand) URLOnly() *CurlCommand {
+	p := c.Parts()
+	return &p.URL
+}
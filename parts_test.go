@@ -0,0 +1,47 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleCurlCommand_Parts() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "42")
+
+	command, _ := Command(req, nil, ForInteractive())
+	fmt.Println(command.HeadersOnly())
+	fmt.Println(command.BodyOnly())
+	fmt.Println(command.URLOnly())
+
+	parts := command.Parts()
+	fmt.Println(parts.Program, parts.Flags)
+	fmt.Println(parts.Command().String() == command.String())
+
+	// Output:
+	// -H 'Content-Type: application/json' -H 'X-Request-Id: 42'
+	// -d '{"a":1}'
+	// 'http://example.com/items'
+	// [curl -X 'POST'] [-v]
+	// true
+}
+
+func ExampleCommandParts_Command() {
+	req, _ := http.NewRequest("PUT", "http://example.com/items/1", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := Command(req, nil,
+		WithOutputVersion(OutputV2),
+		WithPrompt(regexp.MustCompile(`items/(1)`), "ITEM", "Item: "),
+	)
+	parts := command.Parts()
+	fmt.Printf("%q %q\n", parts.Prelude, parts.Program)
+	fmt.Println(parts.Command().String() == command.String())
+
+	// Output:
+	// ["read -r -p 'Item: ' ITEM\n"] ["curl" "-X" "'PUT'"]
+	// true
+}
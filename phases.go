@@ -0,0 +1,197 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// minTokenLength is the length from which a value sent by a request and
+// found in an earlier response is taken as extracted from it, shorter
+// values matching by chance.
+const minTokenLength = 12
+
+// dependency is a request needing the response of an earlier one.
+type dependency struct {
+	on     int
+	reason string
+}
+
+// PhasedScript returns a bash script sending the requests of a session,
+// reqs, grouped into numbered phases: a request whose cookies were set, or
+// whose tokens were returned, by the response of an earlier request is in
+// a later phase than it, with a comment naming the dependency, so that
+// the script is not run out of order. resps are the responses of reqs, by
+// index, nil or shorter when unknown; their bodies are read and replaced
+// by in-memory copies. Requests that fail are left out of the script and
+// reported in a *MultiError.
+func PhasedScript(reqs []*http.Request, resps []*http.Response, opts ...Option) (string, error) {
+	var errs MultiError
+	commands := make([]*CurlCommand, len(reqs))
+	sent := make([]string, len(reqs))
+	received := make([]string, len(reqs))
+	for i, req := range reqs {
+		command, err := Command(req, nil, opts...)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		body, err := readBody(req.Context(), req)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		if i < len(resps) && resps[i] != nil {
+			if received[i], err = responseText(resps[i]); err != nil {
+				errs.add(i, err)
+				continue
+			}
+		}
+		commands[i] = command
+		sent[i] = requestText(req, body)
+	}
+
+	phases := make([]int, len(reqs))
+	deps := make([][]dependency, len(reqs))
+	last := 0
+	for i, req := range reqs {
+		if commands[i] == nil {
+			continue
+		}
+		deps[i] = sessionDependencies(i, req, resps, commands, sent, received)
+		phases[i] = 1
+		for _, d := range deps[i] {
+			if phases[d.on] >= phases[i] {
+				phases[i] = phases[d.on] + 1
+			}
+		}
+		if phases[i] > last {
+			last = phases[i]
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\nset -euo pipefail\n")
+	for phase := 1; phase <= last; phase++ {
+		fmt.Fprintf(&b, "\n# phase %d\n", phase)
+		for i := range reqs {
+			if commands[i] == nil || phases[i] != phase {
+				continue
+			}
+			for _, d := range deps[i] {
+				fmt.Fprintf(&b, "# request %d needs %s of request %d\n", i+1, d.reason, d.on+1)
+			}
+			b.WriteString(commands[i].String() + "\n")
+		}
+	}
+	return b.String(), errs.errOrNil()
+}
+
+// sessionDependencies returns the earlier requests the request at index i
+// depends on, by index.
+func sessionDependencies(i int, req *http.Request, resps []*http.Response, commands []*CurlCommand, sent, received []string) []dependency {
+	var deps []dependency
+	seen := map[int]bool{}
+	depend := func(j int, reason string) {
+		if !seen[j] {
+			seen[j] = true
+			deps = append(deps, dependency{on: j, reason: reason})
+		}
+	}
+	for _, c := range req.Cookies() {
+		for j := i - 1; j >= 0; j-- {
+			if commands[j] == nil || j >= len(resps) || resps[j] == nil {
+				continue
+			}
+			if setsCookie(resps[j], c) {
+				depend(j, "the cookie "+c.Name)
+				break
+			}
+		}
+	}
+	for _, token := range valueTokens(sent[i]) {
+		for j := i - 1; j >= 0; j-- {
+			if commands[j] == nil || !strings.Contains(received[j], token) {
+				continue
+			}
+			// a value sent by j too is only echoed back by its response
+			if !strings.Contains(sent[j], token) {
+				depend(j, "a value from the response")
+			}
+			break
+		}
+	}
+	sort.Slice(deps, func(a, b int) bool { return deps[a].on < deps[b].on })
+	return deps
+}
+
+// setsCookie tells whether resp sets c.
+func setsCookie(resp *http.Response, c *http.Cookie) bool {
+	for _, set := range resp.Cookies() {
+		if set.Name == c.Name && set.Value == c.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// requestText returns what req sends besides its cookies, the text in
+// which the tokens it needs are looked for.
+func requestText(req *http.Request, body []byte) string {
+	var b strings.Builder
+	b.WriteString(req.URL.String() + "\n")
+	for name, values := range req.Header {
+		if http.CanonicalHeaderKey(name) == "Cookie" {
+			continue
+		}
+		for _, v := range values {
+			b.WriteString(v + "\n")
+		}
+	}
+	b.Write(body)
+	return b.String()
+}
+
+// responseText returns the headers and body of resp, its body being
+// replaced by an in-memory copy.
+func responseText(resp *http.Response) (string, error) {
+	var b strings.Builder
+	for name, values := range resp.Header {
+		if http.CanonicalHeaderKey(name) == "Set-Cookie" {
+			continue
+		}
+		for _, v := range values {
+			b.WriteString(v + "\n")
+		}
+	}
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return b.String(), nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	b.Write(body)
+	return b.String(), nil
+}
+
+// valueTokens returns the values of text taken as tokens, such as
+// identifiers and credentials: long enough and mixing letters and digits,
+// unlike media types and host names.
+func valueTokens(text string) []string {
+	var tokens []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.~+=", r))
+	}) {
+		if len(field) >= minTokenLength && strings.ContainsAny(field, "0123456789") &&
+			strings.IndexFunc(field, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }) >= 0 {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
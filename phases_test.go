@@ -0,0 +1,55 @@
+package http2curl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+func ExamplePhasedScript() {
+	login, _ := http.NewRequest("POST", "https://api.example.com/login", strings.NewReader(`{"user":"gopher"}`))
+	login.Header.Set("Content-Type", "application/json")
+	loggedIn := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+			"Set-Cookie":   {"session=s3cr3t; Path=/"},
+		},
+		Body: ioutil.NopCloser(strings.NewReader(`{"token":"eyJhbGciOi.J9x2"}`)),
+	}
+	health, _ := http.NewRequest("GET", "https://api.example.com/health", nil)
+	create, _ := http.NewRequest("POST", "https://api.example.com/orders", strings.NewReader(`{"item":42}`))
+	create.Header.Set("Authorization", "Bearer eyJhbGciOi.J9x2")
+	create.Header.Set("Content-Type", "application/json")
+	created := &http.Response{
+		StatusCode: 201,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"id":"ord_7f3a9c21b4"}`)),
+	}
+	order, _ := http.NewRequest("GET", "https://api.example.com/orders/ord_7f3a9c21b4", nil)
+	order.AddCookie(&http.Cookie{Name: "session", Value: "s3cr3t"})
+
+	script, _ := PhasedScript(
+		[]*http.Request{login, health, create, order},
+		[]*http.Response{loggedIn, nil, created},
+		WithRedactedHeaders(),
+	)
+	fmt.Print(script)
+
+	// Output:
+	// #!/usr/bin/env bash
+	// set -euo pipefail
+	//
+	// # phase 1
+	// curl -X 'POST' -d '{"user":"gopher"}' -H 'Content-Type: application/json' 'https://api.example.com/login'
+	// curl -X 'GET' 'https://api.example.com/health'
+	//
+	// # phase 2
+	// # request 3 needs a value from the response of request 1
+	// curl -X 'POST' -d '{"item":42}' -H 'Authorization: REDACTED' -H 'Content-Type: application/json' 'https://api.example.com/orders'
+	//
+	// # phase 3
+	// # request 4 needs the cookie session of request 1
+	// # request 4 needs a value from the response of request 3
+	// curl -X 'GET' -H 'Cookie: REDACTED' 'https://api.example.com/orders/ord_7f3a9c21b4'
+}
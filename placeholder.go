@@ -0,0 +1,99 @@
+package http2curl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// shellVarRe matches valid shell variable names.
+var shellVarRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithPlaceholder replaces the text matched by pattern in the URL, headers
+// and body with a reference to the shell variable varName, rendered as
+// "${varName}". When pattern has a capture group, only the text of the
+// first group is replaced. Used with GetCurlCommands, it turns a batch of
+// captured commands into templates, for instance to swap tenant IDs.
+func WithPlaceholder(pattern *regexp.Regexp, varName string) Option {
+	return func(o *Options) {
+		if !shellVarRe.MatchString(varName) {
+			o.err = fmt.Errorf("http2curl: invalid placeholder variable name %q", varName)
+			return
+		}
+		o.placeholders = append(o.placeholders, placeholder{re: pattern, name: varName})
+	}
+}
+
+// placeholder replaces the text matched by re with a reference to the shell
+// variable name. When re has a capture group, only the text of the first
+// group is replaced.
+type placeholder struct {
+	re   *regexp.Regexp
+	name string
+}
+
+// segment is a part of a value, either literal text or a variable name.
+type segment struct {
+	text  string
+	isVar bool
+}
+
+// segments splits str into literal text and placeholder variables.
+func (o *Options) segments(str string) []segment {
+	type span struct {
+		start, end int
+		name       string
+	}
+	var spans []span
+	for _, p := range o.placeholders {
+		for _, idx := range p.re.FindAllStringSubmatchIndex(str, -1) {
+			start, end := idx[0], idx[1]
+			// an empty group still marks where the variable goes
+			if len(idx) > 2 && idx[2] >= 0 {
+				start, end = idx[2], idx[3]
+			} else if start == end {
+				continue
+			}
+			spans = append(spans, span{start: start, end: end, name: p.name})
+		}
+	}
+	if len(spans) == 0 {
+		return []segment{{text: str}}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var segs []segment
+	pos := 0
+	for _, s := range spans {
+		if s.start < pos {
+			continue // overlaps an earlier placeholder
+		}
+		if s.start > pos {
+			segs = append(segs, segment{text: str[pos:s.start]})
+		}
+		segs = append(segs, segment{text: s.name, isVar: true})
+		pos = s.end
+	}
+	if pos < len(str) {
+		segs = append(segs, segment{text: str[pos:]})
+	}
+	return segs
+}
+
+// substitute returns str with its placeholders replaced by ${NAME}, the way
+// the shell sees the rendered value before expanding it.
+func (o *Options) substitute(str string) string {
+	if len(o.placeholders) == 0 {
+		return str
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("${" + seg.text + "}")
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleWithPlaceholder() {
+	tenant := regexp.MustCompile(`tenant-[0-9]+`)
+	token := regexp.MustCompile(`Bearer (\S+)`)
+
+	req1, _ := http.NewRequest("GET", "http://example.com/tenants/tenant-42/users", nil)
+	req1.Header.Set("Authorization", "Bearer abc")
+	req2, _ := http.NewRequest("POST", "http://example.com/tenants/tenant-42/users", strings.NewReader(`{"tenant":"tenant-42"}`))
+
+	commands, _ := GetCurlCommands([]*http.Request{req1, req2},
+		WithPlaceholder(tenant, "TENANT"),
+		WithPlaceholder(token, "TOKEN"),
+		WithSelfCheck(),
+	)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'GET' -H 'Authorization: Bearer '"${TOKEN}" 'http://example.com/tenants/'"${TENANT}"'/users'
+	// curl -X 'POST' -d '{"tenant":"'"${TENANT}"'"}' 'http://example.com/tenants/'"${TENANT}"'/users'
+}
@@ -0,0 +1,182 @@
+package http2curl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReplayPlan is a set of distinct requests weighted by how often they were
+// observed in a capture corpus, for realistic load modeling.
+type ReplayPlan struct {
+	// Total is the number of requests the plan was built from.
+	Total int `json:"total"`
+	// Entries are the distinct requests, most frequent first.
+	Entries []PlanEntry `json:"entries"`
+}
+
+// PlanEntry is a distinct request of a ReplayPlan.
+type PlanEntry struct {
+	Command string            `json:"command"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// Count is the number of times the request was observed.
+	Count int `json:"count"`
+	// Weight is Count divided by the total, the entries add up to 1.
+	Weight float64 `json:"weight"`
+}
+
+// NewReplayPlan groups reqs by their curl command, generated with opts,
+// and weights each distinct request by its frequency. The method, URL,
+// headers and body of the entries are those of the command, so options
+// such as WithRedactedHeaders apply to them alike. Requests that fail are
+// left out and reported in a *MultiError, the plan is still returned.
+func NewReplayPlan(reqs []*http.Request, opts ...Option) (*ReplayPlan, error) {
+	var errs MultiError
+	byCommand := map[string]*PlanEntry{}
+	plan := &ReplayPlan{}
+	for i, req := range reqs {
+		o := newOptions(opts)
+		args, err := buildArgs(req, o)
+		if err == nil && o.selfCheck {
+			err = o.verify(args)
+		}
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		command := o.render(args)
+		key := command.String()
+		if entry, ok := byCommand[key]; ok {
+			entry.Count++
+			plan.Total++
+			continue
+		}
+		byCommand[key] = o.planEntry(key, args)
+		plan.Total++
+	}
+
+	for _, entry := range byCommand {
+		entry.Weight = float64(entry.Count) / float64(plan.Total)
+		plan.Entries = append(plan.Entries, *entry)
+	}
+	sort.Slice(plan.Entries, func(i, j int) bool {
+		a, b := plan.Entries[i], plan.Entries[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Command < b.Command
+	})
+	return plan, errs.errOrNil()
+}
+
+// planEntry returns the entry of the command key, whose arguments are
+// args.
+func (o *Options) planEntry(key string, args argList) *PlanEntry {
+	r := o.benchRequest(args, "k6")
+	entry := &PlanEntry{Command: key, Method: r.method, URL: r.target, Count: 1}
+	header := http.Header{}
+	for _, h := range r.headers {
+		name, value, _ := strings.Cut(h, ":")
+		header.Add(name, strings.TrimLeft(value, " "))
+	}
+	if r.contentType != "" {
+		header.Set("Content-Type", r.contentType)
+	}
+	if len(r.cookies) > 0 {
+		header.Set("Cookie", strings.Join(r.cookies, "; "))
+	}
+	for name, values := range header {
+		if entry.Headers == nil {
+			entry.Headers = map[string]string{}
+		}
+		entry.Headers[name] = strings.Join(values, ", ")
+	}
+	switch {
+	case !r.hasBody:
+	case r.body.kind == argValue && strings.HasPrefix(r.body.value, "@"):
+		o.loss(LossDropped, "body", "k6 cannot refer to the file "+r.body.value[1:])
+	default:
+		entry.Body = r.body.value
+	}
+	return entry
+}
+
+// WriteJSON writes the plan as indented JSON.
+func (p *ReplayPlan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// WriteCSV writes the plan as CSV with a count, weight, command header.
+func (p *ReplayPlan) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"count", "weight", "command"}); err != nil {
+		return err
+	}
+	for _, e := range p.Entries {
+		record := []string{strconv.Itoa(e.Count), strconv.FormatFloat(e.Weight, 'f', 6, 64), e.Command}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteK6 writes a k6 script whose iterations each send one request of the
+// plan, picked at random according to the weights.
+func (p *ReplayPlan) WriteK6(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("import http from 'k6/http';\n\n")
+	b.WriteString("const requests = [\n")
+	for _, e := range p.Entries {
+		headers := e.Headers
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		var body interface{}
+		if e.Body != "" {
+			body = e.Body
+		}
+		fmt.Fprintf(&b, "  { weight: %s, method: %s, url: %s, body: %s, params: { headers: %s } },\n",
+			strconv.FormatFloat(e.Weight, 'f', 6, 64), jsString(e.Method), jsString(e.URL), jsValue(body), jsValue(headers))
+	}
+	b.WriteString("];\n\n")
+	b.WriteString(`function pick() {
+  let r = Math.random();
+  for (const req of requests) {
+    if (r < req.weight) {
+      return req;
+    }
+    r -= req.weight;
+  }
+  return requests[requests.length - 1];
+}
+
+export default function () {
+  const req = pick();
+  http.request(req.method, req.url, req.body, req.params);
+}
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// jsString returns s as a JavaScript string literal.
+func jsString(s string) string { return jsValue(s) }
+
+// jsValue returns v as a JavaScript literal. JSON is valid JavaScript once
+// U+2028 and U+2029 are escaped, which encoding/json does.
+func jsValue(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
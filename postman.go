@@ -0,0 +1,287 @@
+package http2curl
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// postmanSchema is the schema of Postman collections v2.1.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection is a Postman collection v2.1, to be encoded as JSON.
+type PostmanCollection struct {
+	Info PostmanInfo   `json:"info"`
+	Item []PostmanItem `json:"item"`
+}
+
+// PostmanInfo describes a Postman collection.
+type PostmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// PostmanItem is a request of a Postman collection.
+type PostmanItem struct {
+	Name    string         `json:"name"`
+	Request PostmanRequest `json:"request"`
+}
+
+// PostmanRequest is the request of a PostmanItem.
+type PostmanRequest struct {
+	Method string            `json:"method"`
+	Header []PostmanKeyValue `json:"header"`
+	Body   *PostmanBody      `json:"body,omitempty"`
+	URL    PostmanURL        `json:"url"`
+}
+
+// PostmanKeyValue is a header, query parameter or form field. Form fields
+// holding files have the type "file" and the name of the file as Src.
+type PostmanKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Src   string `json:"src,omitempty"`
+}
+
+// PostmanBody is the body of a PostmanRequest, in one of the modes raw,
+// urlencoded, formdata or file.
+type PostmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []PostmanKeyValue `json:"urlencoded,omitempty"`
+	FormData   []PostmanKeyValue `json:"formdata,omitempty"`
+	File       *PostmanFile      `json:"file,omitempty"`
+	Options    *PostmanOptions   `json:"options,omitempty"`
+}
+
+// PostmanFile is the file sent as the body in the file mode.
+type PostmanFile struct {
+	Src string `json:"src"`
+}
+
+// PostmanOptions tells Postman how to show a raw body.
+type PostmanOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// PostmanURL is the URL of a PostmanRequest.
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     []string          `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     []string          `json:"path,omitempty"`
+	Query    []PostmanKeyValue `json:"query,omitempty"`
+}
+
+// ToPostmanCollection returns a Postman collection holding reqs, named
+// "http2curl". Requests that fail are left out of the collection and
+// reported in a *MultiError.
+func ToPostmanCollection(reqs []*http.Request, opts ...Option) (*PostmanCollection, error) {
+	c := &PostmanCollection{
+		Info: PostmanInfo{Name: "http2curl", Schema: postmanSchema},
+		Item: []PostmanItem{},
+	}
+	var errs MultiError
+	for i, req := range reqs {
+		item, err := ToPostmanItem(req, opts...)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		c.Item = append(c.Item, *item)
+	}
+	return c, errs.errOrNil()
+}
+
+// ToPostmanItem returns the Postman collection item of req, named after
+// its method and path. Query parameters are split out of the URL, and
+// form and multipart bodies are split into fields. It is generated from
+// the same arguments as the curl command, so options apply alike;
+// placeholders become {{NAME}} Postman variables, and curl flags are
+// reported to the loss callback.
+func ToPostmanItem(req *http.Request, opts ...Option) (*PostmanItem, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		method      string
+		target      string
+		headers     []PostmanKeyValue
+		cookies     []string
+		body        *PostmanBody
+		contentType string
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by Postman")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			name, v, _ := strings.Cut(value.value, ":")
+			v = strings.TrimLeft(v, " ")
+			if strings.EqualFold(name, "Content-Type") {
+				contentType = v
+			}
+			headers = append(headers, PostmanKeyValue{Key: name, Value: o.postmanString(v)})
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			body = &PostmanBody{Mode: "file", File: &PostmanFile{Src: value.value[1:]}}
+		case dataFlags[a.value]:
+			body = &PostmanBody{Mode: "raw", Raw: value.value}
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by Postman")
+		}
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, PostmanKeyValue{Key: "Cookie", Value: o.postmanString(strings.Join(cookies, "; "))})
+	}
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+	if body != nil && body.Mode == "raw" {
+		o.postmanBody(body, contentType)
+	}
+	if body != nil && body.Mode == "formdata" {
+		// Postman sets the Content-Type with a boundary of its own
+		for i, h := range headers {
+			if strings.EqualFold(h.Key, "Content-Type") {
+				headers = append(headers[:i:i], headers[i+1:]...)
+				break
+			}
+		}
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	postmanURL := PostmanURL{
+		Raw:      o.postmanString(target),
+		Protocol: u.Scheme,
+		Host:     strings.Split(u.Hostname(), "."),
+		Port:     u.Port(),
+	}
+	if path := strings.TrimPrefix(u.EscapedPath(), "/"); path != "" {
+		for _, segment := range strings.Split(path, "/") {
+			postmanURL.Path = append(postmanURL.Path, o.postmanString(segment))
+		}
+	}
+	if u.RawQuery != "" {
+		// Postman keeps query parameters encoded, as in the raw URL
+		for _, kv := range strings.Split(u.RawQuery, "&") {
+			key, value, _ := strings.Cut(kv, "=")
+			postmanURL.Query = append(postmanURL.Query, PostmanKeyValue{Key: key, Value: o.postmanString(value)})
+		}
+	}
+	if headers == nil {
+		headers = []PostmanKeyValue{}
+	}
+
+	return &PostmanItem{
+		Name: method + " " + u.EscapedPath(),
+		Request: PostmanRequest{
+			Method: method,
+			Header: headers,
+			Body:   body,
+			URL:    postmanURL,
+		},
+	}, nil
+}
+
+// postmanBody splits the raw body into fields for form and multipart
+// content types, or sets the language of JSON bodies.
+func (o *Options) postmanBody(body *PostmanBody, contentType string) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		fields, ok := splitQuery(body.Raw)
+		if !ok || len(fields) == 0 {
+			return
+		}
+		for _, kv := range fields {
+			body.URLEncoded = append(body.URLEncoded, PostmanKeyValue{Key: kv[0], Value: o.postmanString(kv[1])})
+		}
+		body.Mode, body.Raw = "urlencoded", ""
+	case mediaType == "multipart/form-data" && params["boundary"] != "":
+		var fields []PostmanKeyValue
+		r := multipart.NewReader(strings.NewReader(body.Raw), params["boundary"])
+		for {
+			p, err := r.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return
+			}
+			if p.FileName() != "" {
+				o.loss(LossDropped, "body part "+p.FormName(), "Postman reads files from disk")
+				fields = append(fields, PostmanKeyValue{Key: p.FormName(), Type: "file", Src: p.FileName()})
+				continue
+			}
+			var value bytes.Buffer
+			if _, err := io.Copy(&value, p); err != nil {
+				return
+			}
+			fields = append(fields, PostmanKeyValue{Key: p.FormName(), Value: o.postmanString(value.String()), Type: "text"})
+		}
+		body.Mode, body.Raw, body.FormData = "formdata", "", fields
+	default:
+		body.Raw = o.postmanString(body.Raw)
+		if mediaType == "application/json" || mediaType == "" && sniffContentType([]byte(body.Raw)) == "application/json" {
+			body.Options = &PostmanOptions{}
+			body.Options.Raw.Language = "json"
+		}
+	}
+}
+
+// postmanString returns str with placeholders as {{NAME}} variables.
+func (o *Options) postmanString(str string) string {
+	if len(o.placeholders) == 0 {
+		return str
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString("{{" + seg.text + "}}")
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	return b.String()
+}
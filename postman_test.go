@@ -0,0 +1,132 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func ExampleToPostmanCollection() {
+	login, _ := http.NewRequest("POST", "https://api.example.com/login?next=%2Fhome", strings.NewReader("user=gopher&pass=secret"))
+	login.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.SetBoundary("boundary")
+	mw.WriteField("title", "Gopher")
+	fw, _ := mw.CreateFormFile("picture", "gopher.png")
+	fw.Write([]byte("\x89PNG"))
+	mw.Close()
+	upload, _ := http.NewRequest("PUT", "https://api.example.com:8443/users/42/picture", &body)
+	upload.Header.Set("Authorization", "Bearer abc123")
+	upload.Header.Set("Content-Type", mw.FormDataContentType())
+
+	c, _ := ToPostmanCollection([]*http.Request{login, upload}, WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "token"))
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(c)
+
+	item, _ := ToPostmanItem(upload)
+	fmt.Println(item.Name)
+
+	// Output:
+	// {
+	//   "info": {
+	//     "name": "http2curl",
+	//     "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	//   },
+	//   "item": [
+	//     {
+	//       "name": "POST /login",
+	//       "request": {
+	//         "method": "POST",
+	//         "header": [
+	//           {
+	//             "key": "Content-Type",
+	//             "value": "application/x-www-form-urlencoded"
+	//           }
+	//         ],
+	//         "body": {
+	//           "mode": "urlencoded",
+	//           "urlencoded": [
+	//             {
+	//               "key": "user",
+	//               "value": "gopher"
+	//             },
+	//             {
+	//               "key": "pass",
+	//               "value": "secret"
+	//             }
+	//           ]
+	//         },
+	//         "url": {
+	//           "raw": "https://api.example.com/login?next=%2Fhome",
+	//           "protocol": "https",
+	//           "host": [
+	//             "api",
+	//             "example",
+	//             "com"
+	//           ],
+	//           "path": [
+	//             "login"
+	//           ],
+	//           "query": [
+	//             {
+	//               "key": "next",
+	//               "value": "%2Fhome"
+	//             }
+	//           ]
+	//         }
+	//       }
+	//     },
+	//     {
+	//       "name": "PUT /users/42/picture",
+	//       "request": {
+	//         "method": "PUT",
+	//         "header": [
+	//           {
+	//             "key": "Authorization",
+	//             "value": "Bearer {{token}}"
+	//           }
+	//         ],
+	//         "body": {
+	//           "mode": "formdata",
+	//           "formdata": [
+	//             {
+	//               "key": "title",
+	//               "value": "Gopher",
+	//               "type": "text"
+	//             },
+	//             {
+	//               "key": "picture",
+	//               "type": "file",
+	//               "src": "gopher.png"
+	//             }
+	//           ]
+	//         },
+	//         "url": {
+	//           "raw": "https://api.example.com:8443/users/42/picture",
+	//           "protocol": "https",
+	//           "host": [
+	//             "api",
+	//             "example",
+	//             "com"
+	//           ],
+	//           "port": "8443",
+	//           "path": [
+	//             "users",
+	//             "42",
+	//             "picture"
+	//           ]
+	//         }
+	//       }
+	//     }
+	//   ]
+	// }
+	// PUT /users/42/picture
+}
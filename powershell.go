@@ -0,0 +1,185 @@
+package http2curl
+
+import (
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithPowerShell renders the command for PowerShell: the program is
+// curl.exe, since curl is an alias of Invoke-WebRequest in Windows
+// PowerShell, values are double-quoted with backtick escapes and
+// placeholders reference ${env:VAR} variables. Before PowerShell 7.3,
+// double quotes inside arguments are not passed to curl.exe as written,
+// InvokeRestMethod does not have this problem. WithWrapLongValues is
+// ignored and prompts are only supported by POSIX shells.
+func WithPowerShell() Option {
+	return func(o *Options) { o.shell = powerShell{} }
+}
+
+// powerShell is the syntax of PowerShell.
+type powerShell struct{}
+
+func (powerShell) name() string { return "powershell" }
+
+func (powerShell) program(name string) string {
+	if strings.TrimLeft(name, " ") == "curl" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// powerShellUnsafeRe matches the characters that need quoting on
+// PowerShell, where a comma builds an array and a leading @ splats.
+var powerShellUnsafeRe = regexp.MustCompile(`[^\w+=:./%-]`)
+
+func (powerShell) quote(style QuoteStyle, str string) string {
+	switch style {
+	case QuoteNever:
+		return str
+	case QuoteMinimal:
+		if str != "" && !powerShellUnsafeRe.MatchString(str) {
+			return str
+		}
+	}
+	return powerShellEscape(str)
+}
+
+func (powerShell) variable(name string) string { return "${env:" + name + "}" }
+
+func (powerShell) comment(text string, trailing bool) string {
+	return posixShell{}.comment(text, trailing)
+}
+
+func (powerShell) Quote(arg string) string { return powerShellEscape(arg) }
+
+func (powerShell) Join(args []string) string { return strings.Join(args, " ") }
+
+func (powerShell) Continuation() string { return " `" }
+
+// powerShellEscape double-quotes str, escaping with a backtick the
+// characters PowerShell interprets in double quotes, typographic quotes
+// included since PowerShell takes them for straight ones.
+func powerShellEscape(str string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range str {
+		switch r {
+		case '`', '$', '"', '“', '”', '„':
+			b.WriteByte('`')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// InvokeRestMethod returns a PowerShell Invoke-RestMethod call sending
+// req, for Windows users without curl.exe. It is generated from the same
+// arguments as the curl command, so options such as redaction or
+// placeholders apply; curl flags without an equivalent are reported to
+// the loss callback and left out. Bodies that are not valid UTF-8 are
+// decoded from base64.
+func InvokeRestMethod(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	o.shell = powerShell{}
+	o.quoteStyle = QuoteAlways
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		comments    []string
+		method      string
+		uri         string
+		names       []string
+		values      = map[string][]string{}
+		contentType string
+		body        []string
+		insecure    bool
+	)
+loop:
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			comments = append(comments, "# "+a.value)
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by Invoke-RestMethod")
+			break loop
+		case argURL:
+			uri = o.quote(a.value)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value *arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = &args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			name, v, _ := strings.Cut(value.value, ":")
+			v = strings.TrimSpace(v)
+			if strings.EqualFold(name, "Content-Type") {
+				contentType = o.quote(v)
+				continue
+			}
+			if _, ok := values[http.CanonicalHeaderKey(name)]; !ok {
+				names = append(names, name)
+			}
+			values[http.CanonicalHeaderKey(name)] = append(values[http.CanonicalHeaderKey(name)], v)
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			body = []string{"-InFile", o.quote(value.value[1:])}
+		case dataFlags[a.value]:
+			if utf8.ValidString(value.value) {
+				body = []string{"-Body", o.quote(value.value)}
+			} else {
+				body = []string{"-Body", "([Convert]::FromBase64String(" + o.quote(base64.StdEncoding.EncodeToString([]byte(value.value))) + "))"}
+			}
+		case a.value == "-k":
+			insecure = true
+		case a.value == "--compressed":
+			// Invoke-RestMethod decompresses responses
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by Invoke-RestMethod")
+		}
+	}
+
+	if method == "" && len(body) > 0 {
+		method = http.MethodPost
+	}
+	line := []string{"Invoke-RestMethod"}
+	if method != "" {
+		line = append(line, "-Method", o.quote(method))
+	}
+	line = append(line, "-Uri", uri)
+	if len(names) > 0 {
+		var headers []string
+		for _, name := range names {
+			// a hashtable holds a single value per key
+			v := strings.Join(values[http.CanonicalHeaderKey(name)], ", ")
+			headers = append(headers, o.quote(name)+" = "+o.quote(v))
+		}
+		line = append(line, "-Headers", "@{ "+strings.Join(headers, "; ")+" }")
+	}
+	if contentType != "" {
+		line = append(line, "-ContentType", contentType)
+	}
+	line = append(line, body...)
+	if insecure {
+		line = append(line, "-SkipCertificateCheck")
+	}
+	return strings.Join(append(comments, strings.Join(line, " ")), "\n"), nil
+}
@@ -0,0 +1,45 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleWithPowerShell() {
+	req, _ := http.NewRequest("POST", "http://example.com/items?a=1&b=2", strings.NewReader(`{"price":"$5"}`))
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := GetCurlCommandWithOptions(req,
+		WithPowerShell(),
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"),
+	)
+	fmt.Println(command)
+
+	// Output:
+	// curl.exe -X "POST" -d "{`"price`":`"`$5`"}" -H "Authorization: Bearer "${env:TOKEN} -H "Content-Type: application/json" "http://example.com/items?a=1&b=2"
+}
+
+func ExampleInvokeRestMethod() {
+	req, _ := http.NewRequest("PUT", "http://example.com/items/1", strings.NewReader(`{"name":"it's $5"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Accept", "text/plain")
+
+	command, _ := InvokeRestMethod(req, ForInteractive(), WithLossCallback(func(e LossEvent) { fmt.Println(e) }))
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("POST", "http://example.com/upload", strings.NewReader("\xff\xfe"))
+	command, _ = InvokeRestMethod(req, WithAnalysis())
+	fmt.Println(command)
+
+	// Output:
+	// header Accept rewritten: 2 values joined with spaces
+	// flag -v dropped: not supported by Invoke-RestMethod
+	// Invoke-RestMethod -Method "PUT" -Uri "http://example.com/items/1" -Headers @{ "Accept" = "application/json text/plain" } -ContentType "application/json" -Body "{`"name`":`"it's `$5`"}"
+	// # request size: 2 bytes (headers 0 bytes, body 2 bytes)
+	// # cookies: 0
+	// Invoke-RestMethod -Method "POST" -Uri "http://example.com/upload" -Body ([Convert]::FromBase64String("//4="))
+}
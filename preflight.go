@@ -0,0 +1,93 @@
+package http2curl
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// corsSafelisted are the request headers a browser sends without asking
+// for permission in a preflight, given a simple value.
+var corsSafelisted = map[string]bool{
+	"Accept":           true,
+	"Accept-Language":  true,
+	"Content-Language": true,
+	"Content-Type":     true,
+}
+
+// corsSimpleContentTypes are the Content-Type values not needing a
+// preflight.
+var corsSimpleContentTypes = map[string]bool{
+	"application/x-www-form-urlencoded": true,
+	"multipart/form-data":               true,
+	"text/plain":                        true,
+}
+
+// browserControlled are the headers set by the browser itself, which never
+// appear in Access-Control-Request-Headers.
+var browserControlled = map[string]bool{
+	"Accept-Encoding": true,
+	"Connection":      true,
+	"Content-Length":  true,
+	"Cookie":          true,
+	"Host":            true,
+	"Origin":          true,
+	"Referer":         true,
+	"User-Agent":      true,
+}
+
+// PreflightCommand returns the curl command for the CORS preflight a
+// browser would send before req: an OPTIONS request to the same URL with
+// Origin, Access-Control-Request-Method and Access-Control-Request-Headers
+// derived from req. The origin is taken from the Origin header of req, or
+// from its Referer. The command includes -i to show the response headers.
+func PreflightCommand(req *http.Request, opts ...Option) (*CurlCommand, error) {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		if ref, err := url.Parse(req.Referer()); err == nil && ref.Scheme != "" && ref.Host != "" {
+			origin = ref.Scheme + "://" + ref.Host
+		}
+	}
+	if origin == "" {
+		return nil, errors.New("http2curl: request has no Origin or Referer header to derive a preflight from")
+	}
+
+	preflight, err := http.NewRequest(http.MethodOptions, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	preflight.Header.Set("Origin", origin)
+	preflight.Header.Set("Access-Control-Request-Method", req.Method)
+	if headers := corsRequestHeaders(req.Header); len(headers) > 0 {
+		preflight.Header.Set("Access-Control-Request-Headers", strings.Join(headers, ","))
+	}
+
+	opts = append([]Option{func(o *Options) { o.flags.addFlag("-i") }}, opts...)
+	return Command(preflight, nil, opts...)
+}
+
+// corsRequestHeaders returns the lowercased, sorted names of the headers
+// of h that need the permission of the server.
+func corsRequestHeaders(h http.Header) []string {
+	var names []string
+	for name := range h {
+		canonical := http.CanonicalHeaderKey(name)
+		if browserControlled[canonical] || strings.HasPrefix(canonical, "Access-Control-") {
+			continue
+		}
+		if corsSafelisted[canonical] {
+			if canonical != "Content-Type" {
+				continue
+			}
+			if mediaType, _, err := mime.ParseMediaType(h.Get(name)); err == nil && corsSimpleContentTypes[mediaType] {
+				continue
+			}
+		}
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,21 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExamplePreflightCommand() {
+	req, _ := http.NewRequest("PUT", "https://api.example.com/items/1", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Accept", "application/json")
+
+	command, _ := PreflightCommand(req)
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'OPTIONS' -H 'Access-Control-Request-Headers: authorization,content-type' -H 'Access-Control-Request-Method: PUT' -H 'Origin: https://app.example.com' -i 'https://api.example.com/items/1'
+}
@@ -0,0 +1,77 @@
+package http2curl
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Pending is a request whose capture is generated in two phases: Prepare
+// keeps what is cheap to get, Finalize reads the body and generates the
+// command only when it is asked for, for instance once the response shows
+// the request is worth keeping.
+type Pending struct {
+	// Method, URL and Header are those of the request when prepared.
+	Method string
+	URL    string
+	Header http.Header
+
+	req  *http.Request
+	opts []Option
+	sent *recordingBody // nil when the body is read with GetBody
+}
+
+// Prepare returns the Pending capture of req, generated with opts, without
+// reading its body. When req.GetBody is set, Finalize reads the body from
+// a copy it returns. Otherwise req.Body is replaced by a reader recording
+// what is read from it, so that the body can be sent before Finalize is
+// called; Finalize then uses what was sent.
+func Prepare(req *http.Request, opts ...Option) *Pending {
+	p := &Pending{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		req:    req.Clone(req.Context()),
+		opts:   opts,
+	}
+	if req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+		p.sent = &recordingBody{ReadCloser: req.Body}
+		req.Body = p.sent
+		p.req.Body = nil
+	}
+	return p
+}
+
+// Finalize reads the body of the request and returns its Capture.
+func (p *Pending) Finalize() (*Capture, error) {
+	req := p.req
+	if p.sent != nil {
+		req = req.Clone(req.Context())
+		body := p.sent.bytes()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	return NewCapture(req, p.opts...)
+}
+
+// recordingBody keeps a copy of what is read from a request body.
+type recordingBody struct {
+	io.ReadCloser
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *recordingBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.mu.Lock()
+	r.buf.Write(p[:n])
+	r.mu.Unlock()
+	return n, err
+}
+
+func (r *recordingBody) bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]byte(nil), r.buf.Bytes()...)
+}
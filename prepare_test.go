@@ -0,0 +1,52 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func ExamplePrepare() {
+	// a body without GetBody, only readable once
+	req, _ := http.NewRequest("PUT", "http://example.com/files/1", io.NopCloser(strings.NewReader("content")))
+
+	pending := Prepare(req)
+	fmt.Println(pending.Method, pending.URL)
+
+	io.ReadAll(req.Body) // sending the request reads the body
+	capture, _ := pending.Finalize()
+	fmt.Println(capture.Command, capture.BodySize)
+
+	// Output:
+	// PUT http://example.com/files/1
+	// curl -X 'PUT' -d 'content' 'http://example.com/files/1' 7
+}
+
+func ExampleTransport_keep() {
+	server := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		status := http.StatusOK
+		if bytes.Contains(body, []byte("bad")) {
+			status = http.StatusUnprocessableEntity
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody, Request: req}, nil
+	})
+	client := &http.Client{Transport: &Transport{
+		Base:   server,
+		Writer: os.Stdout,
+		Keep:   KeepErrors,
+	}}
+
+	for _, body := range []string{"good", "bad"} {
+		resp, _ := client.Post("http://example.com/items", "text/plain", io.NopCloser(strings.NewReader(body)))
+		fmt.Println(resp.StatusCode)
+	}
+
+	// Output:
+	// 200
+	// curl -X 'POST' -d 'bad' -H 'Content-Type: text/plain' 'http://example.com/items'
+	// 422
+}
@@ -0,0 +1,104 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Exact makes the command send the same bytes as the request, at the
+// expense of readability: the body is sent with --data-raw, which sends
+// it as is even when it starts with '@', each value of a header gets its
+// own -H flag rather than being joined, and the Content-Length header is
+// set explicitly. The command is also checked as with WithSelfCheck.
+// Header casing is kept as set in the http.Header map, see
+// WithHeaderCasing; their order cannot be known from it and is
+// alphabetical.
+func Exact() Option {
+	return func(o *Options) {
+		o.dataFlag = DataRaw
+		o.headerPerValue = true
+		o.contentLength = true
+		o.selfCheck = true
+	}
+}
+
+// Readable makes the command easy to read and edit, at the expense of
+// sending the exact bytes of the request: JSON bodies are indented, -X is
+// left out when curl implies the method, as with OutputV2, and values are
+// only quoted when needed, as with QuoteMinimal. Headers are sorted, as
+// always.
+func Readable() Option {
+	return func(o *Options) {
+		o.outputVersion = OutputV2
+		o.quoteStyle = QuoteMinimal
+		o.prettyJSON = true
+	}
+}
+
+// hardenedBodyLimit is the body limit set by Hardened.
+const hardenedBodyLimit = 64 << 10
+
+// Hardened makes generating commands from untrusted requests, such as
+// those received by an internet-facing server, safe. Given last, it
+// overrides the other options where needed: the sensitive headers and the
+// URL password are redacted, bodies are truncated to 64 KiB, headers are
+// never spilled to files nor bodies read from them, prompts are dropped,
+// values are always quoted for POSIX shells and the command is checked as
+// with WithSelfCheck. Requests with control characters in a header or in
+// the URL, which could fool an operator reading the command, are rejected.
+func Hardened() Option {
+	return func(o *Options) {
+		WithRedactedHeaders()(o)
+		if o.bodyLimit == 0 || o.bodyLimit > hardenedBodyLimit {
+			o.bodyLimit = hardenedBodyLimit
+		}
+		o.spill = nil
+		o.spillBytes = 0
+		o.bodyFile = ""
+		o.prompts = nil
+		o.shell = posixShell{}
+		o.quoteStyle = QuoteAlways
+		o.selfCheck = true
+		o.rejectControl = true
+	}
+}
+
+// checkControl returns an error when a header or the URL in args holds a
+// control character, tabs aside, if requested.
+func (o *Options) checkControl(args argList) error {
+	if !o.rejectControl {
+		return nil
+	}
+	for _, a := range args {
+		if a.kind != argHeader && a.kind != argURL {
+			continue
+		}
+		if i := strings.IndexFunc(a.value, isControl); i >= 0 {
+			return fmt.Errorf("http2curl: control character %q in %q", a.value[i], a.value)
+		}
+	}
+	return nil
+}
+
+// isControl reports whether r is an ASCII control character other than tab.
+func isControl(r rune) bool {
+	return r < ' ' && r != '\t' || r == 0x7f
+}
+
+// indentJSON returns body indented when it is a JSON object or array and
+// indenting is enabled.
+func (o *Options) indentJSON(body []byte) []byte {
+	if !o.prettyJSON || sniffContentType(body) != "application/json" {
+		return body
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return body
+	}
+	if !bytes.Equal(indented.Bytes(), body) {
+		o.loss(LossRewritten, "body", "JSON indented")
+	}
+	return indented.Bytes()
+}
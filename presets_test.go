@@ -0,0 +1,41 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleExact() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader("line 1\nline 2\n"))
+	req.Header["X-Trace"] = []string{"a", "b"}
+
+	command, _ := Command(req, nil, Exact())
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("POST", "http://example.com/mentions", strings.NewReader("@gopher"))
+	command, err := Command(req, nil, Exact())
+	fmt.Println(command, err)
+
+	// Output:
+	// curl -X 'POST' --data-raw 'line 1
+	// line 2
+	// ' -H 'Content-Length: 14' -H 'X-Trace: a' -H 'X-Trace: b' 'http://example.com/items'
+	// curl -X 'POST' --data-raw '@gopher' -H 'Content-Length: 7' 'http://example.com/mentions' <nil>
+}
+
+func ExampleReadable() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(`{"name":"gopher","tags":["go"]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := Command(req, nil, Readable())
+	fmt.Println(command)
+
+	// Output:
+	// curl -H 'Content-Type: application/json' -d '{
+	//   "name": "gopher",
+	//   "tags": [
+	//     "go"
+	//   ]
+	// }' http://example.com/items
+}
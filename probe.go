@@ -0,0 +1,47 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProbeCommand returns the curl command for a lightweight variant of req,
+// to check that its URL is reachable and its credentials accepted apart
+// from any issue with its payload: the same URL and headers, without the
+// body and the headers describing it, sent with method, either HEAD or
+// OPTIONS, HEAD by default. curl only prints the status code of the
+// response.
+func ProbeCommand(req *http.Request, method string, opts ...Option) (*CurlCommand, error) {
+	if method == "" {
+		method = http.MethodHead
+	}
+	method = strings.ToUpper(method)
+	if method != http.MethodHead && method != http.MethodOptions {
+		return nil, fmt.Errorf("http2curl: invalid probe method %q, want HEAD or OPTIONS", method)
+	}
+	if req.URL == nil {
+		return nil, fmt.Errorf("http2curl: invalid request, req.URL is nil")
+	}
+
+	probe := req.Clone(req.Context())
+	probe.Method = method
+	probe.Body = nil
+	probe.GetBody = nil
+	probe.ContentLength = 0
+	probe.Trailer = nil
+	for name := range probe.Header {
+		canonical := http.CanonicalHeaderKey(name)
+		if strings.HasPrefix(canonical, "Content-") || canonical == "Expect" || canonical == "Transfer-Encoding" {
+			probe.Header.Del(name)
+		}
+	}
+
+	opts = append([]Option{func(o *Options) {
+		o.headFlag = true
+		o.flags.addFlag("-sS")
+		o.flags.flag("-o", argValue, "/dev/null")
+		o.flags.flag("-w", argValue, `%{http_code}\n`)
+	}}, opts...)
+	return GetCurlCommandWithOptions(probe, opts...)
+}
@@ -0,0 +1,23 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleProbeCommand() {
+	req, _ := http.NewRequest("POST", "https://api.example.com/orders", strings.NewReader(`{"item":42}`))
+	req.Header.Set("Authorization", "Bearer 0123456789")
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := ProbeCommand(req, "")
+	fmt.Println(command)
+
+	command, _ = ProbeCommand(req, "options")
+	fmt.Println(command)
+
+	// Output:
+	// curl --head -H 'Authorization: Bearer 0123456789' -sS -o '/dev/null' -w '%{http_code}\n' 'https://api.example.com/orders'
+	// curl -X 'OPTIONS' -H 'Authorization: Bearer 0123456789' -sS -o '/dev/null' -w '%{http_code}\n' 'https://api.example.com/orders'
+}
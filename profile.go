@@ -0,0 +1,90 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ClientProfile holds the content negotiation headers sent by a client.
+// Empty fields leave the request unchanged.
+type ClientProfile struct {
+	UserAgent      string
+	Accept         string
+	AcceptLanguage string
+}
+
+// ClientProfiles are the profiles available to WithClientProfile, by name.
+// Entries may be added before generating commands.
+var ClientProfiles = map[string]ClientProfile{
+	"chrome-windows": {
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		Accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
+		AcceptLanguage: "en-US,en;q=0.9",
+	},
+	"ios-safari": {
+		UserAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+		Accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		AcceptLanguage: "en-US,en;q=0.9",
+	},
+	"googlebot": {
+		UserAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		Accept:    "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+	},
+}
+
+// WithClientProfile overrides the User-Agent, Accept and Accept-Language
+// headers with those of the named profile of ClientProfiles, to debug
+// content negotiation differences between clients.
+func WithClientProfile(name string) Option {
+	return func(o *Options) {
+		p, ok := ClientProfiles[name]
+		if !ok {
+			o.err = fmt.Errorf("http2curl: unknown client profile %q", name)
+			return
+		}
+		o.overrideHeader("User-Agent", p.UserAgent)
+		o.overrideHeader("Accept", p.Accept)
+		o.overrideHeader("Accept-Language", p.AcceptLanguage)
+	}
+}
+
+// WithUserAgent overrides the User-Agent header.
+func WithUserAgent(value string) Option {
+	return func(o *Options) { o.overrideHeader("User-Agent", value) }
+}
+
+// WithAccept overrides the Accept header.
+func WithAccept(value string) Option {
+	return func(o *Options) { o.overrideHeader("Accept", value) }
+}
+
+// WithAcceptLanguage overrides the Accept-Language header.
+func WithAcceptLanguage(value string) Option {
+	return func(o *Options) { o.overrideHeader("Accept-Language", value) }
+}
+
+func (o *Options) overrideHeader(name, value string) {
+	if value == "" {
+		return
+	}
+	if o.headerOverrides == nil {
+		o.headerOverrides = map[string]string{}
+	}
+	o.headerOverrides[name] = value
+}
+
+// applyHeaderOverrides sets the overridden headers on req.
+func (o *Options) applyHeaderOverrides(req *http.Request) {
+	names := make([]string, 0, len(o.headerOverrides))
+	for name := range o.headerOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if old := req.Header.Get(name); old != "" && old != o.headerOverrides[name] {
+			o.loss(LossRewritten, "header "+name, "overridden")
+		}
+		req.Header.Set(name, o.headerOverrides[name])
+	}
+}
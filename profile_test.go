@@ -0,0 +1,21 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithClientProfile() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("User-Agent", "Go-http-client/1.1")
+
+	command, _ := Command(req, nil, WithClientProfile("googlebot"), WithAcceptLanguage("fr-FR"))
+	fmt.Println(command)
+
+	_, err := Command(req, nil, WithClientProfile("netscape"))
+	fmt.Println(err)
+
+	// Output:
+	// curl -X 'GET' -H 'Accept: text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8' -H 'Accept-Language: fr-FR' -H 'User-Agent: Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)' 'http://example.com/'
+	// http2curl: unknown client profile "netscape"
+}
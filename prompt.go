@@ -0,0 +1,26 @@
+package http2curl
+
+import "regexp"
+
+// WithPrompt works like WithPlaceholder, and also makes the command start
+// with a bash read -p line asking for the value of varName, so that
+// exported commands and scripts can be run by different people without
+// hardcoding their values.
+func WithPrompt(pattern *regexp.Regexp, varName, prompt string) Option {
+	return withPrompt(pattern, varName, "read -r -p "+bashEscape(prompt)+" "+varName)
+}
+
+// WithSecretPrompt works like WithPrompt but reads the value with read -s,
+// without echoing it, for passwords, tokens or one-time codes.
+func WithSecretPrompt(pattern *regexp.Regexp, varName, prompt string) Option {
+	return withPrompt(pattern, varName, "read -r -s -p "+bashEscape(prompt)+" "+varName+" && echo")
+}
+
+func withPrompt(pattern *regexp.Regexp, varName, line string) Option {
+	return func(o *Options) {
+		WithPlaceholder(pattern, varName)(o)
+		if o.err == nil {
+			o.prompts.add(argPrompt, line)
+		}
+	}
+}
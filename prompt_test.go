@@ -0,0 +1,27 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleWithPrompt() {
+	req, _ := http.NewRequest("POST", "https://example.com/login", strings.NewReader(`{"user":"gopher","otp":"123456"}`))
+	req.Header.Set("Authorization", "Bearer 0123456789")
+
+	command, _ := Command(req, nil,
+		WithPrompt(regexp.MustCompile(`"user":"([^"]*)"`), "USER_NAME", "User: "),
+		WithSecretPrompt(regexp.MustCompile(`"otp":"([^"]*)"`), "OTP", "One-time code: "),
+		WithSecretPrompt(regexp.MustCompile(`^Authorization: Bearer (.*)$`), "TOKEN", "Token: "),
+		WithSelfCheck(),
+	)
+	fmt.Println(command)
+
+	// Output:
+	// read -r -p 'User: ' USER_NAME
+	// read -r -s -p 'One-time code: ' OTP && echo
+	// read -r -s -p 'Token: ' TOKEN && echo
+	// curl -X 'POST' -d '{"user":"'"${USER_NAME}"'","otp":"'"${OTP}"'"}' -H 'Authorization: Bearer '"${TOKEN}" 'https://example.com/login'
+}
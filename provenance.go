@@ -0,0 +1,62 @@
+package http2curl
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CallerFrame is a function call in the code that issued a request.
+type CallerFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (f CallerFrame) String() string {
+	return filepath.Base(f.File) + ":" + strconv.Itoa(f.Line) + " (" + f.Function + ")"
+}
+
+// WithComment adds a comment line above the command.
+func WithComment(text string) Option {
+	return func(o *Options) { o.comments = append(o.comments, text) }
+}
+
+// callers returns depth frames of the current goroutine's stack, leaving
+// out the Transport, net/http and runtime frames, then skip more frames.
+func callers(skip, depth int) []CallerFrame {
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	var out []CallerFrame
+	for {
+		f, more := frames.Next()
+		if !internalFrame(f.Function) {
+			if skip > 0 {
+				skip--
+			} else {
+				out = append(out, CallerFrame{Function: f.Function, File: f.File, Line: f.Line})
+				if len(out) == depth {
+					return out
+				}
+			}
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// internalFrame reports whether function belongs to the machinery
+// between the caller and the Transport.
+func internalFrame(function string) bool {
+	for _, prefix := range []string{"net/http.", "runtime.", "github.com/gdey/http2curl/v2.(*Transport)", "github.com/gdey/http2curl/v2.callers"} {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// GetPythonSnippet returns a Python snippet sending req with the requests
+// library. JSON bodies are passed with json= as Python literals, in which
+// case requests sets the Content-Type header itself; other bodies are
+// passed with data=, as bytes when they are not valid UTF-8. It is
+// generated from the same arguments as the curl command, so options apply
+// alike; placeholders become environment variables read with os.environ,
+// and curl flags without an equivalent are reported to the loss callback.
+func GetPythonSnippet(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		comments []string
+		method   string
+		target   string
+		headers  []string
+		cookies  []string
+		body     *arg
+		file     string
+		verify   = true
+		check    bool
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argTrailingComment:
+			comments = append(comments, "# "+a.value)
+			continue
+		case argURL:
+			target = a.value
+			continue
+		case argPipe:
+			o.loss(LossDropped, "pipeline", "not supported by requests")
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			headers = append(headers, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			file = value.value[1:]
+		case dataFlags[a.value]:
+			body = &args[i]
+		case a.value == "-k" || a.value == "--insecure":
+			verify = false
+		case a.value == "-f" || a.value == "--fail" || a.value == "--fail-with-body":
+			check = true
+		case a.value == "--compressed":
+			// requests decompresses responses
+		default:
+			o.loss(LossDropped, "flag "+a.value, "not supported by requests")
+		}
+	}
+	if method == "" {
+		method = http.MethodGet
+		if body != nil || file != "" {
+			method = http.MethodPost
+		}
+	}
+
+	var jsonBody string
+	if body != nil && len(o.segments(body.value)) <= 1 {
+		for _, h := range headers {
+			name, v, _ := strings.Cut(h, ":")
+			if mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(v)); strings.EqualFold(name, "Content-Type") && mediaType == "application/json" {
+				jsonBody, _ = pythonJSON(body.value, "    ")
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range comments {
+		b.WriteString(c + "\n")
+	}
+	b.WriteString("response = requests.request(\n")
+	b.WriteString("    " + o.pyString(method) + ",\n")
+	b.WriteString("    " + o.pyString(target) + ",\n")
+	if len(headers) > 0 {
+		var lines []string
+		for _, h := range headers {
+			name, v, _ := strings.Cut(h, ":")
+			v = strings.TrimLeft(v, " ")
+			if jsonBody != "" && strings.EqualFold(name, "Content-Type") && v == "application/json" {
+				// set by requests for json=
+				continue
+			}
+			lines = append(lines, "        "+pyString(name)+": "+o.pyString(v)+",\n")
+		}
+		if len(lines) > 0 {
+			b.WriteString("    headers={\n" + strings.Join(lines, "") + "    },\n")
+		}
+	}
+	if len(cookies) > 0 {
+		b.WriteString("    cookies={\n")
+		for _, c := range cookies {
+			name, v, _ := strings.Cut(c, "=")
+			b.WriteString("        " + pyString(name) + ": " + o.pyString(v) + ",\n")
+		}
+		b.WriteString("    },\n")
+	}
+	switch {
+	case jsonBody != "":
+		b.WriteString("    json=" + jsonBody + ",\n")
+	case body != nil && !utf8.ValidString(body.value):
+		b.WriteString("    data=" + pyBytes(body.value) + ",\n")
+	case body != nil:
+		b.WriteString("    data=" + o.pyString(body.value) + ",\n")
+	case file != "":
+		b.WriteString("    data=open(" + pyString(file) + ", \"rb\"),\n")
+	}
+	if !verify {
+		b.WriteString("    verify=False,\n")
+	}
+	b.WriteString(")\n")
+	if check {
+		b.WriteString("response.raise_for_status()\n")
+	}
+
+	imports := "import requests\n\n"
+	if len(o.placeholders) > 0 && strings.Contains(b.String(), "os.environ[") {
+		imports = "import os\n\n" + imports
+	}
+	return imports + b.String(), nil
+}
+
+// pyString returns a Python expression for str, placeholders being
+// concatenated as environment variables.
+func (o *Options) pyString(str string) string {
+	if len(o.placeholders) == 0 {
+		return pyString(str)
+	}
+	var parts []string
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			parts = append(parts, `os.environ[`+pyString(seg.text)+`]`)
+		} else {
+			parts = append(parts, pyString(seg.text))
+		}
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+// pyString returns str as a Python string literal.
+func pyString(str string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range str {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < ' ' || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// pyBytes returns str as a Python bytes literal.
+func pyBytes(str string) string {
+	var b strings.Builder
+	b.WriteString(`b"`)
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '\\' || c == '"':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c >= 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// pythonJSON returns the JSON document doc as a Python literal, keeping
+// the order of object keys, indented with indent past the first line.
+func pythonJSON(doc, indent string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var b bytes.Buffer
+	if err := writePythonValue(&b, dec, indent, "    "); err != nil {
+		return "", err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return "", fmt.Errorf("http2curl: invalid JSON body")
+	}
+	return b.String(), nil
+}
+
+// writePythonValue writes the next JSON value of dec as a Python literal.
+func writePythonValue(b *bytes.Buffer, dec *json.Decoder, indent, step string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		closing := "]"
+		if t == '{' {
+			closing = "}"
+		}
+		b.WriteString(string(t))
+		empty := true
+		for dec.More() {
+			empty = false
+			b.WriteString("\n" + indent + step)
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				b.WriteString(pyString(key.(string)) + ": ")
+			}
+			if err := writePythonValue(b, dec, indent+step, step); err != nil {
+				return err
+			}
+			b.WriteByte(',')
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		if !empty {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteString(closing)
+	case string:
+		b.WriteString(pyString(t))
+	case json.Number:
+		b.WriteString(t.String())
+	case bool:
+		if t {
+			b.WriteString("True")
+		} else {
+			b.WriteString("False")
+		}
+	case nil:
+		b.WriteString("None")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleGetPythonSnippet() {
+	req, _ := http.NewRequest("PUT", "https://example.com/items/1", strings.NewReader(`{"name":"go\"pher","tags":["go",null],"age":1.5e1,"admin":false,"extra":{}}`))
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session=abc")
+
+	snippet, _ := GetPythonSnippet(req,
+		WithCookieFlags(),
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"),
+		ForScripting(),
+		WithLossCallback(func(e LossEvent) { fmt.Println(e) }),
+	)
+	fmt.Println(snippet)
+
+	req, _ = http.NewRequest("POST", "https://example.com/upload", strings.NewReader("\xff\x00a\n"))
+	snippet, _ = GetPythonSnippet(req)
+	fmt.Println(snippet)
+
+	// Output:
+	// flag -sS dropped: not supported by requests
+	// flag --retry dropped: not supported by requests
+	// flag --retry-connrefused dropped: not supported by requests
+	// import os
+	//
+	// import requests
+	//
+	// response = requests.request(
+	//     "PUT",
+	//     "https://example.com/items/1",
+	//     headers={
+	//         "Authorization": "Bearer " + os.environ["TOKEN"],
+	//     },
+	//     cookies={
+	//         "session": "abc",
+	//     },
+	//     json={
+	//         "name": "go\"pher",
+	//         "tags": [
+	//             "go",
+	//             None,
+	//         ],
+	//         "age": 1.5e1,
+	//         "admin": False,
+	//         "extra": {},
+	//     },
+	// )
+	// response.raise_for_status()
+	//
+	// import requests
+	//
+	// response = requests.request(
+	//     "POST",
+	//     "https://example.com/upload",
+	//     data=b"\xff\x00a\x0a",
+	// )
+}
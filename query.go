@@ -0,0 +1,72 @@
+package http2curl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// WithSortedQuery sorts the query parameters of the URL by name, then by
+// value, so that the same request gives the same command whatever the
+// order its parameters were added in, for fingerprinting, deduplication
+// and golden tests. Parameters are compared decoded, and keep their
+// encoding unless WithNormalizedQueryEncoding is used too.
+func WithSortedQuery() Option {
+	return func(o *Options) { o.sortedQuery = true }
+}
+
+// WithNormalizedQueryEncoding percent-encodes the query parameters of the
+// URL the way url.QueryEscape does, so that a=%7e, a=~ and a=%7E are all
+// written a=~. Parameters that cannot be decoded are kept as they are.
+func WithNormalizedQueryEncoding() Option {
+	return func(o *Options) { o.normalizedQuery = true }
+}
+
+// queryParam is a query parameter, as written and decoded.
+type queryParam struct {
+	raw        string
+	key, value string
+}
+
+// normalizeQuery sorts and re-encodes the query of u as requested.
+func (o *Options) normalizeQuery(u *url.URL) {
+	if !o.sortedQuery && !o.normalizedQuery || u.RawQuery == "" {
+		return
+	}
+	var params []queryParam
+	for _, raw := range strings.Split(u.RawQuery, "&") {
+		if raw == "" {
+			continue
+		}
+		p := queryParam{raw: raw}
+		key, value, hasValue := strings.Cut(raw, "=")
+		var errKey, errValue error
+		p.key, errKey = url.QueryUnescape(key)
+		p.value, errValue = url.QueryUnescape(value)
+		if errKey != nil || errValue != nil {
+			p.key, p.value = key, value
+		} else if o.normalizedQuery {
+			p.raw = url.QueryEscape(p.key)
+			if hasValue {
+				p.raw += "=" + url.QueryEscape(p.value)
+			}
+		}
+		params = append(params, p)
+	}
+	if o.sortedQuery {
+		sort.SliceStable(params, func(i, j int) bool {
+			if params[i].key != params[j].key {
+				return params[i].key < params[j].key
+			}
+			return params[i].value < params[j].value
+		})
+	}
+	raws := make([]string, len(params))
+	for i, p := range params {
+		raws[i] = p.raw
+	}
+	if query := strings.Join(raws, "&"); query != u.RawQuery {
+		u.RawQuery = query
+		o.loss(LossRewritten, "url", "query normalized")
+	}
+}
@@ -0,0 +1,24 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithSortedQuery() {
+	req, _ := http.NewRequest("GET", "http://example.com/search?q=go+lang&page=2&tag=b&tag=a&sort=%7enew", nil)
+
+	for _, opts := range [][]Option{
+		{WithSortedQuery()},
+		{WithNormalizedQueryEncoding()},
+		{WithSortedQuery(), WithNormalizedQueryEncoding()},
+	} {
+		command, _ := GetCurlCommandWithOptions(req, opts...)
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'GET' 'http://example.com/search?page=2&q=go+lang&sort=%7enew&tag=a&tag=b'
+	// curl -X 'GET' 'http://example.com/search?q=go+lang&page=2&tag=b&tag=a&sort=~new'
+	// curl -X 'GET' 'http://example.com/search?page=2&q=go+lang&sort=~new&tag=a&tag=b'
+}
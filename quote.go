@@ -0,0 +1,53 @@
+package http2curl
+
+import (
+	"regexp"
+)
+
+// QuoteStyle controls when values are quoted for the shell.
+type QuoteStyle int
+
+const (
+	// QuoteAlways quotes every value, even simple ones. It is the default.
+	QuoteAlways QuoteStyle = iota
+	// QuoteMinimal only quotes values containing characters the shell
+	// would interpret, leaving tokens such as POST or bare URLs as is.
+	QuoteMinimal
+	// QuoteNever never quotes values. The output is only safe to paste in
+	// a shell when the values are known to be free of special characters.
+	QuoteNever
+)
+
+func (s QuoteStyle) String() string {
+	switch s {
+	case QuoteAlways:
+		return "always"
+	case QuoteMinimal:
+		return "minimal"
+	case QuoteNever:
+		return "never"
+	}
+	return "unknown"
+}
+
+// WithQuoteStyle sets when values are quoted.
+func WithQuoteStyle(style QuoteStyle) Option {
+	return func(o *Options) { o.quoteStyle = style }
+}
+
+// shellUnsafeRe matches the characters that need quoting.
+var shellUnsafeRe = regexp.MustCompile(`[^\w@%+=:,./-]`)
+
+// quoteWith escapes str for the shell according to style.
+func quoteWith(style QuoteStyle, str string) string {
+	switch style {
+	case QuoteNever:
+		return str
+	case QuoteMinimal:
+		// zsh expands a leading '=' as a command path
+		if str != "" && str[0] != '=' && !shellUnsafeRe.MatchString(str) {
+			return str
+		}
+	}
+	return bashEscape(str)
+}
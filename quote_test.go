@@ -0,0 +1,42 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithQuoteStyle() {
+	req, _ := http.NewRequest("POST", "http://example.com/items?page=2&sort=asc", strings.NewReader("name=o'neill"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	for _, style := range []QuoteStyle{QuoteAlways, QuoteMinimal} {
+		command, _ := Command(req, nil, WithQuoteStyle(style))
+		fmt.Println(command)
+	}
+
+	// Output:
+	// curl -X 'POST' -d 'name=o'\''neill' -H 'Content-Type: application/x-www-form-urlencoded' 'http://example.com/items?page=2&sort=asc'
+	// curl -X POST -d 'name=o'\''neill' -H 'Content-Type: application/x-www-form-urlencoded' 'http://example.com/items?page=2&sort=asc'
+}
+
+func ExampleWithQuoteStyle_never() {
+	// values free of special characters only
+	req, _ := http.NewRequest("DELETE", "https://example.com/v1/users/42", nil)
+
+	command, _ := Command(req, nil, WithQuoteStyle(QuoteNever))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X DELETE https://example.com/v1/users/42
+}
+
+func ExampleWithQuoteStyle_minimal() {
+	req, _ := http.NewRequest("GET", "https://example.com/v1/users", nil)
+
+	command, _ := Command(req, nil, WithQuoteStyle(QuoteMinimal))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X GET https://example.com/v1/users
+}
@@ -0,0 +1,120 @@
+package http2curl
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FromRawRequest returns the curl command of the raw HTTP/1.1 request
+// read from r, such as the output of httputil.DumpRequest or a request
+// copied as raw from Burp or browser devtools, generated according to
+// opts. See ReadRawRequest.
+func FromRawRequest(r io.Reader, opts ...Option) (*CurlCommand, error) {
+	req, err := ReadRawRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return GetCurlCommandWithOptions(req, opts...)
+}
+
+// ReadRawRequest reads a raw HTTP request from r into a client request.
+// As dumps do not tell the scheme, the URL is https unless the Host has a
+// port other than 443; request lines with an absolute URL keep theirs.
+// Dumps edited or copied by hand are accepted: lines may end with LF
+// alone, the Content-Length is that of the body as read, HTTP/2 and
+// HTTP/3 request lines are read as HTTP/1.1, and pseudo-headers such as
+// :authority are dropped, :authority giving the Host when there is none.
+func ReadRawRequest(r io.Reader) (*http.Request, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	raw = bytes.TrimLeft(raw, "\r\n")
+	head, body := raw, []byte(nil)
+	if i := bytes.Index(raw, []byte("\r\n\r\n")); i >= 0 {
+		head, body = raw[:i], raw[i+4:]
+	} else if i := bytes.Index(raw, []byte("\n\n")); i >= 0 {
+		head, body = raw[:i], raw[i+2:]
+	}
+
+	var (
+		b         bytes.Buffer
+		authority string
+		hasHost   bool
+		chunked   bool
+	)
+	for i, line := range strings.Split(string(head), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if i == 0 {
+			if fields := strings.Fields(line); len(fields) == 3 && strings.HasPrefix(fields[2], "HTTP/") && !strings.HasPrefix(fields[2], "HTTP/1.") {
+				line = fields[0] + " " + fields[1] + " HTTP/1.1"
+			}
+			b.WriteString(line + "\r\n")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		switch {
+		case strings.HasPrefix(line, ":"):
+			if name, value, _ := strings.Cut(line[1:], ":"); name == "authority" {
+				authority = strings.TrimSpace(value)
+			}
+			continue
+		case strings.EqualFold(name, "Content-Length"):
+			continue
+		case strings.EqualFold(name, "Host"):
+			hasHost = true
+		case strings.EqualFold(name, "Transfer-Encoding"):
+			chunked = strings.Contains(strings.ToLower(value), "chunked")
+		}
+		b.WriteString(line + "\r\n")
+	}
+	if !hasHost && authority != "" {
+		b.WriteString("Host: " + authority + "\r\n")
+	}
+	if !chunked && len(body) > 0 {
+		b.WriteString("Content-Length: " + strconv.Itoa(len(body)) + "\r\n")
+	}
+	b.WriteString("\r\n")
+	b.Write(body)
+
+	req, err := http.ReadRequest(bufio.NewReader(&b))
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid raw request: %v", err)
+	}
+	if body, err = ioutil.ReadAll(req.Body); err != nil {
+		return nil, fmt.Errorf("http2curl: invalid raw request: %v", err)
+	}
+	if !req.URL.IsAbs() {
+		if req.Host == "" {
+			return nil, fmt.Errorf("http2curl: invalid raw request: no Host")
+		}
+		scheme := "https"
+		if _, port, err := net.SplitHostPort(req.Host); err == nil && port != "443" {
+			scheme = "http"
+		}
+		if req.URL, err = url.Parse(scheme + "://" + req.Host + req.RequestURI); err != nil {
+			return nil, fmt.Errorf("http2curl: invalid raw request: %v", err)
+		}
+	}
+	req.RequestURI = ""
+	req.Header.Del("Content-Length")
+	req.ContentLength = int64(len(body))
+	req.TransferEncoding = nil
+	req.Body, req.GetBody = http.NoBody, nil
+	if len(body) > 0 {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(body)), nil }
+	}
+	return req, nil
+}
@@ -0,0 +1,42 @@
+package http2curl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleFromRawRequest() {
+	raw := "POST /api/items?draft=1 HTTP/1.1\r\n" +
+		"Host: api.example.com\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 2\r\n" +
+		"\r\n" +
+		`{"name":"gopher"}`
+
+	command, err := FromRawRequest(strings.NewReader(raw))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'POST' -d '{"name":"gopher"}' -H 'Content-Type: application/json' 'https://api.example.com/api/items?draft=1'
+}
+
+func ExampleReadRawRequest_http2() {
+	// as copied from browser devtools
+	raw := `GET /search?q=go HTTP/2
+:authority: localhost:8080
+accept: text/html
+`
+	req, err := ReadRawRequest(strings.NewReader(raw))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(req.Method, req.URL, req.Header)
+
+	// Output:
+	// GET http://localhost:8080/search?q=go map[Accept:[text/html]]
+}
@@ -0,0 +1,163 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CaptureSchemaVersion is the version of the JSON form of a Capture,
+// stored in each record as schema_version. It is increased when fields
+// are removed or change meaning; fields may be added without a new
+// version, so readers must ignore unknown fields.
+const CaptureSchemaVersion = 1
+
+// CaptureSchema is the JSON Schema of the JSON form of a Capture.
+const CaptureSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://github.com/gdey/http2curl/schema/capture-v1.json",
+  "title": "http2curl capture",
+  "type": "object",
+  "required": ["schema_version", "command", "method", "url", "body_size"],
+  "properties": {
+    "schema_version": {"const": 1},
+    "command": {"type": "string", "description": "the curl command"},
+    "method": {"type": "string"},
+    "url": {"type": "string"},
+    "body_size": {"type": "integer", "minimum": 0},
+    "body_source": {"enum": ["GetBody", "Body"]},
+    "parts": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["field_name", "size", "sha256"],
+        "properties": {
+          "field_name": {"type": "string"},
+          "file_name": {"type": "string"},
+          "content_type": {"type": "string"},
+          "size": {"type": "integer", "minimum": 0},
+          "sha256": {"type": "string", "pattern": "^[0-9a-f]{64}$"}
+        }
+      }
+    },
+    "callers": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["function", "file", "line"],
+        "properties": {
+          "function": {"type": "string"},
+          "file": {"type": "string"},
+          "line": {"type": "integer"}
+        }
+      }
+    },
+    "status_code": {"type": "integer", "description": "the status code of the response, when known"},
+    "tags": {"type": "object", "additionalProperties": {"type": "string"}},
+    "time": {"type": "string", "format": "date-time", "description": "when the request was sent, when known"},
+    "latency_ms": {"type": "number", "minimum": 0, "description": "how long the response took, when known"},
+    "error": {"type": "string", "description": "the error of a request that failed before a response"}
+  }
+}
+`
+
+// captureRecord is the JSON form of a Capture.
+type captureRecord struct {
+	SchemaVersion int               `json:"schema_version"`
+	Command       string            `json:"command"`
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	BodySize      int               `json:"body_size"`
+	BodySource    BodySource        `json:"body_source,omitempty"`
+	Parts         []MultipartPart   `json:"parts,omitempty"`
+	Callers       []CallerFrame     `json:"callers,omitempty"`
+	StatusCode    int               `json:"status_code,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	Time          *time.Time        `json:"time,omitempty"`
+	LatencyMS     float64           `json:"latency_ms,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// MarshalJSON returns the JSON form of c, described by CaptureSchema.
+func (c *Capture) MarshalJSON() ([]byte, error) {
+	record := captureRecord{
+		SchemaVersion: CaptureSchemaVersion,
+		Method:        c.Method,
+		URL:           c.URL,
+		BodySize:      c.BodySize,
+		BodySource:    c.BodySource,
+		Parts:         c.Parts,
+		Callers:       c.Callers,
+		StatusCode:    c.StatusCode,
+		Tags:          c.Tags,
+		LatencyMS:     float64(c.Latency) / float64(time.Millisecond),
+		Error:         c.Error,
+	}
+	if !c.Time.IsZero() {
+		record.Time = &c.Time
+	}
+	if c.Command != nil {
+		record.Command = c.Command.String()
+	}
+	return json.Marshal(record)
+}
+
+// UnmarshalJSON reads the JSON form of a Capture. Records of a later
+// schema version are rejected. The command is read back as a single
+// token holding the whole command line.
+func (c *Capture) UnmarshalJSON(b []byte) error {
+	var record captureRecord
+	if err := json.Unmarshal(b, &record); err != nil {
+		return err
+	}
+	if record.SchemaVersion != CaptureSchemaVersion {
+		return fmt.Errorf("http2curl: unsupported capture schema version %d", record.SchemaVersion)
+	}
+	*c = Capture{
+		Command:    &CurlCommand{record.Command},
+		Method:     record.Method,
+		URL:        record.URL,
+		BodySize:   record.BodySize,
+		BodySource: record.BodySource,
+		Parts:      record.Parts,
+		Callers:    record.Callers,
+		StatusCode: record.StatusCode,
+		Tags:       record.Tags,
+		Latency:    time.Duration(record.LatencyMS * float64(time.Millisecond)),
+		Error:      record.Error,
+	}
+	if record.Time != nil {
+		c.Time = *record.Time
+	}
+	return nil
+}
+
+// NDJSONWriter writes captures as newline delimited JSON, one record per
+// line. It is safe for concurrent use, for instance as the Logger of a
+// Transport or a Middleware.
+type NDJSONWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONWriter returns an NDJSONWriter writing to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{w: w}
+}
+
+// Write writes the record of c.
+func (nw *NDJSONWriter) Write(c *Capture) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	_, err = nw.w.Write(append(b, '\n'))
+	return err
+}
+
+// Log writes the record of c, ignoring errors, to be used as a Logger.
+func (nw *NDJSONWriter) Log(c *Capture) { nw.Write(c) }
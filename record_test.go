@@ -0,0 +1,44 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func ExampleNDJSONWriter() {
+	timeNow = func() time.Time { return time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	client := &http.Client{Transport: &Transport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+		}),
+		Logger: NewNDJSONWriter(os.Stdout).Log,
+	}}
+	client.Post("http://example.com/items", "text/plain", strings.NewReader("hello"))
+
+	// Output:
+	// {"schema_version":1,"command":"curl -X 'POST' -d 'hello' -H 'Content-Type: text/plain' 'http://example.com/items'","method":"POST","url":"http://example.com/items","body_size":5,"body_source":"GetBody","status_code":204,"time":"2021-03-04T05:30:00Z"}
+}
+
+func ExampleCaptureSchema() {
+	fmt.Println(json.Valid([]byte(CaptureSchema)))
+
+	// records can be read back
+	record := `{"schema_version":1,"command":"curl 'http://example.com/'","method":"GET","url":"http://example.com/","body_size":0,"added_later":true}`
+	var c Capture
+	err := json.Unmarshal([]byte(record), &c)
+	fmt.Println(c.Command, c.Method, err)
+
+	err = json.Unmarshal([]byte(`{"schema_version":2}`), &c)
+	fmt.Println(err)
+
+	// Output:
+	// true
+	// curl 'http://example.com/' GET <nil>
+	// http2curl: unsupported capture schema version 2
+}
@@ -0,0 +1,181 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces redacted values.
+const redactedValue = "REDACTED"
+
+// WithRedactedHeaders replaces the values of the headers names by
+// REDACTED, along with the password of the URL, so that commands can be
+// shared without leaking credentials. Without names, the headers commonly
+// carrying credentials are redacted: Authorization, Proxy-Authorization,
+// Cookie, X-Api-Key, X-Auth-Token and X-Amz-Security-Token.
+func WithRedactedHeaders(names ...string) Option {
+	if len(names) == 0 {
+		names = sensitiveHeaders
+	}
+	return func(o *Options) {
+		if o.redactHeaders == nil {
+			o.redactHeaders = map[string]bool{}
+		}
+		for _, name := range names {
+			o.redactHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithHeaderVariables renders the values of the headers names as
+// references to shell variables named after them, such as ${X_API_KEY}
+// for X-Api-Key, so that commands can be shared and run by others with
+// their own credentials. Without names, the headers listed by
+// WithRedactedHeaders are used.
+func WithHeaderVariables(names ...string) Option {
+	if len(names) == 0 {
+		names = sensitiveHeaders
+	}
+	return func(o *Options) {
+		for _, name := range names {
+			varName := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+			if !shellVarRe.MatchString(varName) {
+				o.err = fmt.Errorf("http2curl: no variable name for header %q", name)
+				return
+			}
+			// header arguments are "Name: value"
+			re := regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(name) + `: (.*)$`)
+			o.placeholders = append(o.placeholders, placeholder{re: re, name: varName})
+		}
+	}
+}
+
+// redact replaces the values of the redacted headers of req, and the
+// password of its URL when headers are redacted.
+func (o *Options) redact(req *http.Request) {
+	if len(o.redactHeaders) == 0 {
+		return
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if o.redactHeaders[http.CanonicalHeaderKey(name)] {
+			req.Header[name] = []string{redactedValue}
+			o.loss(LossRewritten, "header "+name, "value redacted")
+		}
+	}
+	if redactPassword(req.URL) {
+		o.loss(LossRewritten, "url", "password redacted")
+	}
+}
+
+// redactPassword replaces the password of u, reporting whether it has one.
+func redactPassword(u *url.URL) bool {
+	if u.User == nil {
+		return false
+	}
+	if _, ok := u.User.Password(); !ok {
+		return false
+	}
+	u.User = url.UserPassword(u.User.Username(), redactedValue)
+	return true
+}
+
+// redactedURL returns u as rendered in commands, its password and query
+// parameters redacted as by the redaction options, without reporting it,
+// for the URLs recorded next to the commands.
+func (o *Options) redactedURL(u *url.URL) string {
+	c := *u
+	if len(o.redactHeaders) > 0 {
+		redactPassword(&c)
+	}
+	if o.redactor != nil {
+		c.RawQuery = o.redactQuery(c.RawQuery, func(LossKind, string, string) {})
+	}
+	return c.String()
+}
+
+// WithRedactor calls fn for each header value of the request, cookies
+// from the cookie jar included, and for each query parameter of its URL,
+// whose names are then prefixed with "?", as in "?X-Amz-Signature". fn
+// returns the value to render, and false to leave the header value or
+// parameter out. It can mask part of a value, such as all but the first
+// characters of a token, where WithRedactedHeaders replaces it all.
+func WithRedactor(fn func(name, value string) (string, bool)) Option {
+	return func(o *Options) { o.redactor = fn }
+}
+
+// applyRedactor applies the WithRedactor callback to req.
+func (o *Options) applyRedactor(req *http.Request) {
+	if o.redactor == nil {
+		return
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var kept []string
+		for _, value := range req.Header[name] {
+			v, keep := o.redactor(name, value)
+			switch {
+			case !keep:
+				o.loss(LossDropped, "header "+name, "dropped by the redactor")
+				continue
+			case v != value:
+				o.loss(LossRewritten, "header "+name, "rewritten by the redactor")
+			}
+			kept = append(kept, v)
+		}
+		if len(kept) == 0 {
+			delete(req.Header, name)
+		} else {
+			req.Header[name] = kept
+		}
+	}
+
+	req.URL.RawQuery = o.redactQuery(req.URL.RawQuery, o.loss)
+}
+
+// redactQuery returns rawQuery with the WithRedactor callback applied to
+// its parameters, reporting the changes to loss.
+func (o *Options) redactQuery(rawQuery string, loss func(kind LossKind, field, detail string)) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	var pairs []string
+	changed := false
+	for _, pair := range strings.Split(rawQuery, "&") {
+		rawKey, rawValue, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		value, err2 := url.QueryUnescape(rawValue)
+		if err != nil || err2 != nil {
+			pairs = append(pairs, pair)
+			continue
+		}
+		v, keep := o.redactor("?"+key, value)
+		switch {
+		case !keep:
+			loss(LossDropped, "query "+key, "dropped by the redactor")
+			changed = true
+			continue
+		case v != value:
+			loss(LossRewritten, "query "+key, "rewritten by the redactor")
+			pair = rawKey + "=" + url.QueryEscape(v)
+			changed = true
+		}
+		pairs = append(pairs, pair)
+	}
+	if !changed {
+		return rawQuery
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,266 @@
+package http2curl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Perturbation changes a request in a controlled way before it is
+// replayed, to find which part of it triggers a server behavior.
+type Perturbation struct {
+	// Name describes the change in reports.
+	Name string
+	// Apply changes the request, a copy with a fresh body.
+	Apply func(req *http.Request) error
+}
+
+// DropHeader returns a Perturbation removing the header name.
+func DropHeader(name string) Perturbation {
+	return Perturbation{
+		Name:  "drop header " + http.CanonicalHeaderKey(name),
+		Apply: func(req *http.Request) error { req.Header.Del(name); return nil },
+	}
+}
+
+// TruncateBody returns a Perturbation cutting the body to n bytes.
+func TruncateBody(n int) Perturbation {
+	return Perturbation{
+		Name: fmt.Sprintf("truncate body to %d bytes", n),
+		Apply: func(req *http.Request) error {
+			body, err := readBody(req.Context(), req)
+			if err != nil {
+				return err
+			}
+			if n < len(body) {
+				body = body[:n]
+			}
+			setBody(req, body)
+			return nil
+		},
+	}
+}
+
+// ChangeMethod returns a Perturbation sending the request with method.
+func ChangeMethod(method string) Perturbation {
+	return Perturbation{
+		Name:  "change method to " + strings.ToUpper(method),
+		Apply: func(req *http.Request) error { req.Method = strings.ToUpper(method); return nil },
+	}
+}
+
+// Replayer sends captured requests again.
+type Replayer struct {
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Options are used to generate the commands of the results. Their
+	// clock, see WithClock, paces HostRate.
+	Options []Option
+	// Concurrency is the number of requests Replay sends at once, one
+	// when zero.
+	Concurrency int
+	// HostRate is the number of requests per second sent to each host at
+	// most, without limit when zero.
+	HostRate float64
+	// ThinkTime is waited between two requests sent by the same worker.
+	ThinkTime time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time // earliest time of the next request per host
+}
+
+// ReplayResult is the outcome of replaying a request.
+type ReplayResult struct {
+	// Perturbation is the name of the perturbation applied, empty for the
+	// original request.
+	Perturbation string
+	// Command is the curl command of the request sent.
+	Command *CurlCommand
+	// StatusCode, BodySize and BodySHA256 describe the response.
+	StatusCode int
+	BodySize   int
+	BodySHA256 string
+	// Differences lists how the response differs from the one to the
+	// original request.
+	Differences []string
+	// Err is set when the request could not be sent.
+	Err error
+}
+
+// Perturb replays req, then a copy of req for each perturbation, and
+// reports how each response differs from the one to req. The first result
+// is the one of req itself. An error is only returned when req cannot be
+// replayed.
+func (r *Replayer) Perturb(req *http.Request, perturbations ...Perturbation) ([]ReplayResult, error) {
+	body, err := readBody(req.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	base := r.replay(req, body, Perturbation{})
+	if base.Err != nil {
+		return nil, base.Err
+	}
+	results := []ReplayResult{base}
+	for _, p := range perturbations {
+		result := r.replay(req, body, p)
+		if result.Err == nil {
+			result.Differences = base.diff(result)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Replay sends a copy of each of reqs, at most Concurrency at once and
+// HostRate per second to each host, with ThinkTime between the requests
+// of a worker, so that a large capture can be replayed against a server
+// without flooding it. The results are in the order of reqs; requests not
+// sent once ctx is done get its error.
+func (r *Replayer) Replay(ctx context.Context, reqs []*http.Request) []ReplayResult {
+	results := make([]ReplayResult, len(reqs))
+	workers := r.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first := true
+			for i := range indexes {
+				if !first && r.ThinkTime > 0 {
+					if err := sleep(ctx, r.ThinkTime); err != nil {
+						results[i].Err = err
+						continue
+					}
+				}
+				first = false
+				results[i] = r.replayAt(ctx, reqs[i])
+			}
+		}()
+	}
+	for i := range reqs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// replayAt sends req once the rate of its host allows it.
+func (r *Replayer) replayAt(ctx context.Context, req *http.Request) ReplayResult {
+	if err := sleep(ctx, r.wait(req.URL.Host)); err != nil {
+		return ReplayResult{Err: err}
+	}
+	body, err := readBody(ctx, req)
+	if err != nil {
+		return ReplayResult{Err: err}
+	}
+	return r.replay(req.WithContext(ctx), body, Perturbation{})
+}
+
+// wait reserves the next slot of host and returns how long to wait for it.
+func (r *Replayer) wait(host string) time.Duration {
+	if r.HostRate <= 0 {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next == nil {
+		r.next = map[string]time.Time{}
+	}
+	now := newOptions(r.Options).now()
+	slot := r.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next[host] = slot.Add(time.Duration(float64(time.Second) / r.HostRate))
+	return slot.Sub(now)
+}
+
+// sleep waits for d, or returns the error of ctx once it is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// replay sends a copy of req with body, changed by p.
+func (r *Replayer) replay(req *http.Request, body []byte, p Perturbation) ReplayResult {
+	result := ReplayResult{Perturbation: p.Name}
+	out := req.Clone(req.Context())
+	out.RequestURI = ""
+	setBody(out, body)
+	if p.Apply != nil {
+		if err := p.Apply(out); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	if command, err := GetCurlCommandWithOptions(out, r.Options...); err == nil {
+		result.Command = command
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(out)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, resp.Body)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.StatusCode = resp.StatusCode
+	result.BodySize = int(n)
+	result.BodySHA256 = hex.EncodeToString(h.Sum(nil))
+	return result
+}
+
+// diff returns how other differs from r.
+func (r ReplayResult) diff(other ReplayResult) []string {
+	var diffs []string
+	if r.StatusCode != other.StatusCode {
+		diffs = append(diffs, fmt.Sprintf("status %d, was %d", other.StatusCode, r.StatusCode))
+	}
+	if r.BodySHA256 != other.BodySHA256 {
+		diffs = append(diffs, fmt.Sprintf("body of %d bytes differs from the %d bytes of the original", other.BodySize, r.BodySize))
+	}
+	return diffs
+}
+
+// setBody sets the body of req to a copy of body.
+func setBody(req *http.Request, body []byte) {
+	req.ContentLength = int64(len(body))
+	if body == nil {
+		req.Body, req.GetBody = nil, nil
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
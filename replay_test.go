@@ -0,0 +1,108 @@
+package http2curl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func ExampleReplayer_Perturb() {
+	// a server failing on requests with a Content-Type it does not expect
+	server := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Content-Type") == "application/xml" {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("boom")), Request: req}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+	})
+	replayer := &Replayer{Client: &http.Client{Transport: server}}
+
+	req, _ := http.NewRequest("POST", "http://example.com/import", strings.NewReader("<a>1</a>"))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("X-Request-Id", "42")
+
+	results, _ := replayer.Perturb(req, DropHeader("X-Request-Id"), DropHeader("Content-Type"), TruncateBody(0), ChangeMethod("put"))
+	for _, result := range results {
+		name := result.Perturbation
+		if name == "" {
+			name = "original"
+		}
+		fmt.Println(strings.TrimSpace(fmt.Sprintf("%s: %d %s", name, result.StatusCode, strings.Join(result.Differences, "; "))))
+	}
+	fmt.Println(results[2].Command)
+
+	// Output:
+	// original: 500
+	// drop header X-Request-Id: 500
+	// drop header Content-Type: 200 status 200, was 500; body of 2 bytes differs from the 4 bytes of the original
+	// truncate body to 0 bytes: 500
+	// change method to PUT: 500
+	// curl -X 'POST' -d '<a>1</a>' -H 'X-Request-Id: 42' 'http://example.com/import'
+}
+
+func ExampleReplayer_Replay() {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		most     int
+	)
+	server := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(req.URL.Path)), Request: req}, nil
+	})
+	replayer := &Replayer{
+		Client:      &http.Client{Transport: server},
+		Concurrency: 2,
+		HostRate:    20,
+	}
+
+	var reqs []*http.Request
+	for _, u := range []string{"http://a.example.com/1", "http://b.example.com/1", "http://a.example.com/2", "http://a.example.com/3"} {
+		req, _ := http.NewRequest("GET", u, nil)
+		reqs = append(reqs, req)
+	}
+
+	start := time.Now()
+	for _, result := range replayer.Replay(context.Background(), reqs) {
+		fmt.Println(result.Command, result.StatusCode, result.BodySize)
+	}
+	// three requests to a.example.com at 20 per second take 100ms at least
+	fmt.Println("at most 2 at once:", most <= 2)
+	fmt.Println("rate limited:", time.Since(start) >= 100*time.Millisecond)
+
+	// Output:
+	// curl -X 'GET' 'http://a.example.com/1' 200 2
+	// curl -X 'GET' 'http://b.example.com/1' 200 2
+	// curl -X 'GET' 'http://a.example.com/2' 200 2
+	// curl -X 'GET' 'http://a.example.com/3' 200 2
+	// at most 2 at once: true
+	// rate limited: true
+}
+
+func TestReplayerWaitClock(t *testing.T) {
+	r := &Replayer{
+		HostRate: 4,
+		Options:  []Option{WithClock(FixedClock(time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC)))},
+	}
+	for i, want := range []time.Duration{0, 250 * time.Millisecond, 500 * time.Millisecond} {
+		if got := r.wait("example.com"); got != want {
+			t.Errorf("wait %d = %v, want %v", i, got, want)
+		}
+	}
+	if got := r.wait("example.org"); got != 0 {
+		t.Errorf("wait for another host = %v, want 0", got)
+	}
+}
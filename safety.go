@@ -0,0 +1,48 @@
+package http2curl
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// cacheBustParam is the query parameter added by WithCacheBust.
+const cacheBustParam = "_cb"
+
+// randRead fills b with random bytes.
+var randRead = rand.Read
+
+// WithCacheBust adds a query parameter with a unique value to the URL, so
+// that replaying a request is never answered from a cache.
+func WithCacheBust() Option {
+	return func(o *Options) { o.cacheBust = true }
+}
+
+// WithDryRunHeader adds the header name, set to "true", to the request.
+// Services honoring such a header can then process replays of captured
+// requests without side effects.
+func WithDryRunHeader(name string) Option {
+	return func(o *Options) { o.dryRunHeader = name }
+}
+
+// applyReplaySafety applies the cache busting and dry-run options to req.
+func (o *Options) applyReplaySafety(req *http.Request) error {
+	if o.dryRunHeader != "" {
+		req.Header.Set(o.dryRunHeader, "true")
+		o.loss(LossRewritten, "header "+http.CanonicalHeaderKey(o.dryRunHeader), "set to mark the request as a dry run")
+	}
+	if o.cacheBust {
+		b := make([]byte, 8)
+		if err := o.readRandom(b); err != nil {
+			return err
+		}
+		param := cacheBustParam + "=" + hex.EncodeToString(b)
+		if req.URL.RawQuery == "" {
+			req.URL.RawQuery = param
+		} else {
+			req.URL.RawQuery += "&" + param
+		}
+		o.loss(LossRewritten, "url", "cache-busting parameter added")
+	}
+	return nil
+}
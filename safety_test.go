@@ -0,0 +1,18 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithCacheBust() {
+	random := bytes.NewReader([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+
+	req, _ := http.NewRequest("GET", "http://example.com/items?page=2", nil)
+	command, _ := Command(req, nil, WithCacheBust(), WithDryRunHeader("X-Dry-Run"), WithRandom(random))
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -H 'X-Dry-Run: true' 'http://example.com/items?page=2&_cb=0001020304050607'
+}
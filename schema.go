@@ -0,0 +1,198 @@
+package http2curl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema, and of OpenAPI response
+// schemas, used to derive response checks.
+type jsonSchema struct {
+	Type       schemaTypes            `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+	Nullable   bool                   `json:"nullable"`
+}
+
+// schemaTypes accepts both "type": "string" and "type": ["string", "null"].
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// SchemaAssertions are response checks derived from a JSON Schema. They
+// can be rendered as a jq expression or as Hurl asserts, so that replaying
+// a request also verifies the shape of the response. Only the type,
+// required, properties and items keywords, and the OpenAPI nullable
+// keyword, are taken into account.
+type SchemaAssertions struct {
+	schema *jsonSchema
+}
+
+// NewSchemaAssertions parses a JSON Schema or an OpenAPI response schema.
+func NewSchemaAssertions(schema []byte) (*SchemaAssertions, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("http2curl: invalid schema: %v", err)
+	}
+	return &SchemaAssertions{schema: &s}, nil
+}
+
+// JQ returns a jq expression that is true when its input matches the
+// schema, meant to be used with jq -e.
+func (s *SchemaAssertions) JQ() string {
+	return jqCondition(s.schema)
+}
+
+// Hurl returns Hurl asserts checking the types of the response and of its
+// required properties, and that the required properties exist.
+func (s *SchemaAssertions) Hurl() []string {
+	var asserts []string
+	hurlAsserts(s.schema, "$", &asserts)
+	return asserts
+}
+
+// WithSchemaCheck pipes the response to jq -e with the expression of s,
+// so the command fails when the response does not match the schema.
+func WithSchemaCheck(s *SchemaAssertions) Option {
+	return func(o *Options) {
+		o.flags.addFlag("-sS")
+		o.pipe = argList{
+			{kind: argPipe, value: "|"},
+			{kind: argProgram, value: "jq"},
+			{kind: argFlag, value: "-e"},
+			{kind: argValue, value: s.JQ()},
+		}
+	}
+}
+
+var jqTypes = map[string]string{
+	"null":    `type == "null"`,
+	"boolean": `type == "boolean"`,
+	"number":  `type == "number"`,
+	"integer": `(type == "number" and . == floor)`,
+	"string":  `type == "string"`,
+	"array":   `type == "array"`,
+	"object":  `type == "object"`,
+}
+
+func jqCondition(s *jsonSchema) string {
+	var conds []string
+
+	types := s.types()
+	if len(types) > 0 {
+		var alts []string
+		for _, t := range types {
+			if cond, ok := jqTypes[t]; ok {
+				alts = append(alts, cond)
+			}
+		}
+		if len(alts) > 0 {
+			conds = append(conds, "("+strings.Join(alts, " or ")+")")
+		}
+	}
+
+	var object []string
+	for _, name := range s.Required {
+		object = append(object, "has("+jqString(name)+")")
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		sub := jqCondition(s.Properties[name])
+		if sub == "true" {
+			continue
+		}
+		object = append(object, fmt.Sprintf("(if has(%s) then (.[%s] | %s) else true end)", jqString(name), jqString(name), sub))
+	}
+	if len(object) > 0 {
+		conds = append(conds, `(if type == "object" then `+strings.Join(object, " and ")+" else true end)")
+	}
+
+	if s.Items != nil {
+		if sub := jqCondition(s.Items); sub != "true" {
+			conds = append(conds, fmt.Sprintf(`(if type == "array" then all(.[]; %s) else true end)`, sub))
+		}
+	}
+
+	if len(conds) == 0 {
+		return "true"
+	}
+	return strings.Join(conds, " and ")
+}
+
+var hurlTypes = map[string]string{
+	"boolean": "isBoolean",
+	"number":  "isNumber",
+	"integer": "isInteger",
+	"string":  "isString",
+	"array":   "isCollection",
+	"object":  "isCollection",
+}
+
+func hurlAsserts(s *jsonSchema, path string, asserts *[]string) {
+	// Hurl has no "or" between predicates, only check unambiguous types
+	if types := s.types(); len(types) == 1 {
+		if predicate, ok := hurlTypes[types[0]]; ok {
+			*asserts = append(*asserts, fmt.Sprintf("jsonpath %s %s", hurlString(path), predicate))
+		}
+	}
+	for _, name := range s.Required {
+		sub := path + "[" + jqString(name) + "]"
+		if isSimpleName(name) {
+			sub = path + "." + name
+		}
+		*asserts = append(*asserts, fmt.Sprintf("jsonpath %s exists", hurlString(sub)))
+		if prop, ok := s.Properties[name]; ok {
+			hurlAsserts(prop, sub, asserts)
+		}
+	}
+}
+
+// types returns the allowed types, adding null for nullable schemas.
+func (s *jsonSchema) types() []string {
+	types := append([]string(nil), s.Type...)
+	if s.Nullable && len(types) > 0 {
+		types = append(types, "null")
+	}
+	return types
+}
+
+func jqString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func hurlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func isSimpleName(s string) bool {
+	for i, c := range s {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func sortedKeys(m map[string]*jsonSchema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
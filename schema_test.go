@@ -0,0 +1,65 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": "string"},
+		"email": {"type": "string", "nullable": true},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func ExampleSchemaAssertions_JQ() {
+	s, _ := NewSchemaAssertions([]byte(userSchema))
+	fmt.Println(s.JQ())
+
+	// Output:
+	// (type == "object") and (if type == "object" then has("id") and has("name") and (if has("email") then (.["email"] | (type == "string" or type == "null")) else true end) and (if has("id") then (.["id"] | ((type == "number" and . == floor))) else true end) and (if has("name") then (.["name"] | (type == "string")) else true end) and (if has("tags") then (.["tags"] | (type == "array") and (if type == "array" then all(.[]; (type == "string")) else true end)) else true end) else true end)
+}
+
+func ExampleSchemaAssertions_Hurl() {
+	s, _ := NewSchemaAssertions([]byte(userSchema))
+	for _, assert := range s.Hurl() {
+		fmt.Println(assert)
+	}
+
+	// Output:
+	// jsonpath "$" isCollection
+	// jsonpath "$.id" exists
+	// jsonpath "$.id" isInteger
+	// jsonpath "$.name" exists
+	// jsonpath "$.name" isString
+}
+
+func ExampleWithSchemaCheck() {
+	s, _ := NewSchemaAssertions([]byte(`{"type": "object", "required": ["id"]}`))
+
+	req, _ := http.NewRequest("GET", "http://example.com/users/1", nil)
+	command, _ := Command(req, nil, WithSchemaCheck(s), WithSelfCheck())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -sS 'http://example.com/users/1' | jq -e '(type == "object") and (if type == "object" then has("id") else true end)'
+}
+
+func ExampleWithSchemaCheck_forScripting() {
+	s, _ := NewSchemaAssertions([]byte(`{"type": "object"}`))
+	opts := []Option{ForScripting(), WithSchemaCheck(s)}
+
+	req, _ := http.NewRequest("GET", "http://example.com/users/1", nil)
+	command, _ := Command(req, nil, opts...)
+	fmt.Println(command)
+	resolved := ResolveOptions(opts...).Resolved()
+	fmt.Println(resolved["flags"], resolved["pipe"])
+
+	// Output:
+	// curl -X 'GET' --fail-with-body -sS --retry '3' --retry-connrefused 'http://example.com/users/1' | jq -e '(type == "object")'
+	// [--fail-with-body -sS --retry 3 --retry-connrefused] [| jq -e (type == "object")]
+}
@@ -0,0 +1,94 @@
+package http2curl
+
+import (
+	"io"
+	"strings"
+)
+
+// AWSRedaction redacts the credentials of requests to AWS: the headers of
+// WithRedactedHeaders, which include Authorization and
+// X-Amz-Security-Token, and the X-Amz-Signature, X-Amz-Credential and
+// X-Amz-Security-Token parameters of pre-signed URLs.
+func AWSRedaction() Option {
+	return func(o *Options) {
+		WithRedactedHeaders()(o)
+		withRedactedParams("X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token")(o)
+	}
+}
+
+// GoogleRedaction redacts the credentials of requests to Google APIs: the
+// headers of WithRedactedHeaders and X-Goog-Api-Key, and the key and
+// access_token query parameters.
+func GoogleRedaction() Option {
+	return func(o *Options) {
+		WithRedactedHeaders()(o)
+		WithRedactedHeaders("X-Goog-Api-Key")(o)
+		withRedactedParams("key", "access_token")(o)
+	}
+}
+
+// StripeRedaction redacts the credentials of requests to Stripe: the
+// headers of WithRedactedHeaders, the secret key being sent in
+// Authorization, and the Stripe-Signature of webhooks.
+func StripeRedaction() Option {
+	return func(o *Options) {
+		WithRedactedHeaders()(o)
+		WithRedactedHeaders("Stripe-Signature")(o)
+	}
+}
+
+// withRedactedParams replaces the values of the query parameters names,
+// compared regardless of case, by REDACTED, the WithRedactor callback set
+// before applying to the other parameters and headers.
+func withRedactedParams(names ...string) Option {
+	return func(o *Options) {
+		next := o.redactor
+		o.redactor = func(name, value string) (string, bool) {
+			for _, n := range names {
+				if strings.EqualFold(name, "?"+n) {
+					return redactedValue, true
+				}
+			}
+			if next != nil {
+				return next(name, value)
+			}
+			return value, true
+		}
+	}
+}
+
+// NewAWSTransport returns a Transport writing the commands of the requests
+// of the AWS SDK to w, with AWSRedaction. With the AWS SDK for Go v2:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx, config.WithHTTPClient(&http.Client{
+//		Transport: http2curl.NewAWSTransport(os.Stderr),
+//	}))
+//
+// Signed requests cannot be replayed once redacted, nor after their
+// signature expired.
+func NewAWSTransport(w io.Writer) *Transport {
+	return &Transport{Writer: w, Options: []Option{AWSRedaction()}}
+}
+
+// NewGoogleTransport returns a Transport writing the commands of the
+// requests of Google API clients to w, with GoogleRedaction. The
+// Transport must sit below the one adding the credentials, for instance
+// by giving its client to oauth2:
+//
+//	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+//		Transport: http2curl.NewGoogleTransport(os.Stderr),
+//	})
+//	client, err := google.DefaultClient(ctx, scopes...)
+func NewGoogleTransport(w io.Writer) *Transport {
+	return &Transport{Writer: w, Options: []Option{GoogleRedaction()}}
+}
+
+// NewStripeTransport returns a Transport writing the commands of the
+// requests of the Stripe SDK to w, with StripeRedaction:
+//
+//	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+//		HTTPClient: &http.Client{Transport: http2curl.NewStripeTransport(os.Stderr)},
+//	}))
+func NewStripeTransport(w io.Writer) *Transport {
+	return &Transport{Writer: w, Options: []Option{StripeRedaction()}}
+}
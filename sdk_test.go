@@ -0,0 +1,53 @@
+package http2curl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func ExampleNewAWSTransport() {
+	t := NewAWSTransport(os.Stdout)
+	t.Base = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	})
+	client := &http.Client{Transport: t}
+
+	req, _ := http.NewRequest("GET", "https://bucket.s3.amazonaws.com/report.csv?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIA%2F20210304&X-Amz-Signature=abc123", nil)
+	client.Do(req)
+	req, _ = http.NewRequest("GET", "https://sqs.us-east-1.amazonaws.com/?Action=ListQueues", nil)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIA/20210304, Signature=abc123")
+	req.Header.Set("X-Amz-Security-Token", "FwoGZXIvYXdzE")
+	client.Do(req)
+
+	// Output:
+	// curl -X 'GET' 'https://bucket.s3.amazonaws.com/report.csv?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=REDACTED&X-Amz-Signature=REDACTED'
+	// curl -X 'GET' -H 'Authorization: REDACTED' -H 'X-Amz-Security-Token: REDACTED' 'https://sqs.us-east-1.amazonaws.com/?Action=ListQueues'
+}
+
+func ExampleGoogleRedaction() {
+	req, _ := http.NewRequest("GET", "https://maps.googleapis.com/maps/api/geocode/json?address=Paris&key=AIzaSy", nil)
+	req.Header.Set("X-Goog-Api-Key", "AIzaSy")
+	req.Header.Set("X-Goog-User-Project", "my-project")
+
+	command, _ := GetCurlCommandWithOptions(req, GoogleRedaction())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'GET' -H 'X-Goog-Api-Key: REDACTED' -H 'X-Goog-User-Project: my-project' 'https://maps.googleapis.com/maps/api/geocode/json?address=Paris&key=REDACTED'
+}
+
+func ExampleStripeRedaction() {
+	req, _ := http.NewRequest("POST", "https://api.stripe.com/v1/customers", strings.NewReader("email=gopher%40example.com"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("sk_test_4eC39HqLyjWDarjtT1zdp7dc", "")
+	req.Header.Set("Stripe-Account", "acct_1032D82eZvKYlo2C")
+
+	command, _ := GetCurlCommandWithOptions(req, StripeRedaction())
+	fmt.Println(command)
+
+	// Output:
+	// curl -X 'POST' -d 'email=gopher%40example.com' -H 'Authorization: REDACTED' -H 'Content-Type: application/x-www-form-urlencoded' -H 'Stripe-Account: acct_1032D82eZvKYlo2C' 'https://api.stripe.com/v1/customers'
+}
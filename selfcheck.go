@@ -0,0 +1,67 @@
+package http2curl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithSelfCheck makes generation re-parse the rendered command the way a
+// POSIX shell would and fail when the resulting arguments differ from the
+// intended ones, for instance with QuoteNever or values curl cannot be
+// given on a command line. It is meant for debugging and tests. Commands
+// rendered for other shells, as with WithCmdExe, are not checked.
+func WithSelfCheck() Option {
+	return func(o *Options) { o.selfCheck = true }
+}
+
+// verify checks that rendering args with o is lossless.
+func (o *Options) verify(args argList) error {
+	if _, posix := o.shell.(posixShell); !posix {
+		return nil
+	}
+	// only curl's own arguments are checked, not the commands it is piped
+	// to or the prompts run before it
+	var curl argList
+	for _, a := range args {
+		if a.kind == argPipe {
+			break
+		}
+		if a.kind != argPrompt {
+			curl = append(curl, a)
+		}
+	}
+	args = curl
+
+	var want []string
+	for _, a := range args {
+		switch a.kind {
+		case argComment, argTrailingComment:
+			continue
+		case argProgram:
+			want = append(want, strings.TrimLeft(a.value, " "))
+			continue
+		}
+		if strings.IndexByte(a.value, 0) >= 0 {
+			return fmt.Errorf("http2curl: self-check: %q contains a NUL byte, which cannot be passed as an argument", a.value)
+		}
+		want = append(want, o.substitute(a.value))
+	}
+
+	plain := *o
+	plain.color = false
+	command := plain.render(args)
+	got, err := splitShell(command.String())
+	if err != nil {
+		return fmt.Errorf("http2curl: self-check: %v", err)
+	}
+
+	if len(got) != len(want) {
+		return fmt.Errorf("http2curl: self-check: rendered %d arguments, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			return fmt.Errorf("http2curl: self-check: argument %d renders as %q, want %q", i, got[i], want[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package http2curl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func ExampleWithSelfCheck() {
+	req, _ := http.NewRequest("POST", "http://example.com/?a=1&b=2", strings.NewReader("it's here"))
+
+	_, err := Command(req, nil, WithSelfCheck())
+	fmt.Println(err)
+
+	_, err = Command(req, nil, WithSelfCheck(), WithQuoteStyle(QuoteNever))
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// http2curl: self-check: unterminated quote
+}
+
+func FuzzQuote(f *testing.F) {
+	for _, seed := range []string{"", "POST", "it's", `a\b"c`, "$HOME `id`", "line\nbreak", "=cmd", "~user", "#x", "\t"} {
+		f.Add(seed, 0)
+		f.Add(seed, 3)
+	}
+	f.Fuzz(func(t *testing.T, s string, width int) {
+		if strings.IndexByte(s, 0) >= 0 {
+			t.Skip()
+		}
+		for _, style := range []QuoteStyle{QuoteAlways, QuoteMinimal} {
+			o := newOptions([]Option{WithQuoteStyle(style), WithWrapLongValues(width % 64)})
+			quoted := o.quote(s)
+			got, err := splitShell(quoted)
+			if err != nil {
+				t.Fatalf("%v: splitShell(%q): %v", style, quoted, err)
+			}
+			if len(got) != 1 || got[0] != s {
+				t.Fatalf("%v: %q renders as %q, want [%q]", style, s, quoted, s)
+			}
+		}
+	})
+}
+
+func FuzzCommand(f *testing.F) {
+	f.Add("POST", "/a b?c=d", "X-Test", "it's \"quoted\"", "@body\n")
+	f.Add("GET", "-o/etc/passwd", "Cookie", "a=1; b=2", "")
+	f.Fuzz(func(t *testing.T, method, path, name, value, body string) {
+		if strings.ContainsRune(method+path+name+value+body, 0) || strings.HasPrefix(name, "@") {
+			t.Skip()
+		}
+		// rejected with an invalid header name error
+		if strings.ContainsRune(name, ':') || strings.IndexFunc(name, isControl) >= 0 {
+			t.Skip()
+		}
+		req := &http.Request{
+			Method: method,
+			URL:    &url.URL{Scheme: "http", Host: "example.com", Path: path},
+			Header: http.Header{name: {value}},
+			Body:   nopCloser{bytes.NewBufferString(body)},
+		}
+		for _, version := range []int{OutputV1, OutputV3} {
+			// rejected from OutputV3 on
+			if version >= OutputV3 && validMethod(method) != nil {
+				continue
+			}
+			for _, style := range []QuoteStyle{QuoteAlways, QuoteMinimal} {
+				if _, err := Command(req, nil, WithSelfCheck(), WithQuoteStyle(style), WithOutputVersion(version)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package http2curl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Shell quotes arguments for a command interpreter. Implementations may
+// also implement ShellVariables and ShellComments; otherwise placeholders
+// are written as "${NAME}" and comments start with "#".
+type Shell interface {
+	// Quote escapes arg so that the shell passes it to the program as a
+	// single argument.
+	Quote(arg string) string
+	// Join joins quoted arguments into a command line.
+	Join(args []string) string
+	// Continuation ends a line continued on the next one.
+	Continuation() string
+}
+
+// ShellVariables is implemented by the shells that can reference
+// environment variables, used for placeholders.
+type ShellVariables interface {
+	Variable(name string) string
+}
+
+// ShellComments is implemented by the shells whose comments do not start
+// with "#". Comment returns the comment line for text, without a newline.
+type ShellComments interface {
+	Comment(text string) string
+}
+
+var (
+	shellsMu sync.RWMutex
+	shells   = map[string]Shell{
+		"posix":      posixShell{},
+		"cmd":        cmdShell{},
+		"powershell": powerShell{},
+	}
+)
+
+// RegisterShell makes s available under name to WithShell, for instance
+// to support BusyBox ash, Nushell or tcsh. The built-in shells are posix,
+// cmd and powershell. It panics if name is already registered or s is
+// nil.
+func RegisterShell(name string, s Shell) {
+	shellsMu.Lock()
+	defer shellsMu.Unlock()
+	if s == nil {
+		panic("http2curl: RegisterShell shell is nil")
+	}
+	if _, dup := shells[name]; dup {
+		panic("http2curl: RegisterShell called twice for shell " + name)
+	}
+	shells[name] = s
+}
+
+// LookupShell returns the shell registered under name.
+func LookupShell(name string) (Shell, bool) {
+	shellsMu.RLock()
+	defer shellsMu.RUnlock()
+	s, ok := shells[name]
+	return s, ok
+}
+
+// Shells returns the names of the registered shells, sorted.
+func Shells() []string {
+	shellsMu.RLock()
+	defer shellsMu.RUnlock()
+	names := make([]string, 0, len(shells))
+	for name := range shells {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithShell renders the command for the shell registered under name.
+// Shells other than the built-in ones quote every value, whatever the
+// quote style, unless it is QuoteNever; like cmd and powershell, they do
+// not support prompts, wrapping long values nor self checks.
+func WithShell(name string) Option {
+	return func(o *Options) {
+		s, ok := LookupShell(name)
+		if !ok {
+			o.err = fmt.Errorf("http2curl: unknown shell %q", name)
+			return
+		}
+		if syntax, ok := s.(shellSyntax); ok {
+			o.shell = syntax
+			return
+		}
+		o.shell = customShell{Shell: s, shellName: name}
+	}
+}
+
+// customShell adapts a registered Shell to the syntax used internally.
+type customShell struct {
+	Shell
+	shellName string
+}
+
+func (s customShell) name() string { return s.shellName }
+
+func (customShell) program(name string) string { return name }
+
+func (s customShell) quote(style QuoteStyle, str string) string {
+	if style == QuoteNever {
+		return str
+	}
+	return s.Quote(str)
+}
+
+func (s customShell) variable(name string) string {
+	if v, ok := s.Shell.(ShellVariables); ok {
+		return v.Variable(name)
+	}
+	return posixShell{}.variable(name)
+}
+
+func (s customShell) comment(text string, trailing bool) string {
+	c, ok := s.Shell.(ShellComments)
+	if !ok {
+		return posixShell{}.comment(text, trailing)
+	}
+	if trailing {
+		return c.Comment(text)
+	}
+	return c.Comment(text) + "\n"
+}
+
+// shellSyntax renders arguments for a command interpreter.
+type shellSyntax interface {
+	name() string
+	// program returns the command starting the program name.
+	program(name string) string
+	// quote escapes a literal value according to style.
+	quote(style QuoteStyle, str string) string
+	// variable references the environment variable name.
+	variable(name string) string
+	// comment renders a comment on a line of its own, or at the end of
+	// the command when trailing is set.
+	comment(text string, trailing bool) string
+}
+
+// posixShell is the syntax of sh, bash and zsh, used by default.
+type posixShell struct{}
+
+func (posixShell) name() string { return "posix" }
+
+func (posixShell) program(name string) string { return name }
+
+func (posixShell) quote(style QuoteStyle, str string) string { return quoteWith(style, str) }
+
+func (posixShell) variable(name string) string { return `"${` + name + `}"` }
+
+func (posixShell) comment(text string, trailing bool) string {
+	if trailing {
+		return "# " + text
+	}
+	return "# " + text + "\n"
+}
+
+func (posixShell) Quote(arg string) string { return bashEscape(arg) }
+
+func (posixShell) Join(args []string) string { return strings.Join(args, " ") }
+
+func (posixShell) Continuation() string { return " \\" }
+
+// WithCmdExe renders the command for the Windows command prompt, where
+// curl.exe ships since Windows 10: values are double-quoted, characters
+// cmd.exe would interpret are escaped with a caret and placeholders
+// reference %VAR% variables. Values cannot span lines on cmd.exe, so line
+// breaks in them are replaced with spaces and reported as losses, and
+// WithWrapLongValues is ignored. Prompts are only supported by POSIX shells.
+func WithCmdExe() Option {
+	return func(o *Options) { o.shell = cmdShell{} }
+}
+
+// cmdShell is the syntax of the Windows command prompt.
+type cmdShell struct{}
+
+func (cmdShell) name() string { return "cmd" }
+
+func (cmdShell) program(name string) string { return name }
+
+// cmdUnsafeRe matches the characters that need quoting on cmd.exe.
+var cmdUnsafeRe = regexp.MustCompile(`[^\w@+=:,./\\-]`)
+
+func (cmdShell) quote(style QuoteStyle, str string) string {
+	switch style {
+	case QuoteNever:
+		return str
+	case QuoteMinimal:
+		if str != "" && !cmdUnsafeRe.MatchString(str) {
+			return str
+		}
+	}
+	return cmdEscape(str)
+}
+
+func (cmdShell) variable(name string) string { return "%" + name + "%" }
+
+func (cmdShell) comment(text string, trailing bool) string {
+	if trailing {
+		return "& REM " + text
+	}
+	return "REM " + text + "\n"
+}
+
+func (cmdShell) Quote(arg string) string { return cmdEscape(arg) }
+
+func (cmdShell) Join(args []string) string { return strings.Join(args, " ") }
+
+func (cmdShell) Continuation() string { return " ^" }
+
+// cmdMeta are the characters cmd.exe interprets, even between double
+// quotes for % and !.
+const cmdMeta = `()%!^"<>&|`
+
+var cmdLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// cmdEscape quotes str so that the C runtime of the program started by
+// cmd.exe parses it back as a single argument. Inside double quotes,
+// cmd.exe leaves most characters alone, so that form is used when
+// possible; values with a double quote or a variable expansion character
+// are instead escaped character by character with carets.
+func cmdEscape(str string) string {
+	str = cmdLineBreaks.Replace(str)
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// backslashes before a quote are escapes for the C runtime
+			b.WriteString(strings.Repeat(`\`, 2*backslashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteByte(str[i])
+	}
+	// the closing quote would be escaped by trailing backslashes
+	b.WriteString(strings.Repeat(`\`, 2*backslashes))
+	b.WriteByte('"')
+
+	quoted := b.String()
+	if !strings.ContainsAny(str, `"%!`) {
+		return quoted
+	}
+	b.Reset()
+	for i := 0; i < len(quoted); i++ {
+		if strings.IndexByte(cmdMeta, quoted[i]) >= 0 {
+			b.WriteByte('^')
+		}
+		b.WriteByte(quoted[i])
+	}
+	return b.String()
+}
+
+// escapesControl reports whether header values holding control characters
+// are written with $'...' escapes, from OutputV3 on.
+func (o *Options) escapesControl() bool {
+	_, posix := o.shell.(posixShell)
+	return posix && o.outputVersion >= OutputV3
+}
+
+// quoteControl escapes str, which holds control characters, with ANSI-C
+// quoting, $'...', understood by bash, zsh and recent POSIX shells. It
+// does so whatever the quote style, since the value would otherwise not
+// read as a single word.
+func (o *Options) quoteControl(str string) string {
+	if len(o.placeholders) == 0 {
+		return ansiCEscape(str)
+	}
+	var b strings.Builder
+	for _, seg := range o.segments(str) {
+		if seg.isVar {
+			b.WriteString(o.shell.variable(seg.text))
+		} else {
+			b.WriteString(ansiCEscape(seg.text))
+		}
+	}
+	return b.String()
+}
+
+// ansiCEscape quotes str with $'...', escaping control characters.
+func ansiCEscape(str string) string {
+	var b strings.Builder
+	b.WriteString("$'")
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '\\' || c == '\'':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < ' ' || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// checkShell reports the losses and errors due to the syntax of the
+// target shell.
+func (o *Options) checkShell(args argList) error {
+	if _, ok := o.shell.(posixShell); ok {
+		return nil
+	}
+	_, cmd := o.shell.(cmdShell)
+	for _, a := range args {
+		switch a.kind {
+		case argPrompt:
+			return fmt.Errorf("http2curl: prompts are not supported by %s", o.shell.name())
+		case argComment, argTrailingComment, argProgram, argFlag, argPipe:
+		default:
+			if !cmd || !strings.ContainsAny(a.value, "\r\n") {
+				continue
+			}
+			field := "argument"
+			switch a.kind {
+			case argHeader:
+				field = "header " + strings.SplitN(a.value, ":", 2)[0]
+			case argBody:
+				field = "body"
+			case argURL:
+				field = "url"
+			}
+			o.loss(LossRewritten, field, "line breaks replaced with spaces, cmd.exe cannot pass them")
+		}
+	}
+	return nil
+}
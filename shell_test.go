@@ -0,0 +1,85 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+func ExampleWithCmdExe() {
+	req, _ := http.NewRequest("POST", "http://example.com/search?q=a&b=c", strings.NewReader(`{"discount":"50%"}`))
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := GetCurlCommandWithOptions(req,
+		WithCmdExe(),
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"),
+		WithLossCallback(func(e LossEvent) { fmt.Println(e) }),
+	)
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("GET", `http://example.com/`, nil)
+	req.Header.Set("X-Note", "two\nlines")
+	command, _ = GetCurlCommandWithOptions(req,
+		WithCmdExe(),
+		WithQuoteStyle(QuoteMinimal),
+		WithLossCallback(func(e LossEvent) { fmt.Println(e) }),
+	)
+	fmt.Println(command)
+
+	// Output:
+	// curl -X "POST" -d ^"{\^"discount\^":\^"50^%\^"}^" -H "Authorization: Bearer "%TOKEN% -H "Content-Type: application/json" "http://example.com/search?q=a&b=c"
+	// header X-Note rewritten: line breaks replaced with spaces, cmd.exe cannot pass them
+	// curl -X GET -H "X-Note: two lines" http://example.com/
+}
+
+func ExampleGetCurlCommandWithOptions_controlCharacters() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("X-Note", "line 1\n# rm -rf ~\r\x1b[2J")
+
+	command, _ := GetCurlCommandWithOptions(req, WithOutputVersion(OutputV3), WithSelfCheck())
+	fmt.Println(command)
+
+	// Output:
+	// # header X-Note holds control characters, written with $'...' escapes
+	// curl -H $'X-Note: line 1\n# rm -rf ~\r\x1b[2J' 'http://example.com/'
+}
+
+// tcsh quotes like sh, except that "!" still triggers history expansion
+// inside single quotes.
+type tcsh struct{}
+
+func (tcsh) Quote(arg string) string {
+	arg = strings.ReplaceAll(arg, "'", `'\''`)
+	return "'" + strings.ReplaceAll(arg, "!", `'\!'`) + "'"
+}
+
+func (tcsh) Join(args []string) string { return strings.Join(args, " ") }
+
+func (tcsh) Continuation() string { return " \\" }
+
+func (tcsh) Variable(name string) string { return `"$` + name + `"` }
+
+func ExampleRegisterShell() {
+	RegisterShell("tcsh", tcsh{})
+
+	req, _ := http.NewRequest("POST", "http://example.com/", strings.NewReader("hello!"))
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	command, _ := GetCurlCommandWithOptions(req,
+		WithShell("tcsh"),
+		WithPlaceholder(regexp.MustCompile(`Bearer (\w+)`), "TOKEN"),
+	)
+	fmt.Println(command.MultilineStringFor(tcsh{}))
+
+	_, err := GetCurlCommandWithOptions(req, WithShell("nu"))
+	fmt.Println(err)
+
+	// Output:
+	// curl -X 'POST' \
+	//   -d 'hello'\!'' \
+	//   -H 'Authorization: Bearer '"$TOKEN" \
+	//   'http://example.com/'
+	// http2curl: unknown shell "nu"
+}
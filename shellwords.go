@@ -0,0 +1,262 @@
+package http2curl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// varRefRe matches a ${NAME} variable reference.
+	varRefRe = regexp.MustCompile(`^\$\{[A-Za-z_][A-Za-z0-9_]*\}`)
+	// bareVarRefRe matches a $NAME variable reference.
+	bareVarRefRe = regexp.MustCompile(`^\$[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// errUnterminatedQuote is returned by splitShell for an unclosed quote.
+var errUnterminatedQuote = errors.New("unterminated quote")
+
+// splitShell splits a POSIX shell command line into its words, the way
+// the shell would pass them to the program. It handles single and double
+// quotes, ANSI-C quotes ($'...'), backslash escapes, line continuations
+// and comments, and keeps ${NAME} references in double quotes as
+// written. Unquoted characters that would make the shell do more than
+// split words, such as pipes, redirections, expansions or globs, are
+// reported as an error since the resulting argv could not be known
+// without running a shell.
+func splitShell(line string) ([]string, error) {
+	return splitWords(line, false)
+}
+
+// splitPastedShell splits line as splitShell does, but for commands
+// written by hand, such as curl http://example.com/?a=1, reads the glob
+// characters ?, *, [ and the ! of history expansion as themselves, as
+// non-interactive shells do when no file matches, and keeps variable
+// references as placeholders, $NAME being written ${NAME}.
+func splitPastedShell(line string) ([]string, error) {
+	return splitWords(line, true)
+}
+
+// splitWords splits line into words for splitShell and splitPastedShell,
+// the latter when pasted is set.
+func splitWords(line string, pasted bool) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		escaped bool
+	)
+	flush := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if escaped {
+			escaped = false
+			if c != '\n' { // backslash-newline is a line continuation
+				word.WriteByte(c)
+				inWord = true
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\n':
+			flush()
+		case '\\':
+			escaped = true
+		case '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, errUnterminatedQuote
+			}
+			word.WriteString(line[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case '"':
+			n, err := readDoubleQuoted(line[i+1:], &word, pasted)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i += n + 1
+		case '#':
+			if inWord {
+				word.WriteByte(c)
+				continue
+			}
+			end := strings.IndexByte(line[i:], '\n')
+			if end < 0 {
+				end = len(line) - i
+			}
+			i += end - 1
+		case '$':
+			if n := pastedVarRef(line[i:], &word, pasted); n > 0 {
+				inWord = true
+				i += n - 1
+				continue
+			}
+			if i+1 >= len(line) || line[i+1] != '\'' {
+				return nil, fmt.Errorf("unquoted special character %q", c)
+			}
+			n, err := readANSIC(line[i+2:], &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i += n + 2
+		case '*', '?', '[', '!':
+			if !pasted {
+				return nil, fmt.Errorf("unquoted special character %q", c)
+			}
+			word.WriteByte(c)
+			inWord = true
+		case '|', '&', ';', '<', '>', '(', ')', '`', '{', '}':
+			return nil, fmt.Errorf("unquoted special character %q", c)
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	flush()
+	return words, nil
+}
+
+// ansiCEscapes are the single character escapes of $'...' strings.
+var ansiCEscapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'e': 0x1b, 'E': 0x1b, 'f': '\f', 'n': '\n', 'r': '\r',
+	't': '\t', 'v': '\v', '\\': '\\', '\'': '\'', '"': '"', '?': '?',
+}
+
+// readANSIC reads an ANSI-C quoted string, $'...', up to its closing
+// quote, which s starts right after the opening one, and returns the
+// number of bytes consumed, not counting the closing quote. Hexadecimal
+// and octal escapes are supported, Unicode and control escapes are not.
+func readANSIC(s string, word *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return i, nil
+		}
+		if c != '\\' || i+1 >= len(s) {
+			word.WriteByte(c)
+			continue
+		}
+		i++
+		next := s[i]
+		if b, ok := ansiCEscapes[next]; ok {
+			word.WriteByte(b)
+			continue
+		}
+		base, digits, start := 0, 0, i
+		switch {
+		case next == 'x':
+			base, digits, start = 16, 2, i+1
+		case next >= '0' && next <= '7':
+			base, digits = 8, 3
+		default:
+			// unknown escapes are kept as written
+			word.WriteByte(c)
+			word.WriteByte(next)
+			continue
+		}
+		end := start
+		for end < len(s) && end-start < digits && isDigitIn(s[end], base) {
+			end++
+		}
+		if end == start {
+			word.WriteByte(c)
+			word.WriteByte(next)
+			continue
+		}
+		n, _ := strconv.ParseUint(s[start:end], base, 8)
+		word.WriteByte(byte(n))
+		i = end - 1
+	}
+	return 0, errUnterminatedQuote
+}
+
+// isDigitIn reports whether c is a digit in base 8 or 16.
+func isDigitIn(c byte, base int) bool {
+	switch {
+	case c >= '0' && c <= '7':
+		return true
+	case base == 16:
+		return c >= '8' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+	}
+	return false
+}
+
+// pastedVarRef writes the variable reference s starts with, if any, to
+// word as ${NAME} when pasted is set, and returns its length.
+func pastedVarRef(s string, word *strings.Builder, pasted bool) int {
+	if !pasted {
+		return 0
+	}
+	if m := varRefRe.FindString(s); m != "" {
+		word.WriteString(m)
+		return len(m)
+	}
+	if m := bareVarRefRe.FindString(s); m != "" {
+		word.WriteString("${" + m[1:] + "}")
+		return len(m)
+	}
+	return 0
+}
+
+// readDoubleQuoted reads a double quoted string up to its closing quote,
+// which s starts right after, and returns the number of bytes consumed,
+// not counting the closing quote. References to variables are kept as
+// written, or as with splitPastedShell when pasted is set.
+func readDoubleQuoted(s string, word *strings.Builder, pasted bool) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 < len(s) {
+				switch next := s[i+1]; next {
+				case '$', '`', '"', '\\':
+					word.WriteByte(next)
+					i++
+					continue
+				case '\n':
+					i++
+					continue
+				}
+			}
+			word.WriteByte(c)
+		case '$':
+			if n := pastedVarRef(s[i:], word, pasted); n > 0 {
+				i += n - 1
+				continue
+			}
+			// variable references are kept as written
+			if m := varRefRe.FindString(s[i:]); m != "" {
+				word.WriteString(m)
+				i += len(m) - 1
+				continue
+			}
+			return 0, fmt.Errorf("unescaped %q in double quotes", c)
+		case '!':
+			if !pasted {
+				return 0, fmt.Errorf("unescaped %q in double quotes", c)
+			}
+			word.WriteByte(c)
+		case '`':
+			return 0, fmt.Errorf("unescaped %q in double quotes", c)
+		default:
+			word.WriteByte(c)
+		}
+	}
+	return 0, errUnterminatedQuote
+}
@@ -0,0 +1,37 @@
+package http2curl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitShell(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  bool
+	}{
+		{line: `curl -X 'GET' 'http://x/'`, want: []string{"curl", "-X", "GET", "http://x/"}},
+		{line: `'it'\''s'`, want: []string{"it's"}},
+		{line: `"a \"b\" \$c \\ d"`, want: []string{`a "b" $c \ d`}},
+		{line: "'a'\\\n'b' c", want: []string{"ab", "c"}},
+		{line: "# comment\ncurl x # trailing", want: []string{"curl", "x"}},
+		{line: `a#b ''`, want: []string{"a#b", ""}},
+		{line: `a | b`, err: true},
+		{line: `"$HOME"`, err: true},
+		{line: `'open`, err: true},
+		{line: `a\`, err: true},
+		{line: `$'a\nb\'c\x1b\x7\101\q'`, want: []string{"a\nb'c\x1b\x07A\\q"}},
+		{line: `$'open`, err: true},
+	}
+	for _, tt := range tests {
+		got, err := splitShell(tt.line)
+		if (err != nil) != tt.err {
+			t.Errorf("splitShell(%q) error = %v, want error %v", tt.line, err, tt.err)
+			continue
+		}
+		if !tt.err && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShell(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package http2curl
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// signaturePrefix starts the comment holding the signature of a command.
+const signaturePrefix = "# hmac-sha256:"
+
+// ErrInvalidSignature is returned when a command or a bundle is not signed
+// or does not match its signature.
+var ErrInvalidSignature = errors.New("http2curl: invalid signature")
+
+// Sign returns the command followed by a comment holding the HMAC-SHA256
+// of the command with key, so that it can be checked with VerifyCommand
+// before being run. The comment is ignored by the shell.
+func (c *CurlCommand) Sign(key []byte) *CurlCommand {
+	signed := append(CurlCommand(nil), *c...)
+	signed.append(signaturePrefix + hmacHex(key, []byte(c.String())))
+	return &signed
+}
+
+// Verify checks that the command ends with a signature made by Sign with
+// key, and returns ErrInvalidSignature otherwise.
+func (c *CurlCommand) Verify(key []byte) error {
+	return VerifyCommand(c.String(), key)
+}
+
+// VerifyCommand checks a command line signed with Sign, as it would be
+// copied from a runbook, and returns ErrInvalidSignature when it was
+// modified or signed with another key.
+func VerifyCommand(line string, key []byte) error {
+	line = strings.TrimRight(line, "\r\n")
+	i := strings.LastIndex(line, " "+signaturePrefix)
+	if i < 0 {
+		return ErrInvalidSignature
+	}
+	signature := line[i+1+len(signaturePrefix):]
+	if !hmacEqual(key, []byte(line[:i]), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyBundle checks a bundle written by ExportBundle with a SigningKey:
+// the signature of its manifest, and the size and hash of every file the
+// manifest lists. Files missing from the manifest are reported as well.
+func VerifyBundle(r io.ReaderAt, size int64, key []byte) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		// an extractor may keep either copy, whatever the manifest says
+		if _, ok := files[f.Name]; ok {
+			return fmt.Errorf("http2curl: bundle file %q is duplicated", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		files[f.Name] = b
+	}
+
+	manifestJSON, ok := files["manifest.json"]
+	if !ok || !hmacEqual(key, manifestJSON, strings.TrimSpace(string(files["manifest.sig"]))) {
+		return ErrInvalidSignature
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return fmt.Errorf("http2curl: invalid manifest: %v", err)
+	}
+	listed := map[string]bool{"manifest.json": true, "manifest.sig": true}
+	for _, file := range manifest.Files {
+		listed[file.Name] = true
+		b, ok := files[file.Name]
+		if !ok {
+			return fmt.Errorf("http2curl: bundle file %q is missing", file.Name)
+		}
+		sum := sha256.Sum256(b)
+		if int64(len(b)) != file.Size || hex.EncodeToString(sum[:]) != file.SHA256 {
+			return fmt.Errorf("http2curl: bundle file %q does not match the manifest", file.Name)
+		}
+	}
+	for name := range files {
+		if !listed[name] {
+			return fmt.Errorf("http2curl: bundle file %q is not in the manifest", name)
+		}
+	}
+	return nil
+}
+
+func hmacHex(key, data []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hmacEqual reports whether signature is the hex HMAC of data with key,
+// in constant time.
+func hmacEqual(key, data []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hmac.Equal(got, mac.Sum(nil))
+}
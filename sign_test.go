@@ -0,0 +1,79 @@
+package http2curl
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func ExampleCurlCommand_Sign() {
+	key := []byte("runbook key")
+	req, _ := http.NewRequest("DELETE", "http://example.com/cache", nil)
+
+	command, _ := GetCurlCommand(req)
+	signed := command.Sign(key)
+	fmt.Println(signed)
+	fmt.Println(signed.Verify(key))
+
+	tampered := strings.Replace(signed.String(), "/cache", "/users", 1)
+	fmt.Println(VerifyCommand(tampered, key))
+	fmt.Println(VerifyCommand(signed.String(), []byte("other key")))
+
+	// Output:
+	// curl -X 'DELETE' 'http://example.com/cache' # hmac-sha256:7fb2887ce432fa3789f1652d4360fc3f749f0f4f7f3fd399365f3fd97860f44a
+	// <nil>
+	// http2curl: invalid signature
+	// http2curl: invalid signature
+}
+
+func ExampleVerifyBundle() {
+	key := []byte("bundle key")
+	req, _ := http.NewRequest("PUT", "http://example.com/", strings.NewReader("data"))
+
+	var buf bytes.Buffer
+	ExportBundle(&buf, []*http.Request{req}, BundleConfig{SigningKey: key})
+	fmt.Println(VerifyBundle(bytes.NewReader(buf.Bytes()), int64(buf.Len()), key))
+	fmt.Println(VerifyBundle(bytes.NewReader(buf.Bytes()), int64(buf.Len()), []byte("other key")))
+
+	// Output:
+	// <nil>
+	// http2curl: invalid signature
+}
+
+func TestVerifyBundleDuplicate(t *testing.T) {
+	key := []byte("bundle key")
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	var buf bytes.Buffer
+	if err := ExportBundle(&buf, []*http.Request{req}, BundleConfig{SigningKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second commands.sh after the signed one
+	var tampered bytes.Buffer
+	zw := zip.NewWriter(&tampered)
+	for _, f := range zr.File {
+		if err := zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := zw.Create("commands.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("curl http://attacker.example.com/\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyBundle(bytes.NewReader(tampered.Bytes()), int64(tampered.Len()), key)
+	if want := `http2curl: bundle file "commands.sh" is duplicated`; err == nil || err.Error() != want {
+		t.Errorf("VerifyBundle() = %v, want %s", err, want)
+	}
+}
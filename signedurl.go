@@ -0,0 +1,80 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signedURLParams are the query parameters carrying the signature of
+// pre-signed S3 and GCS URLs, replaced by WithSignedURLPlaceholders.
+var signedURLParams = []string{
+	"X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires", "X-Amz-Security-Token", "X-Amz-Signature",
+	"X-Goog-Credential", "X-Goog-Date", "X-Goog-Expires", "X-Goog-Signature",
+	"AWSAccessKeyId", "GoogleAccessId", "Expires", "Signature",
+}
+
+// WithSignedURLWarning detects pre-signed S3 and GCS URLs and adds a
+// warning comment above the command telling when it stops working, or
+// that it already has.
+func WithSignedURLWarning() Option {
+	return func(o *Options) { o.signedURLWarning = true }
+}
+
+// WithSignedURLPlaceholders replaces the signature parameters of
+// pre-signed S3 and GCS URLs by shell variables named after them, such as
+// ${X_AMZ_SIGNATURE}, so that the command can be run again with a fresh
+// signature. The variables hold the URL-encoded values.
+func WithSignedURLPlaceholders() Option {
+	return func(o *Options) {
+		for _, param := range signedURLParams {
+			re := regexp.MustCompile(`[?&]` + regexp.QuoteMeta(param) + `=([^&#]*)`)
+			name := strings.ToUpper(strings.ReplaceAll(param, "-", "_"))
+			o.placeholders = append(o.placeholders, placeholder{re: re, name: name})
+		}
+	}
+}
+
+// signedURLExpiry returns when the pre-signed URL u expires, and whether
+// it is a pre-signed URL.
+func signedURLExpiry(u *url.URL) (time.Time, bool) {
+	query := u.Query()
+	for _, prefix := range []string{"X-Amz-", "X-Goog-"} {
+		// V4 signatures: signing time and validity in seconds
+		date, expires := query.Get(prefix+"Date"), query.Get(prefix+"Expires")
+		if date == "" || expires == "" || query.Get(prefix+"Signature") == "" {
+			continue
+		}
+		signed, err := time.Parse("20060102T150405Z", date)
+		seconds, err2 := strconv.Atoi(expires)
+		if err != nil || err2 != nil {
+			continue
+		}
+		return signed.Add(time.Duration(seconds) * time.Second), true
+	}
+	// V2 signatures: expiry as a Unix time
+	if query.Get("Signature") != "" && (query.Get("AWSAccessKeyId") != "" || query.Get("GoogleAccessId") != "") {
+		if expires, err := strconv.ParseInt(query.Get("Expires"), 10, 64); err == nil {
+			return time.Unix(expires, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// signedURLWarningFor returns a warning about the expiry of pre-signed URLs.
+func (o *Options) signedURLWarningFor(u *url.URL) string {
+	if !o.signedURLWarning {
+		return ""
+	}
+	expiry, ok := signedURLExpiry(u)
+	if !ok {
+		return ""
+	}
+	if !expiry.After(o.now()) {
+		return fmt.Sprintf("warning: pre-signed URL expired at %s", o.timeString(expiry))
+	}
+	return fmt.Sprintf("warning: pre-signed URL expires at %s, the command stops working after that", o.timeString(expiry))
+}
@@ -0,0 +1,32 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func ExampleWithSignedURLWarning() {
+	clock := WithClock(FixedClock(time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC)))
+
+	req, _ := http.NewRequest("GET", "https://bucket.s3.amazonaws.com/report.pdf"+
+		"?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIDEXAMPLE%2F20210304%2Fus-east-1%2Fs3%2Faws4_request"+
+		"&X-Amz-Date=20210304T050000Z&X-Amz-Expires=3600&X-Amz-SignedHeaders=host&X-Amz-Signature=0123456789abcdef", nil)
+
+	command, _ := Command(req, nil, WithSignedURLWarning(), clock)
+	fmt.Println(command)
+
+	command, _ = Command(req, nil, WithSignedURLWarning(), WithSignedURLPlaceholders(), clock)
+	fmt.Println(command)
+
+	later := WithClock(FixedClock(time.Date(2021, 3, 4, 7, 0, 0, 0, time.UTC)))
+	command, _ = Command(req, nil, WithSignedURLWarning(), later)
+	fmt.Println((*command)[0])
+
+	// Output:
+	// # warning: pre-signed URL expires at 2021-03-04T06:00:00Z, the command stops working after that
+	// curl -X 'GET' 'https://bucket.s3.amazonaws.com/report.pdf?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIDEXAMPLE%2F20210304%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Date=20210304T050000Z&X-Amz-Expires=3600&X-Amz-SignedHeaders=host&X-Amz-Signature=0123456789abcdef'
+	// # warning: pre-signed URL expires at 2021-03-04T06:00:00Z, the command stops working after that
+	// curl -X 'GET' 'https://bucket.s3.amazonaws.com/report.pdf?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential='"${X_AMZ_CREDENTIAL}"'&X-Amz-Date='"${X_AMZ_DATE}"'&X-Amz-Expires='"${X_AMZ_EXPIRES}"'&X-Amz-SignedHeaders=host&X-Amz-Signature='"${X_AMZ_SIGNATURE}"
+	// # warning: pre-signed URL expired at 2021-03-04T06:00:00Z
+}
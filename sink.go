@@ -0,0 +1,319 @@
+package http2curl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SinkFormat is the format in which an HTTPSink posts captures.
+type SinkFormat int
+
+const (
+	// SinkJSON posts a JSON array of capture records, see CaptureSchema.
+	SinkJSON SinkFormat = iota
+	// SinkOTLP posts OpenTelemetry logs in the OTLP/HTTP JSON encoding,
+	// the body of each log record being the curl command.
+	SinkOTLP
+)
+
+// errSinkClosed is returned when queueing captures on a closed HTTPSink.
+var errSinkClosed = errors.New("http2curl: sink closed")
+
+// HTTPSink batches captures and posts them to a collector, so that fleets
+// of services can centralize their captures:
+//
+//	sink := &http2curl.HTTPSink{URL: "https://collector.example.com/v1/logs", Format: http2curl.SinkOTLP}
+//	defer sink.Close(context.Background())
+//	client := &http.Client{Transport: &http2curl.Transport{Logger: sink.Log}}
+//
+// Batches are posted when BatchSize captures are queued or every
+// FlushInterval. Failed posts are retried with an exponential backoff
+// when the error may be temporary. The queue is bounded: Put blocks while
+// it is full, Log drops the capture. An HTTPSink must not be copied after
+// first use.
+type HTTPSink struct {
+	// URL is the endpoint of the collector.
+	URL    string
+	Format SinkFormat
+	// Client posts the batches, http.DefaultClient when nil.
+	Client *http.Client
+	// Header is added to the posts, for instance for authentication.
+	Header http.Header
+	// BatchSize is the number of captures per post, 100 when zero.
+	BatchSize int
+	// FlushInterval is the longest time a capture waits to be posted, 5s
+	// when zero.
+	FlushInterval time.Duration
+	// QueueSize is the number of captures queued at most, 1000 when zero.
+	QueueSize int
+	// MaxRetries is the number of times a failed post is retried, 3 when
+	// zero, none when negative.
+	MaxRetries int
+	// RetryDelay is the delay before the first retry, doubled for each
+	// following one, 500ms when zero.
+	RetryDelay time.Duration
+	// OnError, when set, is called with the errors of posts that are
+	// given up on, and with the number of captures they held.
+	OnError func(err error, captures int)
+	// Options set the clock telling when captures are queued, see
+	// WithClock, the time of the captures without one.
+	Options []Option
+
+	once    sync.Once
+	queue   chan sinkItem
+	closing chan struct{}
+	closed  chan struct{}
+	mu      sync.RWMutex // guards sends to queue against closing
+	done    bool
+	dropped int64
+}
+
+// sinkItem is a queued capture along with the time it was queued.
+type sinkItem struct {
+	time    time.Time
+	capture *Capture
+}
+
+func (s *HTTPSink) start() {
+	s.once.Do(func() {
+		size := s.QueueSize
+		if size <= 0 {
+			size = 1000
+		}
+		s.queue = make(chan sinkItem, size)
+		s.closing = make(chan struct{})
+		s.closed = make(chan struct{})
+		go s.run()
+	})
+}
+
+// Put queues c, waiting while the queue is full until ctx is done.
+func (s *HTTPSink) Put(ctx context.Context, c *Capture) error {
+	s.start()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.done {
+		return errSinkClosed
+	}
+	select {
+	case s.queue <- sinkItem{time: newOptions(s.Options).now(), capture: c}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Log queues c, dropping it when the queue is full or the sink closed, to
+// be used as the Logger of a Transport or a Middleware.
+func (s *HTTPSink) Log(c *Capture) {
+	s.start()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.done {
+		atomic.AddInt64(&s.dropped, 1)
+		return
+	}
+	select {
+	case s.queue <- sinkItem{time: newOptions(s.Options).now(), capture: c}:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of captures dropped by Log.
+func (s *HTTPSink) Dropped() int64 { return atomic.LoadInt64(&s.dropped) }
+
+// Close posts the queued captures and stops the sink, giving up when ctx
+// is done.
+func (s *HTTPSink) Close(ctx context.Context) error {
+	s.start()
+	s.mu.Lock()
+	if !s.done {
+		s.done = true
+		close(s.closing)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run posts the batches until the sink is closed.
+func (s *HTTPSink) run() {
+	defer close(s.closed)
+	size := s.BatchSize
+	if size <= 0 {
+		size = 100
+	}
+	interval := s.FlushInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var batch []sinkItem
+	flush := func() {
+		if len(batch) > 0 {
+			s.post(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case item := <-s.queue:
+			batch = append(batch, item)
+			if len(batch) >= size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.closing:
+			// no more sends once closing, drain what is queued
+			for {
+				select {
+				case item := <-s.queue:
+					batch = append(batch, item)
+					if len(batch) >= size {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// post sends batch, retrying temporary failures.
+func (s *HTTPSink) post(batch []sinkItem) {
+	body, err := s.encode(batch)
+	if err == nil {
+		retries := s.MaxRetries
+		if retries == 0 {
+			retries = 3
+		}
+		delay := s.RetryDelay
+		if delay <= 0 {
+			delay = 500 * time.Millisecond
+		}
+		for attempt := 0; ; attempt++ {
+			var retry bool
+			retry, err = s.send(body)
+			if err == nil || !retry || attempt >= retries {
+				break
+			}
+			time.Sleep(delay << attempt)
+		}
+	}
+	if err != nil && s.OnError != nil {
+		s.OnError(err, len(batch))
+	}
+}
+
+// send posts body once, and tells whether a failure may be temporary.
+func (s *HTTPSink) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for name, values := range s.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("http2curl: collector returned %s", resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// encode returns the body posting batch.
+func (s *HTTPSink) encode(batch []sinkItem) ([]byte, error) {
+	if s.Format != SinkOTLP {
+		captures := make([]*Capture, len(batch))
+		for i, item := range batch {
+			captures[i] = item.capture
+		}
+		return json.Marshal(captures)
+	}
+
+	type value struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"`
+	}
+	type attribute struct {
+		Key   string `json:"key"`
+		Value value  `json:"value"`
+	}
+	type logRecord struct {
+		TimeUnixNano string      `json:"timeUnixNano"`
+		Body         value       `json:"body"`
+		Attributes   []attribute `json:"attributes"`
+	}
+	str := func(s string) value { return value{StringValue: &s} }
+	num := func(n int) value { s := strconv.Itoa(n); return value{IntValue: &s} }
+
+	records := make([]logRecord, len(batch))
+	for i, item := range batch {
+		c := item.capture
+		command := ""
+		if c.Command != nil {
+			command = c.Command.String()
+		}
+		t := c.Time
+		if t.IsZero() {
+			t = item.time
+		}
+		record := logRecord{
+			TimeUnixNano: strconv.FormatInt(t.UnixNano(), 10),
+			Body:         str(command),
+			Attributes: []attribute{
+				{Key: "http.request.method", Value: str(c.Method)},
+				{Key: "url.full", Value: str(c.URL)},
+				{Key: "http.request.body.size", Value: num(c.BodySize)},
+			},
+		}
+		if c.StatusCode != 0 {
+			record.Attributes = append(record.Attributes, attribute{Key: "http.response.status_code", Value: num(c.StatusCode)})
+		}
+		for _, pair := range formatTags(c.Tags) {
+			k, v, _ := strings.Cut(pair, "=")
+			record.Attributes = append(record.Attributes, attribute{Key: "http2curl.tag." + k, Value: str(v)})
+		}
+		records[i] = record
+	}
+	return json.Marshal(map[string]interface{}{
+		"resourceLogs": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": []attribute{}},
+			"scopeLogs": []interface{}{map[string]interface{}{
+				"scope":      map[string]string{"name": "github.com/gdey/http2curl"},
+				"logRecords": records,
+			}},
+		}},
+	})
+}
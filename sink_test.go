@@ -0,0 +1,50 @@
+package http2curl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func ExampleHTTPSink() {
+	// the records have the time of their capture
+	clock := WithClock(FixedClock(time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC)))
+
+	failures := 1
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Println(r.Header.Get("Authorization"), string(body))
+	}))
+	defer collector.Close()
+
+	sink := &HTTPSink{
+		URL:        collector.URL,
+		Format:     SinkOTLP,
+		Header:     http.Header{"Authorization": {"Bearer collector-token"}},
+		BatchSize:  2,
+		RetryDelay: time.Millisecond,
+	}
+	client := &http.Client{Transport: &Transport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		}),
+		Logger:  sink.Log,
+		Options: []Option{clock},
+	}}
+	client.Get("http://example.com/items")
+	client.Get("http://example.com/users")
+	sink.Close(context.Background())
+	fmt.Println(sink.Dropped())
+
+	// Output:
+	// Bearer collector-token {"resourceLogs":[{"resource":{"attributes":[]},"scopeLogs":[{"logRecords":[{"timeUnixNano":"1614835800000000000","body":{"stringValue":"curl -X 'GET' 'http://example.com/items'"},"attributes":[{"key":"http.request.method","value":{"stringValue":"GET"}},{"key":"url.full","value":{"stringValue":"http://example.com/items"}},{"key":"http.request.body.size","value":{"intValue":"0"}},{"key":"http.response.status_code","value":{"intValue":"200"}}]},{"timeUnixNano":"1614835800000000000","body":{"stringValue":"curl -X 'GET' 'http://example.com/users'"},"attributes":[{"key":"http.request.method","value":{"stringValue":"GET"}},{"key":"url.full","value":{"stringValue":"http://example.com/users"}},{"key":"http.request.body.size","value":{"intValue":"0"}},{"key":"http.response.status_code","value":{"intValue":"200"}}]}],"scope":{"name":"github.com/gdey/http2curl"}}]}]}
+	// 0
+}
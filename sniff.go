@@ -0,0 +1,73 @@
+package http2curl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// WithContentTypeCheck compares the Content-Type header with the body and
+// reports a mismatch, such as text/plain for a JSON body or the reverse,
+// in a warning comment.
+func WithContentTypeCheck() Option {
+	return func(o *Options) { o.checkContentType = true }
+}
+
+// WithFixContentType checks the Content-Type header like
+// WithContentTypeCheck and replaces it by the sniffed type when it does
+// not match the body.
+func WithFixContentType() Option {
+	return func(o *Options) {
+		o.checkContentType = true
+		o.fixContentType = true
+	}
+}
+
+// sniffContentType guesses the media type of body, recognizing JSON.
+func sniffContentType(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "application/json"
+	}
+	return http.DetectContentType(body)
+}
+
+// isJSONMediaType reports whether mediaType is JSON, including the
+// "+json" structured syntax suffix.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// contentTypeWarning compares the Content-Type of req with its body. It
+// returns a warning when they do not match, after fixing the header when
+// asked to.
+func (o *Options) contentTypeWarning(req *http.Request, body []byte) string {
+	declared := req.Header.Get("Content-Type")
+	if !o.checkContentType || declared == "" || len(body) == 0 {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil {
+		return ""
+	}
+
+	sniffed := sniffContentType(body)
+	sniffedJSON := sniffed == "application/json"
+	if sniffedJSON == isJSONMediaType(mediaType) {
+		return ""
+	}
+	// declared as JSON, and valid JSON after all, just not an object or array
+	if !sniffedJSON && json.Valid(body) {
+		return ""
+	}
+
+	if o.fixContentType {
+		req.Header.Set("Content-Type", sniffed)
+		o.loss(LossRewritten, "header Content-Type", "changed from "+declared+" to "+sniffed)
+		return fmt.Sprintf("warning: Content-Type changed from %s to %s to match the body", declared, sniffed)
+	}
+	return fmt.Sprintf("warning: Content-Type is %s but the body looks like %s", declared, sniffed)
+}
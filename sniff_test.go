@@ -0,0 +1,36 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithFixContentType() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "text/plain")
+
+	command, _ := Command(req, nil, WithContentTypeCheck())
+	fmt.Println(command)
+
+	command, _ = Command(req, nil, WithFixContentType())
+	fmt.Println(command)
+
+	// Output:
+	// # warning: Content-Type is text/plain but the body looks like application/json
+	// curl -X 'POST' -d '{"a":1}' -H 'Content-Type: text/plain' 'http://example.com/items'
+	// # warning: Content-Type changed from text/plain to application/json to match the body
+	// curl -X 'POST' -d '{"a":1}' -H 'Content-Type: application/json' 'http://example.com/items'
+}
+
+func ExampleWithContentTypeCheck() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(`a=1&b=2`))
+	req.Header.Set("Content-Type", "application/json")
+
+	command, _ := Command(req, nil, WithContentTypeCheck())
+	fmt.Println(command)
+
+	// Output:
+	// # warning: Content-Type is application/json but the body looks like text/plain; charset=utf-8
+	// curl -X 'POST' -d 'a=1&b=2' -H 'Content-Type: application/json' 'http://example.com/items'
+}
@@ -0,0 +1,117 @@
+package http2curl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithHeaderSpill moves the headers to a file when they add up to more
+// than maxBytes, to stay below the command line length limit of the
+// system, for instance when replaying signed requests with many x-amz-*
+// headers. spill is given the contents of the file, one header per line,
+// and returns the path the command reads it from with -H @path.
+//
+// Reading headers from a file needs curl 7.55.0 or later: when an older
+// version is set with WithCurlVersion, headers are kept on the command
+// line and a comment tells they may exceed the limit. Placeholders are not
+// applied to spilled headers. Header values holding a line break are kept
+// on the command line, as curl reads the file a line at a time.
+func WithHeaderSpill(maxBytes int, spill func(headers []byte) (path string, err error)) Option {
+	return func(o *Options) {
+		if maxBytes < 0 {
+			o.err = fmt.Errorf("http2curl: invalid header spill size %d", maxBytes)
+			return
+		}
+		o.spillBytes = maxBytes
+		o.spill = spill
+	}
+}
+
+// WithCurlVersion sets the version of curl the command is meant for, such
+// as "7.54.0", so that features it lacks are not used. By default the
+// latest version is assumed.
+func WithCurlVersion(version string) Option {
+	return func(o *Options) {
+		v, err := parseCurlVersion(version)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.curlVersion = v
+	}
+}
+
+// parseCurlVersion parses a major.minor[.patch] version.
+func parseCurlVersion(version string) ([3]int, error) {
+	var v [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "curl "), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("http2curl: invalid curl version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("http2curl: invalid curl version %q", version)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// curlAtLeast reports whether the target curl version is at least
+// major.minor.patch, which is the case when no version is set.
+func (o *Options) curlAtLeast(major, minor, patch int) bool {
+	if o.curlVersion == [3]int{} {
+		return true
+	}
+	want := [3]int{major, minor, patch}
+	for i := range want {
+		if o.curlVersion[i] != want[i] {
+			return o.curlVersion[i] > want[i]
+		}
+	}
+	return true
+}
+
+// spillHeaders returns the arguments for the header lines, moving them to
+// a file when they are too large.
+func (o *Options) spillHeaders(lines []string, notes *argList) (argList, error) {
+	var args argList
+	size := 0
+	for _, line := range lines {
+		size += len(line) + 1
+	}
+	if o.spill == nil || size <= o.spillBytes {
+		for _, line := range lines {
+			args.flag("-H", argHeader, line)
+		}
+		return args, nil
+	}
+
+	if !o.curlAtLeast(7, 55, 0) {
+		notes.comment(fmt.Sprintf("headers take %s, curl 7.55.0 is needed to read them from a file", o.sizeString(size)))
+		for _, line := range lines {
+			args.flag("-H", argHeader, line)
+		}
+		return args, nil
+	}
+	var spilled []string
+	for _, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			// curl would read the rest of the value as another header
+			args.flag("-H", argHeader, line)
+			continue
+		}
+		spilled = append(spilled, line)
+	}
+	if len(spilled) == 0 {
+		return args, nil
+	}
+	path, err := o.spill([]byte(strings.Join(spilled, "\n") + "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: spilling headers: %v", err)
+	}
+	args.flag("-H", argValue, "@"+path)
+	return args, nil
+}
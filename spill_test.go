@@ -0,0 +1,51 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleWithHeaderSpill() {
+	req, _ := http.NewRequest("PUT", "https://bucket.s3.amazonaws.com/key", nil)
+	req.Header.Set("X-Amz-Date", "20210304T050607Z")
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	req.Header.Set("X-Amz-Meta-Owner", "gopher")
+
+	spill := func(headers []byte) (string, error) {
+		fmt.Printf("%s", headers)
+		return "headers.txt", nil
+	}
+	command, _ := Command(req, nil, WithHeaderSpill(64, spill))
+	fmt.Println(command)
+
+	command, _ = Command(req, nil, WithHeaderSpill(64, spill), WithCurlVersion("7.54.1"))
+	fmt.Println(command)
+
+	// Output:
+	// X-Amz-Content-Sha256: UNSIGNED-PAYLOAD
+	// X-Amz-Date: 20210304T050607Z
+	// X-Amz-Meta-Owner: gopher
+	// curl -X 'PUT' -H '@headers.txt' 'https://bucket.s3.amazonaws.com/key'
+	// # headers take 93 bytes, curl 7.55.0 is needed to read them from a file
+	// curl -X 'PUT' -H 'X-Amz-Content-Sha256: UNSIGNED-PAYLOAD' -H 'X-Amz-Date: 20210304T050607Z' -H 'X-Amz-Meta-Owner: gopher' 'https://bucket.s3.amazonaws.com/key'
+}
+
+func ExampleWithHeaderSpill_lineBreak() {
+	req, _ := http.NewRequest("PUT", "https://bucket.s3.amazonaws.com/key", nil)
+	req.Header.Set("X-Amz-Date", "20210304T050607Z")
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	req.Header["X-Amz-Meta-Note"] = []string{"line\nX-Injected: 1"}
+
+	spill := func(headers []byte) (string, error) {
+		fmt.Printf("%s", headers)
+		return "headers.txt", nil
+	}
+	command, _ := Command(req, nil, WithHeaderSpill(64, spill))
+	fmt.Println(command)
+
+	// Output:
+	// X-Amz-Content-Sha256: UNSIGNED-PAYLOAD
+	// X-Amz-Date: 20210304T050607Z
+	// curl -X 'PUT' -H 'X-Amz-Meta-Note: line
+	// X-Injected: 1' -H '@headers.txt' 'https://bucket.s3.amazonaws.com/key'
+}
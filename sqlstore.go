@@ -0,0 +1,201 @@
+package http2curl
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SQLStore is a Store keeping its data in an SQLite database, opened with
+// any database/sql driver, such as modernc.org/sqlite or
+// github.com/mattn/go-sqlite3. Its tables are prefixed with http2curl_.
+type SQLStore struct {
+	db   *sql.DB
+	opts *Options
+}
+
+// NewSQLStore returns an SQLStore using db, creating its tables if needed.
+// Captures are stored at the time told by the clock set with WithClock
+// among opts.
+func NewSQLStore(db *sql.DB, opts ...Option) (*SQLStore, error) {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS http2curl_captures (
+	id     TEXT PRIMARY KEY,
+	time   TEXT NOT NULL,
+	method TEXT NOT NULL,
+	url    TEXT NOT NULL,
+	record TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS http2curl_captures_time ON http2curl_captures (time);
+CREATE TABLE IF NOT EXISTS http2curl_entries (
+	seq    INTEGER PRIMARY KEY AUTOINCREMENT,
+	id     TEXT NOT NULL UNIQUE,
+	record TEXT NOT NULL
+);`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLStore{db: db, opts: newOptions(opts)}, nil
+}
+
+// PutCapture implements Store.
+func (s *SQLStore) PutCapture(c *Capture) (string, error) {
+	record, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	now, id := newCaptureID(s.opts)
+	_, err = s.db.Exec(`INSERT INTO http2curl_captures (id, time, method, url, record) VALUES (?, ?, ?, ?, ?)`,
+		id, now.Format(storeTimeFormat), c.Method, c.URL, string(record))
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetCapture implements Store.
+func (s *SQLStore) GetCapture(id string) (*StoredCapture, error) {
+	row := s.db.QueryRow(`SELECT id, time, record FROM http2curl_captures WHERE id = ?`, id)
+	c, err := scanCapture(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+// ListCaptures implements Store.
+func (s *SQLStore) ListCaptures(q CaptureQuery) ([]StoredCapture, error) {
+	query := `SELECT id, time, record FROM http2curl_captures WHERE 1 = 1`
+	var args []interface{}
+	if !q.Since.IsZero() {
+		query += ` AND time >= ?`
+		args = append(args, q.Since.UTC().Format(storeTimeFormat))
+	}
+	if !q.Until.IsZero() {
+		query += ` AND time < ?`
+		args = append(args, q.Until.UTC().Format(storeTimeFormat))
+	}
+	if q.Method != "" {
+		query += ` AND method = ?`
+		args = append(args, q.Method)
+	}
+	if q.URLPrefix != "" {
+		// a range on the bytes of the URL, where substr counts characters
+		query += ` AND url >= ?`
+		args = append(args, q.URLPrefix)
+		if end := prefixEnd(q.URLPrefix); end != "" {
+			query += ` AND url < ?`
+			args = append(args, end)
+		}
+	}
+	query += ` ORDER BY id`
+	// tags are inside the records, they are filtered after the query
+	if q.Limit > 0 && len(q.Tags) == 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var captures []StoredCapture
+	for rows.Next() {
+		c, err := scanCapture(rows)
+		if err != nil {
+			return nil, err
+		}
+		if !q.matchesTags(c.Capture.Tags) {
+			continue
+		}
+		captures = append(captures, *c)
+		if q.Limit > 0 && len(captures) == q.Limit {
+			break
+		}
+	}
+	return captures, rows.Err()
+}
+
+// PutEntry implements Store.
+func (s *SQLStore) PutEntry(entry CatalogEntry) error {
+	record, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO http2curl_entries (id, record) VALUES (?, ?)
+ON CONFLICT (id) DO UPDATE SET record = excluded.record`, entry.ID(), string(record))
+	return err
+}
+
+// GetEntry implements Store.
+func (s *SQLStore) GetEntry(id string) (CatalogEntry, error) {
+	var record string
+	err := s.db.QueryRow(`SELECT record FROM http2curl_entries WHERE id = ?`, id).Scan(&record)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CatalogEntry{}, ErrNotFound
+	}
+	if err != nil {
+		return CatalogEntry{}, err
+	}
+	var entry CatalogEntry
+	err = json.Unmarshal([]byte(record), &entry)
+	return entry, err
+}
+
+// ListEntries implements Store.
+func (s *SQLStore) ListEntries() ([]CatalogEntry, error) {
+	rows, err := s.db.Query(`SELECT record FROM http2curl_entries ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []CatalogEntry
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			return nil, err
+		}
+		var entry CatalogEntry
+		if err := json.Unmarshal([]byte(record), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// prefixEnd returns the smallest string greater than the strings starting
+// with prefix, in byte order, or "" when there is none.
+func prefixEnd(prefix string) string {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+	return ""
+}
+
+// scanCapture reads a capture from a row of id, time and record.
+func scanCapture(row interface{ Scan(...interface{}) error }) (*StoredCapture, error) {
+	var (
+		c        StoredCapture
+		storedAt string
+		record   string
+		err      error
+	)
+	if err = row.Scan(&c.ID, &storedAt, &record); err != nil {
+		return nil, err
+	}
+	if c.Time, err = time.Parse(storeTimeFormat, storedAt); err != nil {
+		return nil, err
+	}
+	c.Capture = &Capture{}
+	if err := json.Unmarshal([]byte(record), c.Capture); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,303 @@
+package http2curl
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQL is a database/sql driver running the statements of SQLStore on
+// tables kept in memory, comparing text as SQLite does, byte by byte.
+type fakeSQL struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeTables
+}
+
+// fakeTables are the tables of a fake database.
+type fakeTables struct {
+	captures [][]string // id, time, method, url, record
+	entries  [][]string // id, record, in insertion order
+}
+
+var fakeSQLDriver = &fakeSQL{dbs: map[string]*fakeTables{}}
+
+func init() { sql.Register("http2curl-fake", fakeSQLDriver) }
+
+func (d *fakeSQL) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dbs[name] == nil {
+		d.dbs[name] = &fakeTables{}
+	}
+	return &fakeConn{d: d, t: d.dbs[name]}, nil
+}
+
+type fakeConn struct {
+	d *fakeSQL
+	t *fakeTables
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("no transactions") }
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	t := s.c.t
+	switch {
+	case strings.HasPrefix(s.query, "CREATE "):
+	case strings.HasPrefix(s.query, "INSERT INTO http2curl_captures "):
+		row := make([]string, len(args))
+		for i, arg := range args {
+			row[i] = arg.(string)
+		}
+		t.captures = append(t.captures, row)
+	case strings.HasPrefix(s.query, "INSERT INTO http2curl_entries "):
+		id, record := args[0].(string), args[1].(string)
+		for _, row := range t.entries {
+			if row[0] == id {
+				row[1] = record
+				return driver.RowsAffected(1), nil
+			}
+		}
+		t.entries = append(t.entries, []string{id, record})
+	default:
+		return nil, fmt.Errorf("fake: unexpected statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	t := s.c.t
+	switch {
+	case s.query == "SELECT record FROM http2curl_entries WHERE id = ?":
+		for _, row := range t.entries {
+			if row[0] == args[0] {
+				return &fakeRows{rows: [][]string{{row[1]}}}, nil
+			}
+		}
+		return &fakeRows{}, nil
+	case s.query == "SELECT record FROM http2curl_entries ORDER BY seq":
+		rows := &fakeRows{}
+		for _, row := range t.entries {
+			rows.rows = append(rows.rows, []string{row[1]})
+		}
+		return rows, nil
+	case strings.HasPrefix(s.query, "SELECT id, time, record FROM http2curl_captures WHERE "):
+		return t.selectCaptures(strings.TrimPrefix(s.query, "SELECT id, time, record FROM http2curl_captures WHERE "), args)
+	}
+	return nil, fmt.Errorf("fake: unexpected query %q", s.query)
+}
+
+// selectCaptures runs the conditions, order and limit of a query on the
+// captures.
+func (t *fakeTables) selectCaptures(clauses string, args []driver.Value) (driver.Rows, error) {
+	columns := map[string]int{"id": 0, "time": 1, "method": 2, "url": 3}
+	where, limit, _ := strings.Cut(clauses, " LIMIT ")
+	where = strings.TrimSuffix(where, " ORDER BY id")
+	var conds [][3]string
+	for _, cond := range strings.Split(where, " AND ") {
+		if cond == "1 = 1" {
+			continue
+		}
+		fields := strings.Fields(cond)
+		if len(fields) != 3 || fields[2] != "?" {
+			return nil, fmt.Errorf("fake: unexpected condition %q", cond)
+		}
+		conds = append(conds, [3]string{fields[0], fields[1], args[0].(string)})
+		args = args[1:]
+	}
+
+	rows := &fakeRows{}
+	sorted := append([][]string{}, t.captures...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	for _, row := range sorted {
+		match := true
+		for _, cond := range conds {
+			v := row[columns[cond[0]]]
+			switch cond[1] {
+			case "=":
+				match = match && v == cond[2]
+			case ">=":
+				match = match && v >= cond[2]
+			case "<":
+				match = match && v < cond[2]
+			default:
+				return nil, fmt.Errorf("fake: unexpected operator %q", cond[1])
+			}
+		}
+		if match {
+			rows.rows = append(rows.rows, []string{row[0], row[1], row[4]})
+		}
+	}
+	if limit != "" {
+		if n := int(args[0].(int64)); n < len(rows.rows) {
+			rows.rows = rows.rows[:n]
+		}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	rows [][]string
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) > 0 && len(r.rows[0]) == 1 {
+		return []string{"record"}
+	}
+	return []string{"id", "time", "record"}
+}
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, v := range r.rows[0] {
+		dest[i] = v
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// newFakeSQLStore returns an SQLStore on an empty fake database.
+func newFakeSQLStore(t *testing.T, opts ...Option) *SQLStore {
+	db, err := sql.Open("http2curl-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLStore(db, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLStoreCaptures(t *testing.T) {
+	now := time.Date(2021, 3, 4, 23, 59, 0, 0, time.UTC)
+	store := newFakeSQLStore(t, WithClock(ClockFunc(func() time.Time { return now })))
+	var ids []string
+	for _, target := range []string{
+		"http://example.com/?q=café&n=1",
+		"http://example.com/?q=cafe&n=2",
+		"http://example.com/?q=café&n=3",
+		"http://example.com/items",
+	} {
+		req, _ := http.NewRequest("GET", target, nil)
+		c, err := NewCapture(req, WithTag("env", "test"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := store.PutCapture(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+		now = now.Add(time.Minute)
+	}
+	if !strings.HasPrefix(ids[0], "2021-03-04T23:59:00.000000000Z-") {
+		t.Errorf("ID %s does not start with the time of the clock", ids[0])
+	}
+
+	tests := []struct {
+		q    CaptureQuery
+		want []int
+	}{
+		{CaptureQuery{}, []int{0, 1, 2, 3}},
+		{CaptureQuery{URLPrefix: "http://example.com/?q=café&"}, []int{0, 2}},
+		{CaptureQuery{URLPrefix: "http://example.com/?q=caf"}, []int{0, 1, 2}},
+		{CaptureQuery{Since: time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)}, []int{1, 2, 3}},
+		{CaptureQuery{Until: time.Date(2021, 3, 5, 0, 1, 0, 0, time.UTC)}, []int{0, 1}},
+		{CaptureQuery{URLPrefix: "http://example.com/", Limit: 2}, []int{0, 1}},
+		{CaptureQuery{URLPrefix: "http://example.com/i"}, []int{3}},
+		{CaptureQuery{Tags: map[string]string{"env": "test"}, Limit: 1}, []int{0}},
+		{CaptureQuery{Tags: map[string]string{"env": "prod"}}, nil},
+		{CaptureQuery{Method: "POST"}, nil},
+	}
+	for _, tt := range tests {
+		captures, err := store.ListCaptures(tt.q)
+		if err != nil {
+			t.Fatalf("ListCaptures(%+v): %v", tt.q, err)
+		}
+		var got []int
+		for _, c := range captures {
+			for i, id := range ids {
+				if c.ID == id {
+					got = append(got, i)
+				}
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ListCaptures(%+v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+
+	c, err := store.GetCapture(ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Capture.URL != "http://example.com/?q=café&n=1" || !c.Time.Equal(time.Date(2021, 3, 4, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("GetCapture(%s) = %s at %v", ids[0], c.Capture.URL, c.Time)
+	}
+	if _, err := store.GetCapture("unknown"); err != ErrNotFound {
+		t.Errorf("GetCapture(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLStoreEntries(t *testing.T) {
+	store := newFakeSQLStore(t)
+	store.PutEntry(CatalogEntry{Name: "items.list", Definition: Definition{URL: "http://example.com/items"}})
+	store.PutEntry(CatalogEntry{Name: "users.list", Definition: Definition{URL: "http://example.com/users"}})
+	store.PutEntry(CatalogEntry{Name: "items.list", Definition: Definition{URL: "http://example.com/items?all=1"}})
+
+	catalog, err := LoadStoreCatalog(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range catalog.List() {
+		got = append(got, entry.ID()+" "+entry.URL)
+	}
+	if want := "[items.list http://example.com/items?all=1 users.list http://example.com/users]"; fmt.Sprint(got) != want {
+		t.Errorf("entries = %v, want %s", got, want)
+	}
+	if _, err := store.GetEntry("unknown"); err != ErrNotFound {
+		t.Errorf("GetEntry(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	for prefix, want := range map[string]string{
+		"abc":      "abd",
+		"café":     "caf\xc3\xaa",
+		"a\xff":    "b",
+		"\xff\xff": "",
+	} {
+		if got := prefixEnd(prefix); got != want {
+			t.Errorf("prefixEnd(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
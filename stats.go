@@ -0,0 +1,138 @@
+package http2curl
+
+import (
+	"net/url"
+	"sort"
+)
+
+// CorpusStats are aggregate statistics over a set of captures, for
+// capacity planning and API usage reviews.
+type CorpusStats struct {
+	// Captures is the number of captures.
+	Captures int
+	// Endpoints are the statistics of each endpoint, the most used first.
+	Endpoints []EndpointStats
+	// Headers are the statistics of each header, by name.
+	Headers []HeaderStats
+	// Statuses counts the captures by response status code, for the
+	// captures holding one.
+	Statuses map[int]int
+}
+
+// EndpointStats are the statistics of the requests sent with the same
+// method to the same host and path.
+type EndpointStats struct {
+	Method string
+	// Endpoint is the host and path, without the query.
+	Endpoint string
+	Count    int
+	BodySize Percentiles
+	// Statuses counts the requests by response status code.
+	Statuses map[int]int
+}
+
+// HeaderStats are the statistics of a header, as sent in the commands.
+type HeaderStats struct {
+	Name string
+	// Count is the number of captures sending the header.
+	Count int
+	// Distinct is the number of distinct values, redacted values counting
+	// as one.
+	Distinct int
+}
+
+// Percentiles summarize a distribution of sizes, with the nearest-rank
+// method.
+type Percentiles struct {
+	P50, P90, P99, Max int
+}
+
+// Stats computes statistics over captures. Headers are read back from
+// the commands, so that redacted values are not looked at; captures whose
+// command cannot be parsed, as with WithCmdExe or pipelines, are left out
+// of the header statistics only.
+func Stats(captures []Capture) *CorpusStats {
+	stats := &CorpusStats{Captures: len(captures), Statuses: map[int]int{}}
+
+	type endpointKey struct{ method, endpoint string }
+	endpoints := map[endpointKey]*EndpointStats{}
+	sizes := map[endpointKey][]int{}
+	headers := map[string]*HeaderStats{}
+	values := map[string]map[string]bool{}
+	for _, c := range captures {
+		key := endpointKey{method: c.Method, endpoint: c.URL}
+		if u, err := url.Parse(c.URL); err == nil {
+			key.endpoint = u.Host + u.Path
+		}
+		e, ok := endpoints[key]
+		if !ok {
+			e = &EndpointStats{Method: key.method, Endpoint: key.endpoint, Statuses: map[int]int{}}
+			endpoints[key] = e
+		}
+		e.Count++
+		sizes[key] = append(sizes[key], c.BodySize)
+		if c.StatusCode != 0 {
+			e.Statuses[c.StatusCode]++
+			stats.Statuses[c.StatusCode]++
+		}
+
+		if c.Command == nil {
+			continue
+		}
+		argv, err := splitShell(c.Command.String())
+		if err != nil {
+			continue
+		}
+		sent, err := parseCurlArgs(argv)
+		if err != nil {
+			continue
+		}
+		for name, vs := range sent.headers {
+			h, ok := headers[name]
+			if !ok {
+				h = &HeaderStats{Name: name}
+				headers[name] = h
+				values[name] = map[string]bool{}
+			}
+			h.Count++
+			for _, v := range vs {
+				values[name][v] = true
+			}
+		}
+	}
+
+	for key, e := range endpoints {
+		e.BodySize = percentiles(sizes[key])
+		stats.Endpoints = append(stats.Endpoints, *e)
+	}
+	sort.Slice(stats.Endpoints, func(i, j int) bool {
+		a, b := stats.Endpoints[i], stats.Endpoints[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		return a.Method < b.Method
+	})
+	for name, h := range headers {
+		h.Distinct = len(values[name])
+		stats.Headers = append(stats.Headers, *h)
+	}
+	sort.Slice(stats.Headers, func(i, j int) bool { return stats.Headers[i].Name < stats.Headers[j].Name })
+	return stats
+}
+
+// percentiles summarizes sizes, which it sorts.
+func percentiles(sizes []int) Percentiles {
+	if len(sizes) == 0 {
+		return Percentiles{}
+	}
+	sort.Ints(sizes)
+	rank := func(p int) int {
+		// nearest rank: the smallest value with p percent of the values
+		// at or below it
+		return sizes[(p*len(sizes)+99)/100-1]
+	}
+	return Percentiles{P50: rank(50), P90: rank(90), P99: rank(99), Max: sizes[len(sizes)-1]}
+}
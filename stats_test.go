@@ -0,0 +1,46 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleStats() {
+	statuses := []int{200, 200, 404, 201, 201}
+	var captures []Capture
+	client := &http.Client{Transport: &Transport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			status := statuses[0]
+			statuses = statuses[1:]
+			return &http.Response{StatusCode: status, Body: http.NoBody, Request: req}, nil
+		}),
+		Logger:  func(c *Capture) { captures = append(captures, *c) },
+		Options: []Option{WithRedactedHeaders("Authorization")},
+	}}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		req, _ := http.NewRequest("GET", "http://example.com/items?page=1", nil)
+		req.Header.Set("Authorization", "Bearer "+user)
+		req.Header.Set("User-Agent", "client/"+user)
+		client.Do(req)
+	}
+	client.Post("http://example.com/items", "application/json", strings.NewReader(`{"name":"a"}`))
+	client.Post("http://example.com/items", "application/json", strings.NewReader(`{"name":"abcdef"}`))
+
+	stats := Stats(captures)
+	fmt.Println(stats.Captures, "captures, statuses", stats.Statuses)
+	for _, e := range stats.Endpoints {
+		fmt.Println(e.Method, e.Endpoint, e.Count, e.Statuses, "body p50", e.BodySize.P50, "max", e.BodySize.Max)
+	}
+	for _, h := range stats.Headers {
+		fmt.Println(h.Name, h.Count, h.Distinct)
+	}
+
+	// Output:
+	// 5 captures, statuses map[200:2 201:2 404:1]
+	// GET example.com/items 3 map[200:2 404:1] body p50 0 max 0
+	// POST example.com/items 2 map[201:2] body p50 12 max 17
+	// Authorization 3 1
+	// Content-Type 2 1
+	// User-Agent 3 3
+}
@@ -0,0 +1,106 @@
+package http2curl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotFound is returned by a Store for unknown captures and entries.
+var ErrNotFound = errors.New("http2curl: not found")
+
+// Store persists captures and catalog entries, so that long running
+// deployments can keep and query weeks of recorded commands. FileStore
+// and SQLStore are the implementations provided.
+type Store interface {
+	// PutCapture stores c and returns its ID. IDs sort in the order the
+	// captures were stored.
+	PutCapture(c *Capture) (string, error)
+	// GetCapture returns the capture with the given ID.
+	GetCapture(id string) (*StoredCapture, error)
+	// ListCaptures returns the captures matching q, oldest first.
+	ListCaptures(q CaptureQuery) ([]StoredCapture, error)
+	// PutEntry stores entry, replacing the one with the same ID.
+	PutEntry(entry CatalogEntry) error
+	// GetEntry returns the entry with the given ID, see CatalogEntry.ID.
+	GetEntry(id string) (CatalogEntry, error)
+	// ListEntries returns the entries in the order they were first stored.
+	ListEntries() ([]CatalogEntry, error)
+}
+
+// StoredCapture is a capture as kept by a Store. Commands read back from a
+// Store are single tokens, as with Capture.UnmarshalJSON.
+type StoredCapture struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Capture *Capture  `json:"capture"`
+}
+
+// CaptureQuery selects stored captures. Zero fields select everything.
+type CaptureQuery struct {
+	// Since and Until bound the time the captures were stored, Until
+	// being excluded.
+	Since, Until time.Time
+	// Method only selects the captures of requests with this method.
+	Method string
+	// URLPrefix only selects the captures whose URL starts with it.
+	URLPrefix string
+	// Tags only selects the captures having all these tags.
+	Tags map[string]string
+	// Limit, when positive, is the number of captures returned at most.
+	Limit int
+}
+
+func (q CaptureQuery) matches(c StoredCapture) bool {
+	return (q.Since.IsZero() || !c.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || c.Time.Before(q.Until)) &&
+		(q.Method == "" || c.Capture.Method == q.Method) &&
+		strings.HasPrefix(c.Capture.URL, q.URLPrefix) &&
+		q.matchesTags(c.Capture.Tags)
+}
+
+func (q CaptureQuery) matchesTags(tags map[string]string) bool {
+	for k, v := range q.Tags {
+		if got, ok := tags[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// storeTimeFormat is a fixed width time format, so that stored times and
+// the IDs starting with them sort as strings.
+const storeTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// captureSeq tells apart the captures stored at the same time.
+var captureSeq uint32
+
+// newCaptureID returns the time a capture is stored, told by the clock of
+// o, and its ID.
+func newCaptureID(o *Options) (time.Time, string) {
+	now := o.now().UTC()
+	return now, fmt.Sprintf("%s-%04x", now.Format(storeTimeFormat), uint16(atomic.AddUint32(&captureSeq, 1)))
+}
+
+// StoreLogger returns a function storing captures in s, ignoring errors,
+// to be used as the Logger of a Transport or a Middleware.
+func StoreLogger(s Store) func(*Capture) {
+	return func(c *Capture) { s.PutCapture(c) }
+}
+
+// LoadStoreCatalog returns a Catalog holding the entries of s.
+func LoadStoreCatalog(s Store) (*Catalog, error) {
+	entries, err := s.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	c := NewCatalog()
+	for _, entry := range entries {
+		if err := c.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
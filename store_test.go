@@ -0,0 +1,51 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+func ExampleFileStore() {
+	dir, _ := os.MkdirTemp("", "http2curl")
+	defer os.RemoveAll(dir)
+	now := time.Date(2021, 3, 4, 23, 59, 0, 0, time.UTC)
+	clock := WithClock(ClockFunc(func() time.Time { return now }))
+
+	store, _ := NewFileStore(dir, clock)
+	client := &http.Client{Transport: &Transport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		}),
+		Logger:  StoreLogger(store),
+		Options: []Option{clock},
+	}}
+	client.Get("http://example.com/items")
+	now = now.Add(2 * time.Minute)
+	client.Get("http://example.com/users")
+
+	captures, _ := store.ListCaptures(CaptureQuery{Since: time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)})
+	for _, c := range captures {
+		fmt.Println(c.Time, c.Capture.Command, c.Capture.StatusCode)
+	}
+	c, _ := store.GetCapture(captures[0].ID)
+	fmt.Println(c.Capture.URL)
+	_, err := store.GetCapture("2021-03-05T00:00:00.000000000Z-ffff")
+	fmt.Println(err)
+
+	store.PutEntry(CatalogEntry{Name: "items.list", Definition: Definition{URL: "http://example.com/items"}})
+	store.PutEntry(CatalogEntry{Name: "users.list", Definition: Definition{URL: "http://example.com/users"}})
+	store.PutEntry(CatalogEntry{Name: "items.list", Definition: Definition{URL: "http://example.com/items?all=1"}})
+	catalog, _ := LoadStoreCatalog(store)
+	for _, entry := range catalog.List() {
+		fmt.Println(entry.ID(), entry.URL)
+	}
+
+	// Output:
+	// 2021-03-05 00:01:00 +0000 UTC curl -X 'GET' 'http://example.com/users' 200
+	// http://example.com/users
+	// http2curl: not found
+	// items.list http://example.com/items?all=1
+	// users.list http://example.com/users
+}
@@ -0,0 +1,58 @@
+package http2curl
+
+import (
+	"encoding/csv"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// captureColumns are the columns written by WriteCaptureTable.
+var captureColumns = []string{"timestamp", "method", "host", "path", "status", "latency_ms", "tags", "command"}
+
+// WriteCaptureTable writes captures as a spreadsheet-friendly table, one
+// row per capture after a header row, for triage and sharing: the time
+// the request was sent, its method, host and path, the status and latency
+// of its response, its tags and its curl command, without the comments
+// above it. Use ',' as comma for CSV and '\t' for TSV. Unknown times,
+// statuses and latencies are left empty.
+func WriteCaptureTable(w io.Writer, captures []Capture, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(captureColumns); err != nil {
+		return err
+	}
+	for _, c := range captures {
+		var timestamp, host, path, status, latency, command string
+		if !c.Time.IsZero() {
+			timestamp = c.Time.UTC().Format(time.RFC3339Nano)
+		}
+		if u, err := url.Parse(c.URL); err == nil {
+			host, path = u.Host, u.EscapedPath()
+		}
+		if c.StatusCode != 0 {
+			status = strconv.Itoa(c.StatusCode)
+		}
+		if c.Latency != 0 {
+			latency = strconv.FormatFloat(float64(c.Latency)/float64(time.Millisecond), 'f', -1, 64)
+		}
+		if c.Command != nil {
+			// comments and prompts would make rows span several lines
+			var tokens []string
+			for _, token := range *c.Command {
+				if !strings.HasSuffix(token, "\n") {
+					tokens = append(tokens, token)
+				}
+			}
+			command = strings.Join(tokens, " ")
+		}
+		row := []string{timestamp, c.Method, host, path, status, latency, strings.Join(formatTags(c.Tags), " "), command}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
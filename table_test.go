@@ -0,0 +1,35 @@
+package http2curl
+
+import (
+	"net/http"
+	"os"
+	"time"
+)
+
+func ExampleWriteCaptureTable() {
+	clock := time.Date(2021, 3, 4, 5, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { clock = clock.Add(25 * time.Millisecond); return clock }
+	defer func() { timeNow = time.Now }()
+
+	var captures []Capture
+	client := &http.Client{Transport: &Transport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.URL.Path == "/missing" {
+				status = http.StatusNotFound
+			}
+			return &http.Response{StatusCode: status, Body: http.NoBody, Request: req}, nil
+		}),
+		Logger:  func(c *Capture) { captures = append(captures, *c) },
+		Options: []Option{WithTag("team", "search")},
+	}}
+	client.Get("https://api.example.com/items?q=a,b")
+	client.Get("https://api.example.com/missing")
+
+	WriteCaptureTable(os.Stdout, captures, ',')
+
+	// Output:
+	// timestamp,method,host,path,status,latency_ms,tags,command
+	// 2021-03-04T05:30:00.025Z,GET,api.example.com,/items,200,25,team=search,"curl -X 'GET' 'https://api.example.com/items?q=a,b'"
+	// 2021-03-04T05:30:00.075Z,GET,api.example.com,/missing,404,25,team=search,curl -X 'GET' 'https://api.example.com/missing'
+}
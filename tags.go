@@ -0,0 +1,74 @@
+package http2curl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type tagsKey struct{}
+
+// WithTag attaches the tag key=value to the commands and captures, such
+// as WithTag("service", "billing"), so that large corpora can be sliced
+// by team, feature or environment. Tags are written in a comment above
+// the command and kept by captures, sinks, stores and exports. Keys
+// cannot be empty nor hold spaces or "=".
+func WithTag(key, value string) Option {
+	return func(o *Options) {
+		if key == "" || strings.ContainsAny(key, "= \t\r\n") {
+			o.err = fmt.Errorf("http2curl: invalid tag key %q", key)
+			return
+		}
+		if o.tags == nil {
+			o.tags = map[string]string{}
+		}
+		o.tags[key] = value
+	}
+}
+
+// ContextWithTag returns a copy of ctx carrying the tag key=value, which
+// is attached to the commands and captures of the requests using it, as
+// done by WithTag. Tags set by options take precedence.
+func ContextWithTag(ctx context.Context, key, value string) context.Context {
+	tags := TagsFromContext(ctx)
+	tags[key] = value
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// TagsFromContext returns the tags stored in ctx by ContextWithTag.
+func TagsFromContext(ctx context.Context) map[string]string {
+	stored, _ := ctx.Value(tagsKey{}).(map[string]string)
+	// never share the map with the context
+	tags := make(map[string]string, len(stored))
+	for k, v := range stored {
+		tags[k] = v
+	}
+	return tags
+}
+
+// tagsFor returns the tags of ctx, the context of a request, overridden
+// by those of the options, nil when there are none.
+func (o *Options) tagsFor(ctx context.Context) map[string]string {
+	tags := TagsFromContext(o.context())
+	for k, v := range TagsFromContext(ctx) {
+		tags[k] = v
+	}
+	for k, v := range o.tags {
+		tags[k] = v
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// formatTags returns tags as sorted key=value pairs.
+func formatTags(tags map[string]string) []string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
@@ -0,0 +1,43 @@
+package http2curl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func ExampleWithTag() {
+	ctx := ContextWithTag(context.Background(), "env", "staging")
+	req, _ := http.NewRequestWithContext(ctx, "POST", "http://billing.example.com/invoices", strings.NewReader(`{"amount":42}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	capture, _ := NewCapture(req, WithTag("service", "billing"), WithTag("env", "test"))
+	fmt.Println(capture.Command)
+
+	b, _ := json.Marshal(capture)
+	fmt.Println(string(b))
+
+	entry, _ := capture.CatalogEntry("invoices.create")
+	enc := json.NewEncoder(os.Stdout)
+	enc.Encode(entry)
+
+	dir, _ := os.MkdirTemp("", "captures")
+	defer os.RemoveAll(dir)
+	store, _ := NewFileStore(dir)
+	store.PutCapture(capture)
+	other, _ := NewCapture(req)
+	store.PutCapture(other)
+	billing, _ := store.ListCaptures(CaptureQuery{Tags: map[string]string{"service": "billing"}})
+	staging, _ := store.ListCaptures(CaptureQuery{Tags: map[string]string{"env": "staging"}})
+	fmt.Println(len(billing), len(staging))
+
+	// Output:
+	// # tags: env=test service=billing
+	// curl -X 'POST' -d '{"amount":42}' -H 'Content-Type: application/json' 'http://billing.example.com/invoices'
+	// {"schema_version":1,"command":"# tags: env=test service=billing\ncurl -X 'POST' -d '{\"amount\":42}' -H 'Content-Type: application/json' 'http://billing.example.com/invoices'","method":"POST","url":"http://billing.example.com/invoices","body_size":13,"body_source":"GetBody","tags":{"env":"test","service":"billing"}}
+	// {"name":"invoices.create","tags":["env=test","service=billing"],"method":"POST","url":"http://billing.example.com/invoices","headers":{"Content-Type":"application/json"},"body":"{\"amount\":42}"}
+	// 1 1
+}
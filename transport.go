@@ -0,0 +1,211 @@
+package http2curl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Transport is an http.RoundTripper logging the curl command of every
+// request it sends, for instance to debug the requests a service makes:
+//
+//	client := &http.Client{Transport: &http2curl.Transport{Writer: os.Stderr}}
+//
+// Commands are written before the requests are sent, captures are logged
+// once the response is received. When a request fails before any
+// response, for instance on a DNS, connection or TLS error, the error is
+// recorded in the capture and described in a comment above its command,
+// or on the line after it once written. Failing to generate a command
+// never fails the request. A Transport must not be copied after first
+// use.
+type Transport struct {
+	// Base sends the requests, http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// Writer, when set, receives the commands, one per line.
+	Writer io.Writer
+	// Logger, when set, is called with the Capture of each request, along
+	// with the status code of its response when one was received. It may
+	// be called concurrently.
+	Logger func(*Capture)
+	// Filter, when set, selects the requests to log.
+	Filter func(*http.Request) bool
+	// Options are used to generate the commands.
+	Options []Option
+	// Callers is the number of calls issuing the request to record in the
+	// captures, the first one also being shown in a comment above the
+	// command. The calls of net/http are left out, CallerSkip leaves out
+	// more, for instance those of an API client wrapper.
+	Callers    int
+	CallerSkip int
+	// Keep, when set, is called with the response, or the error, of each
+	// request, and the request is only logged when it returns true, see
+	// KeepErrors. The body and the command are then only generated for
+	// the requests kept, and commands are written once the response is
+	// received.
+	Keep func(resp *http.Response, err error) bool
+
+	mu sync.Mutex // serializes writes to Writer
+}
+
+// RoundTrip logs the curl command of req and sends it with Base.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if (t.Writer == nil && t.Logger == nil) || (t.Filter != nil && !t.Filter(req)) {
+		return base.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify req, reading its body may replace it
+	out := req.Clone(req.Context())
+	opts := t.Options
+	frames := callers(t.CallerSkip, t.Callers)
+	if len(frames) > 0 {
+		opts = append(opts[:len(opts):len(opts)], WithComment("from "+frames[0].String()))
+	}
+	if t.Keep != nil {
+		return t.roundTripKept(base, out, opts, frames)
+	}
+	capture, err := NewCapture(out, opts...)
+	if err != nil {
+		return base.RoundTrip(out)
+	}
+	capture.Callers = frames
+	if t.Writer != nil {
+		t.mu.Lock()
+		fmt.Fprintln(t.Writer, capture.Command)
+		t.mu.Unlock()
+	}
+	clock := newOptions(opts)
+	capture.Time = clock.now()
+	resp, err := base.RoundTrip(out)
+	capture.Latency = clock.now().Sub(capture.Time)
+	if err != nil && resp == nil {
+		comment := failed(capture, opts, err)
+		if t.Writer != nil {
+			t.mu.Lock()
+			fmt.Fprint(t.Writer, comment)
+			t.mu.Unlock()
+		}
+	}
+	if t.Logger != nil {
+		if resp != nil {
+			capture.StatusCode = resp.StatusCode
+		}
+		t.Logger(capture)
+	}
+	return resp, err
+}
+
+// roundTripKept sends req and logs it when t.Keep selects its response.
+func (t *Transport) roundTripKept(base http.RoundTripper, req *http.Request, opts []Option, frames []CallerFrame) (*http.Response, error) {
+	pending := Prepare(req, opts...)
+	clock := newOptions(opts)
+	start := clock.now()
+	resp, err := base.RoundTrip(req)
+	latency := clock.now().Sub(start)
+	if !t.Keep(resp, err) {
+		return resp, err
+	}
+	capture, cerr := pending.Finalize()
+	if cerr != nil {
+		return resp, err
+	}
+	capture.Callers = frames
+	capture.Time, capture.Latency = start, latency
+	if resp != nil {
+		capture.StatusCode = resp.StatusCode
+	} else if err != nil {
+		failed(capture, opts, err)
+	}
+	if t.Writer != nil {
+		t.mu.Lock()
+		fmt.Fprintln(t.Writer, capture.Command)
+		t.mu.Unlock()
+	}
+	if t.Logger != nil {
+		t.Logger(capture)
+	}
+	return resp, err
+}
+
+// failed records err, the error of the request of capture, in capture and
+// in a comment above its command, and returns the comment.
+func failed(capture *Capture, opts []Option, err error) string {
+	capture.Error = err.Error()
+	comment := newOptions(opts).token(arg{kind: argComment, value: transportError(err)})
+	command := append(CurlCommand{comment}, *capture.Command...)
+	capture.Command = &command
+	return comment
+}
+
+// transportError describes err, the error of a request that got no
+// response, telling what failed when it is known.
+func transportError(err error) string {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		recordErr    tls.RecordHeaderError
+	)
+	what := "request failed"
+	switch {
+	case errors.As(err, &dnsErr):
+		what = "DNS lookup failed"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		what = "connection refused"
+	case errors.Is(err, syscall.ECONNRESET):
+		what = "connection reset"
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr),
+		errors.As(err, &recordErr), strings.Contains(err.Error(), "tls: "):
+		what = "TLS handshake failed"
+	case errors.Is(err, context.Canceled):
+		what = "request canceled"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		what = "request timed out"
+	}
+	return what + ": " + err.Error()
+}
+
+// KeepErrors is a Transport Keep function selecting the requests that
+// failed or got a response with a 4xx or 5xx status.
+func KeepErrors(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusBadRequest
+}
+
+// FilterHosts returns a Transport filter selecting the requests to the
+// given hosts, with or without their port.
+func FilterHosts(hosts ...string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		for _, host := range hosts {
+			if req.URL.Host == host || req.URL.Hostname() == host {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterPaths returns a Transport filter selecting the requests whose path
+// starts with one of prefixes.
+func FilterPaths(prefixes ...string) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(req.URL.Path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
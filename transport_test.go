@@ -0,0 +1,91 @@
+package http2curl
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// roundTripFunc is an http.RoundTripper answering without a network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func ExampleTransport() {
+	echo := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body))), Request: req}, nil
+	})
+	client := &http.Client{Transport: &Transport{
+		Base:   echo,
+		Writer: os.Stdout,
+		Filter: FilterPaths("/api/"),
+	}}
+
+	resp, _ := client.Post("http://example.com/api/items", "application/json", strings.NewReader(`{"a":1}`))
+	body, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(body))
+
+	resp, _ = client.Get("http://example.com/healthz")
+	fmt.Println(resp.StatusCode)
+
+	// Output:
+	// curl -X 'POST' -d '{"a":1}' -H 'Content-Type: application/json' 'http://example.com/api/items'
+	// {"a":1}
+	// 200
+}
+
+func ExampleTransport_callers() {
+	ok := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	var capture *Capture
+	client := &http.Client{Transport: &Transport{
+		Base:    ok,
+		Writer:  os.Stdout,
+		Logger:  func(c *Capture) { capture = c },
+		Callers: 2,
+	}}
+
+	fetchItems := func() { client.Get("http://example.com/items") }
+	fetchItems()
+	for _, frame := range capture.Callers {
+		fmt.Println(frame.Function)
+	}
+
+	// Output:
+	// # from transport_test.go:57 (github.com/gdey/http2curl/v2.ExampleTransport_callers.func3)
+	// curl -X 'GET' 'http://example.com/items'
+	// github.com/gdey/http2curl/v2.ExampleTransport_callers.func3
+	// github.com/gdey/http2curl/v2.ExampleTransport_callers
+}
+
+func ExampleTransport_connectionError() {
+	refused := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	})
+	var capture *Capture
+	client := &http.Client{Transport: &Transport{
+		Base:   refused,
+		Writer: os.Stdout,
+		Logger: func(c *Capture) { capture = c },
+	}}
+
+	client.Get("http://localhost:8080/healthz")
+	fmt.Println(capture.Error)
+	fmt.Println(capture.Command)
+
+	// Output:
+	// curl -X 'GET' 'http://localhost:8080/healthz'
+	// # connection refused: dial tcp: connect: connection refused
+	// dial tcp: connect: connection refused
+	// # connection refused: dial tcp: connect: connection refused
+	// curl -X 'GET' 'http://localhost:8080/healthz'
+}
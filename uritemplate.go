@@ -0,0 +1,124 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// URITemplate is a URL rendered as an RFC 6570 URI template, with the
+// values of its variables as found in the original URL.
+type URITemplate struct {
+	// Template is the URI template, e.g.
+	// "https://example.com/users/{user_id}{?fields}".
+	Template string
+	// Vars are the variables of Template, in order of appearance.
+	Vars []TemplateVar
+}
+
+// TemplateVar is a variable of a URITemplate and its captured value.
+type TemplateVar struct {
+	Name  string
+	Value string
+}
+
+var (
+	// idSegmentRe matches path segments that look like identifiers:
+	// numbers, UUIDs and long hexadecimal strings.
+	idSegmentRe = regexp.MustCompile(`^(?:[0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+	// resourceNameRe matches path segments usable to name the following
+	// identifier.
+	resourceNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// NewURITemplate turns u into a URI template: path segments that look like
+// identifiers become variables named after the preceding segment (the
+// "42" of "/users/42" becomes {user_id}) and query parameters become a
+// form-style query expansion. Parameters given more than once, or named
+// like a variable of the path, are kept as they are, as expanding their
+// values joined would send a single one. The password of the URL is left
+// out.
+func NewURITemplate(u *url.URL) *URITemplate {
+	t := &URITemplate{}
+	used := map[string]int{}
+	name := func(base string) string {
+		v := base
+		for n := 2; used[v] > 0; n++ {
+			v = fmt.Sprintf("%s%d", base, n)
+		}
+		used[v]++
+		return v
+	}
+
+	var b strings.Builder
+	if u.Scheme != "" {
+		b.WriteString(u.Scheme + "://")
+	}
+	if u.User != nil {
+		// keep the password out of the template
+		b.WriteString(url.User(u.User.Username()).String() + "@")
+	}
+	b.WriteString(u.Host)
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, seg := range segments {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		value, err := url.PathUnescape(seg)
+		if err != nil || !idSegmentRe.MatchString(value) {
+			b.WriteString(seg)
+			continue
+		}
+		base := "id"
+		if i > 0 && resourceNameRe.MatchString(segments[i-1]) && !idSegmentRe.MatchString(segments[i-1]) {
+			base = strings.TrimSuffix(segments[i-1], "s") + "_id"
+		}
+		v := name(base)
+		b.WriteString("{" + v + "}")
+		t.Vars = append(t.Vars, TemplateVar{Name: v, Value: value})
+	}
+
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var literal, vars []string
+	for _, k := range keys {
+		// names must also be valid shell variables for Assignments
+		if !shellVarRe.MatchString(k) || used[k] > 0 || len(query[k]) > 1 {
+			for _, v := range query[k] {
+				literal = append(literal, url.QueryEscape(k)+"="+url.QueryEscape(v))
+			}
+			continue
+		}
+		used[k]++
+		vars = append(vars, k)
+		t.Vars = append(t.Vars, TemplateVar{Name: k, Value: query[k][0]})
+	}
+	if len(literal) > 0 {
+		b.WriteString("?" + strings.Join(literal, "&"))
+		if len(vars) > 0 {
+			b.WriteString("{&" + strings.Join(vars, ",") + "}")
+		}
+	} else if len(vars) > 0 {
+		b.WriteString("{?" + strings.Join(vars, ",") + "}")
+	}
+
+	t.Template = b.String()
+	return t
+}
+
+// Assignments returns the variables as shell assignments, one per line.
+func (t *URITemplate) Assignments() string {
+	var b strings.Builder
+	for _, v := range t.Vars {
+		fmt.Fprintf(&b, "%s=%s\n", v.Name, bashEscape(v.Value))
+	}
+	return b.String()
+}
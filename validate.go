@@ -0,0 +1,58 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithScheme sets the scheme of requests whose URL has none, such as the
+// requests received by a server, their host being taken from the Host
+// header. From OutputV3 on, such requests are an error without it, as curl
+// cannot send them; Middleware rebuilds their URL from what the server
+// knows instead.
+func WithScheme(scheme string) Option {
+	return func(o *Options) {
+		scheme = strings.ToLower(strings.TrimSuffix(scheme, "://"))
+		if scheme == "" || strings.IndexFunc(scheme, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.')
+		}) >= 0 {
+			o.err = fmt.Errorf("http2curl: invalid scheme %q", scheme)
+			return
+		}
+		o.scheme = scheme
+	}
+}
+
+// checkURL completes the URL of req with the scheme set by WithScheme, and
+// from OutputV3 on returns an error telling how to fix URLs curl could not
+// send.
+func (o *Options) checkURL(req *http.Request) error {
+	if req.URL == nil {
+		return fmt.Errorf("http2curl: request has no URL")
+	}
+	if o.upstreamCompat {
+		// rebuilt from the Host header, as upstream does
+		return nil
+	}
+	if req.URL.Scheme == "" && o.scheme != "" {
+		u := *req.URL
+		u.Scheme = o.scheme
+		if u.Host == "" {
+			u.Host = req.Host
+		}
+		req.URL = &u
+		o.loss(LossRewritten, "url", "scheme set to "+o.scheme)
+	}
+	if o.outputVersion < OutputV3 {
+		// written as they are, as always
+		return nil
+	}
+	if req.URL.Scheme == "" {
+		return fmt.Errorf("http2curl: URL %q has no scheme; use WithScheme, or Middleware for the requests of a server", req.URL.String())
+	}
+	if req.URL.Host == "" && req.URL.Opaque == "" {
+		return fmt.Errorf("http2curl: URL %q has no host; set the Host of the request or of its URL", req.URL.String())
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http/httptest"
+)
+
+func ExampleWithScheme() {
+	// as received by a server
+	req := httptest.NewRequest("GET", "/items?page=2", nil)
+	req.Host = "api.example.com"
+
+	_, err := GetCurlCommandWithOptions(req, WithOutputVersion(OutputV3))
+	fmt.Println(err)
+
+	command, _ := Command(req, nil, WithScheme("https"))
+	fmt.Println(command)
+
+	// Output:
+	// http2curl: URL "/items?page=2" has no scheme; use WithScheme, or Middleware for the requests of a server
+	// curl -X 'GET' 'https://api.example.com/items?page=2'
+}
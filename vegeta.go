@@ -0,0 +1,94 @@
+package http2curl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetVegetaTarget returns req as a target of the HTTP format of Vegeta:
+// its request line, its headers and, when it has a body, a reference to
+// the file holding it. The body is given to writeBody, which stores it
+// and returns the path to refer to, see VegetaBodyDir. It is generated
+// from the same arguments as the curl command, so options apply alike;
+// placeholders are written as ${NAME}, for the targets to be expanded
+// before use, and curl flags, which are set on vegeta attack for all the
+// targets, are reported to the loss callback.
+func GetVegetaTarget(req *http.Request, writeBody func(body []byte) (path string, err error), opts ...Option) (string, error) {
+	o := newOptions(opts)
+	return o.vegetaTarget(req, writeBody)
+}
+
+// WriteVegetaTargets writes reqs as Vegeta targets separated by blank
+// lines, see GetVegetaTarget, for vegeta attack -targets. Requests that
+// fail are left out and reported in a *MultiError.
+func WriteVegetaTargets(w io.Writer, reqs []*http.Request, writeBody func(body []byte) (path string, err error), opts ...Option) error {
+	o := newOptions(opts)
+	var (
+		b    strings.Builder
+		errs MultiError
+	)
+	for i, req := range reqs {
+		target, err := o.vegetaTarget(req, writeBody)
+		if err != nil {
+			errs.add(i, err)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(target)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	return errs.errOrNil()
+}
+
+// VegetaBodyDir returns a writeBody function for GetVegetaTarget and
+// WriteVegetaTargets storing bodies in dir, named after their SHA-256 so
+// that requests with the same body share a file.
+func VegetaBodyDir(dir string) func(body []byte) (string, error) {
+	return func(body []byte) (string, error) {
+		sum := sha256.Sum256(body)
+		path := filepath.Join(dir, "body-"+hex.EncodeToString(sum[:8])+".bin")
+		if err := os.WriteFile(path, body, 0o600); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+}
+
+func (o *Options) vegetaTarget(req *http.Request, writeBody func(body []byte) (string, error)) (string, error) {
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+	r := o.checkRequest(args, "Vegeta", func(name string) string { return "${" + name + "}" })
+	if r.insecure {
+		o.loss(LossDropped, "flag -k", "set -insecure on vegeta attack")
+	}
+	if r.redirect {
+		o.loss(LossDropped, "flag -L", "set -redirects on vegeta attack")
+	}
+	var b strings.Builder
+	b.WriteString(r.method + " " + r.target + "\n")
+	for _, h := range r.headers {
+		b.WriteString(h[0] + ": " + h[1] + "\n")
+	}
+	switch {
+	case r.bodyFile != "":
+		b.WriteString("@" + r.bodyFile + "\n")
+	case r.hasBody:
+		path, err := writeBody([]byte(r.body))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("@" + path + "\n")
+	}
+	return b.String(), nil
+}
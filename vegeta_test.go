@@ -0,0 +1,35 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func ExampleWriteVegetaTargets() {
+	dir, _ := os.MkdirTemp("", "targets")
+	defer os.RemoveAll(dir)
+
+	list, _ := http.NewRequest("GET", "https://api.example.com/items?page=2", nil)
+	list.Header.Set("Accept", "application/json")
+	create, _ := http.NewRequest("POST", "https://api.example.com/items", strings.NewReader(`{"name":"gopher"}`))
+	create.Header.Set("Content-Type", "application/json")
+
+	var targets strings.Builder
+	WriteVegetaTargets(&targets, []*http.Request{list, create}, VegetaBodyDir(dir))
+	fmt.Print(strings.ReplaceAll(targets.String(), dir, "bodies"))
+
+	body, _ := os.ReadFile(filepath.Join(dir, "body-bcd2e8a1030d285e.bin"))
+	fmt.Println(string(body))
+
+	// Output:
+	// GET https://api.example.com/items?page=2
+	// Accept: application/json
+	//
+	// POST https://api.example.com/items
+	// Content-Type: application/json
+	// @bodies/body-bcd2e8a1030d285e.bin
+	// {"name":"gopher"}
+}
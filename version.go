@@ -0,0 +1,45 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Output versions accepted by WithOutputVersion.
+const (
+	// OutputV1 is the original output format. It is the default and
+	// will not change.
+	OutputV1 = 1
+	// OutputV2 lists headers before the body and leaves out -X when curl
+	// would use that method anyway: GET without body, POST with one.
+	OutputV2 = 2
+	// OutputV3 is OutputV2 writing header values that hold control
+	// characters with $'...' escapes for POSIX shells, noting bodies that
+	// were already read in a comment, rejecting URLs without a scheme or a
+	// host, see WithScheme, and methods which are not valid tokens, an
+	// empty method being sent as GET.
+	OutputV3 = 3
+
+	latestOutputVersion = OutputV3
+)
+
+// WithOutputVersion selects the output format. Changes to the order of
+// flags or to quoting ship as new versions, so commands compared against
+// golden strings stay stable until the version is bumped explicitly.
+func WithOutputVersion(version int) Option {
+	return func(o *Options) {
+		if version < OutputV1 || version > latestOutputVersion {
+			o.err = fmt.Errorf("http2curl: unknown output version %d", version)
+			return
+		}
+		o.outputVersion = version
+	}
+}
+
+// impliedMethod reports whether curl sends method without being told to.
+func impliedMethod(method string, hasBody bool) bool {
+	if hasBody {
+		return method == http.MethodPost
+	}
+	return method == http.MethodGet
+}
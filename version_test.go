@@ -0,0 +1,30 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleWithOutputVersion() {
+	req, _ := http.NewRequest("POST", "http://example.com/items", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	for _, version := range []int{OutputV1, OutputV2} {
+		command, _ := Command(req, nil, WithOutputVersion(version))
+		fmt.Println(command)
+	}
+
+	req, _ = http.NewRequest("PUT", "http://example.com/items/1", strings.NewReader(`{"a":2}`))
+	command, _ := Command(req, nil, WithOutputVersion(OutputV2))
+	fmt.Println(command)
+
+	_, err := Command(req, nil, WithOutputVersion(4))
+	fmt.Println(err)
+
+	// Output:
+	// curl -X 'POST' -d '{"a":1}' -H 'Content-Type: application/json' 'http://example.com/items'
+	// curl -H 'Content-Type: application/json' -d '{"a":1}' 'http://example.com/items'
+	// curl -X 'PUT' -d '{"a":2}' 'http://example.com/items/1'
+	// http2curl: unknown output version 4
+}
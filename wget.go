@@ -0,0 +1,135 @@
+package http2curl
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// wgetFlags maps the curl flags without a value to their wget equivalent,
+// empty when wget behaves so by default.
+var wgetFlags = map[string]string{
+	"-k":                  "--no-check-certificate",
+	"--insecure":          "--no-check-certificate",
+	"--compressed":        "--compression=auto",
+	"-s":                  "-q",
+	"-sS":                 "-nv",
+	"-v":                  "--server-response",
+	"-f":                  "",
+	"--fail":              "",
+	"--fail-with-body":    "--content-on-error",
+	"--retry-connrefused": "--retry-connrefused",
+}
+
+// GetWgetCommand returns a wget command sending the same request as the
+// curl command of req, for systems where only wget is available. The body
+// of the response is written to the standard output, as curl does. It is
+// generated from the same arguments as the curl command, so options apply
+// alike; curl flags without an equivalent are reported to the loss
+// callback and left out.
+func GetWgetCommand(req *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	args, err := buildArgs(req, o)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		wget    argList
+		method  string
+		body    argList
+		cookies []string
+		rest    argList
+		urlArgs argList
+	)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch a.kind {
+		case argComment, argPrompt:
+			wget = append(wget, a)
+			continue
+		case argProgram:
+			wget.add(argProgram, strings.TrimSuffix(a.value, "curl")+"wget")
+			wget.add(argFlag, "-O")
+			wget.add(argRaw, "-")
+			continue
+		case argURL:
+			if strings.HasPrefix(a.value, "-") {
+				// ends the options, as --url does for curl
+				urlArgs.add(argFlag, "--")
+			}
+			urlArgs.add(argURL, a.value)
+			continue
+		case argPipe, argTrailingComment:
+			// the commands curl is piped to are kept
+			rest = append(rest, args[i:]...)
+			i = len(args)
+			continue
+		case argFlag:
+		default:
+			continue
+		}
+
+		var value arg
+		if valueFlags[a.value] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case a.value == "-X":
+			method = value.value
+		case a.value == "--head":
+			method = http.MethodHead
+		case a.value == "-H":
+			rest.flag("--header", argHeader, value.value)
+		case a.value == "--cookie":
+			cookies = append(cookies, value.value)
+		case a.value == "--url":
+		case a.value == "--retry":
+			// wget counts the first attempt
+			if n, err := strconv.Atoi(value.value); err == nil {
+				rest.add(argFlag, "--tries="+strconv.Itoa(n+1))
+			}
+		case dataFlags[a.value] && value.kind == argValue && strings.HasPrefix(value.value, "@"):
+			body.flag("--body-file", argValue, value.value[1:])
+		case dataFlags[a.value]:
+			body.flag("--body-data", argBody, value.value)
+		default:
+			flag, ok := wgetFlags[a.value]
+			if !ok {
+				o.loss(LossDropped, "flag "+a.value, "not supported by wget")
+			} else if flag != "" {
+				rest.add(argFlag, flag)
+			}
+		}
+	}
+
+	if method == "" {
+		method = http.MethodGet
+		if len(body) > 0 {
+			method = http.MethodPost
+		}
+	}
+	// wget only sends a body along with --method
+	if method != http.MethodGet || len(body) > 0 {
+		wget.flag("--method", argMethod, method)
+	}
+	wget = append(wget, body...)
+	if len(cookies) > 0 {
+		wget.flag("--header", argHeader, "Cookie: "+strings.Join(cookies, "; "))
+	}
+	// headers and flags, then the URL, then what follows it
+	var tail argList
+	for i, a := range rest {
+		if a.kind == argPipe || a.kind == argTrailingComment {
+			tail = rest[i:]
+			rest = rest[:i]
+			break
+		}
+	}
+	wget = append(wget, rest...)
+	wget = append(wget, urlArgs...)
+	wget = append(wget, tail...)
+	command := o.render(wget)
+	return command.String(), nil
+}
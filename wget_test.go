@@ -0,0 +1,29 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func ExampleGetWgetCommand() {
+	req, _ := http.NewRequest("PUT", "https://example.com/items/1", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session=abc; theme=dark")
+
+	command, _ := GetWgetCommand(req,
+		WithCookieFlags(),
+		WithCompressedFlag(),
+		WithLossCallback(func(e LossEvent) { fmt.Println(e) }),
+	)
+	fmt.Println(command)
+
+	req, _ = http.NewRequest("GET", "https://example.com/items", nil)
+	command, _ = GetWgetCommand(req, ForScripting(), WithLossCallback(func(e LossEvent) { fmt.Println(e) }))
+	fmt.Println(command)
+
+	// Output:
+	// wget -O - --method 'PUT' --body-data '{"name":"gopher"}' --header 'Cookie: session=abc; theme=dark' --header 'Accept-Encoding: gzip' --header 'Content-Type: application/json' --compression=auto 'https://example.com/items/1'
+	// wget -O - --content-on-error -nv --tries=4 --retry-connrefused 'https://example.com/items'
+}
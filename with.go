@@ -0,0 +1,74 @@
+package http2curl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// flagNameRe matches the curl flag names accepted by With.
+var flagNameRe = regexp.MustCompile(`^(-[A-Za-z0-9#:]|--[A-Za-z0-9][A-Za-z0-9-]*)$`)
+
+// requestFlags are the curl flags changing the request sent, that With
+// rejects.
+var requestFlags = map[string]bool{
+	"-X": true, "--request": true, "-H": true, "--header": true,
+	"--url": true, "--url-query": true, "--request-target": true,
+	"-F": true, "--form": true, "--form-string": true, "--json": true,
+	"-G": true, "--get": true, "-I": true, "--head": true,
+	"-T": true, "--upload-file": true,
+	"-u": true, "--user": true, "--oauth2-bearer": true,
+	"-b": true, "--cookie": true, "-A": true, "--user-agent": true, "-e": true, "--referer": true,
+	"-K": true, "--config": true, "--variable": true,
+}
+
+// With returns a copy of c with extra flags added before its URL, each
+// value quoted for the shell, for instance:
+//
+//	command.With("--doh-url", "https://dns.example/dns-query", "--interface", "eth0")
+//
+// extra holds flags, each followed by its value when it takes one. Flags
+// are recognized by name; an unknown flag followed by a value, or a value
+// without a flag, is reported as an error. The flags changing the request,
+// such as -X, -H, -F, -u, the data flags and --url, are rejected too: they
+// belong to the request. Values are quoted for POSIX shells, see WithFor
+// for the others.
+func (c *CurlCommand) With(extra ...string) (*CurlCommand, error) {
+	return c.WithFor(posixShell{}, extra...)
+}
+
+// WithFor is like With for commands rendered for the shell s, which
+// quotes the values, for instance:
+//
+//	cmd, _ := http2curl.LookupShell("cmd")
+//	command.WithFor(cmd, "--interface", "eth0")
+func (c *CurlCommand) WithFor(s Shell, extra ...string) (*CurlCommand, error) {
+	var flags CurlCommand
+	noValue := "" // the previous flag, when it takes no value
+	for i := 0; i < len(extra); i++ {
+		flag := extra[i]
+		if !flagNameRe.MatchString(flag) {
+			if noValue != "" {
+				return nil, fmt.Errorf("http2curl: unexpected value %q after %s, which takes none", flag, noValue)
+			}
+			return nil, fmt.Errorf("http2curl: invalid flag %q", flag)
+		}
+		noValue = ""
+		switch {
+		case requestFlags[flag] || dataFlags[flag]:
+			return nil, fmt.Errorf("http2curl: flag %s cannot be added, it changes the request", flag)
+		case valueFlags[flag] || shortValueFlags[flag]:
+			if i+1 >= len(extra) {
+				return nil, fmt.Errorf("http2curl: missing value for %s", flag)
+			}
+			i++
+			flags = append(flags, flag, s.Quote(extra[i]))
+		default:
+			flags = append(flags, flag)
+			noValue = flag
+		}
+	}
+
+	p := c.Parts()
+	p.Flags = append(append(CurlCommand(nil), p.Flags...), flags...)
+	return p.Command(), nil
+}
@@ -0,0 +1,46 @@
+package http2curl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func ExampleCurlCommand_With() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	command, _ := Command(req, nil, WithHistoryComment())
+
+	extended, err := command.With("--doh-url", "https://dns.example/dns-query", "--interface", "eth0", "-v")
+	fmt.Println(extended, err)
+
+	_, err = command.With("--interface")
+	fmt.Println(err)
+	_, err = command.With("-v", "$(reboot)")
+	fmt.Println(err)
+	_, err = command.With("-H", "X-Debug: 1")
+	fmt.Println(err)
+	_, err = command.With("-u", "gopher:secret")
+	fmt.Println(err)
+
+	extended, err = command.With("--proxy-header", "X-Via: debug", "-E", "client.pem", "--capath", "/etc/ssl/certs")
+	fmt.Println(extended, err)
+
+	// Output:
+	// curl -X 'GET' --doh-url 'https://dns.example/dns-query' --interface 'eth0' -v 'http://example.com/' <nil>
+	// http2curl: missing value for --interface
+	// http2curl: unexpected value "$(reboot)" after -v, which takes none
+	// http2curl: flag -H cannot be added, it changes the request
+	// http2curl: flag -u cannot be added, it changes the request
+	// curl -X 'GET' --proxy-header 'X-Via: debug' -E 'client.pem' --capath '/etc/ssl/certs' 'http://example.com/' <nil>
+}
+
+func ExampleCurlCommand_WithFor() {
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	command, _ := Command(req, nil, WithCmdExe())
+
+	cmd, _ := LookupShell("cmd")
+	extended, err := command.WithFor(cmd, "--interface", "a b&c")
+	fmt.Println(extended, err)
+
+	// Output:
+	// curl -X "GET" --interface "a b&c" "http://example.com/" <nil>
+}
@@ -0,0 +1,105 @@
+// Package yamldef reads the request definitions and catalogs of http2curl
+// in YAML, which is a superset of the JSON read by http2curl itself. It is
+// a separate package so that the YAML decoder is only compiled into the
+// programs using it.
+package yamldef
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gdey/http2curl/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseDefinitions reads request definitions in YAML or JSON, see
+// http2curl.Definition. The input holds a single definition, a list of
+// definitions, or several YAML documents.
+func ParseDefinitions(r io.Reader) ([]http2curl.Definition, error) {
+	var defs []http2curl.Definition
+	err := decodeDocuments(r, func(node *yaml.Node) error {
+		var def http2curl.Definition
+		if err := node.Decode(&def); err != nil {
+			return err
+		}
+		defs = append(defs, def)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("http2curl: invalid definition: %v", err)
+	}
+	return defs, nil
+}
+
+// FromDefinition reads request definitions in YAML or JSON, see
+// ParseDefinitions, and returns the requests they describe, ready to be
+// given to http2curl.GetCurlCommands.
+func FromDefinition(r io.Reader) ([]*http.Request, error) {
+	defs, err := ParseDefinitions(r)
+	if err != nil {
+		return nil, err
+	}
+	reqs := make([]*http.Request, len(defs))
+	for i, def := range defs {
+		req, err := def.Request()
+		if err != nil {
+			return nil, fmt.Errorf("%v (definition %d)", err, i)
+		}
+		reqs[i] = req
+	}
+	return reqs, nil
+}
+
+// LoadCatalog reads the entries of a catalog in YAML or JSON: a list of
+// entries, or several YAML documents holding one entry each. In YAML, the
+// fields of the definition sit next to those of the entry:
+//
+//	name: users.create
+//	version: "2"
+//	description: Create a user
+//	tags: [users, write]
+//	method: POST
+//	url: https://api.example.com/users
+func LoadCatalog(r io.Reader) (*http2curl.Catalog, error) {
+	c := http2curl.NewCatalog()
+	err := decodeDocuments(r, func(node *yaml.Node) error {
+		var entry http2curl.CatalogEntry
+		if err := node.Decode(&entry); err != nil {
+			return fmt.Errorf("http2curl: invalid catalog entry: %v", err)
+		}
+		return c.Add(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// decodeDocuments calls decode for each item of r, a YAML or JSON input
+// holding a single item, a list of items or several YAML documents.
+func decodeDocuments(r io.Reader, decode func(*yaml.Node) error) error {
+	dec := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		items := doc.Content[:1]
+		if doc.Content[0].Kind == yaml.SequenceNode {
+			items = doc.Content[0].Content
+		}
+		for _, item := range items {
+			if err := decode(item); err != nil {
+				return err
+			}
+		}
+	}
+}
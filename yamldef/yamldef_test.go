@@ -0,0 +1,70 @@
+package yamldef_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdey/http2curl/v2"
+	"github.com/gdey/http2curl/v2/yamldef"
+)
+
+func ExampleFromDefinition() {
+	definitions := `
+- method: post
+  url: https://api.example.com/users
+  headers:
+    Content-Type: application/json
+  body: '{"name": "gopher"}'
+- url: https://api.example.com/users?page=2
+`
+	reqs, err := yamldef.FromDefinition(strings.NewReader(definitions))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	commands, _ := http2curl.GetCurlCommands(reqs)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+
+	_, err = yamldef.FromDefinition(strings.NewReader("method: PUT\n"))
+	fmt.Println(err)
+
+	// Output:
+	// curl -X 'POST' -d '{"name": "gopher"}' -H 'Content-Type: application/json' 'https://api.example.com/users'
+	// curl -X 'GET' 'https://api.example.com/users?page=2'
+	// http2curl: definition has no url (definition 0)
+}
+
+func ExampleLoadCatalog() {
+	catalog, err := yamldef.LoadCatalog(strings.NewReader(`
+name: users.create
+version: "1"
+method: POST
+url: https://api.example.com/users
+body: '{"name": "gopher"}'
+---
+name: users.create
+version: "2"
+tags: [users, write]
+method: POST
+url: https://api.example.com/v2/users
+headers:
+  Content-Type: application/json
+body: '{"name": "gopher", "role": "admin"}'
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, entry := range catalog.Search("tag:write") {
+		fmt.Println(entry.ID())
+	}
+	command, _ := catalog.Render("users.create@1", nil)
+	fmt.Println(command)
+
+	// Output:
+	// users.create@2
+	// curl -X 'POST' -d '{"name": "gopher"}' 'https://api.example.com/users'
+}